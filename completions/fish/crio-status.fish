@@ -2,7 +2,7 @@
 
 function __fish_crio-status_no_subcommand --description 'Test if there has been any subcommand yet'
     for i in (commandline -opc)
-        if contains -- $i complete completion help h man markdown md config c containers container cs s info i help h
+        if contains -- $i complete completion help h man markdown md config c containers container cs s info i pulls pull p registries registry r events event e reload help h
             return 1
         end
     end
@@ -25,10 +25,24 @@ complete -c crio-status -n '__fish_seen_subcommand_from markdown md' -f -l help
 complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'markdown md' -d 'Generate the markdown documentation.'
 complete -c crio-status -n '__fish_seen_subcommand_from config c' -f -l help -s h -d 'show help'
 complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'config c' -d 'Show the configuration of CRI-O as a TOML string.'
+complete -c crio-status -n '__fish_seen_subcommand_from config c' -f -l show-origin -d 'show the effective configuration options together with their origin'
 complete -c crio-status -n '__fish_seen_subcommand_from containers container cs s' -f -l help -s h -d 'show help'
 complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'containers container cs s' -d 'Display detailed information about the provided container ID.'
 complete -c crio-status -n '__fish_seen_subcommand_from containers container cs s' -f -l id -s i -r -d 'the container ID'
 complete -c crio-status -n '__fish_seen_subcommand_from info i' -f -l help -s h -d 'show help'
 complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'info i' -d 'Retrieve generic information about CRI-O, such as the cgroup and storage driver.'
+complete -c crio-status -n '__fish_seen_subcommand_from pulls pull p' -f -l help -s h -d 'show help'
+complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'pulls pull p' -d 'Display the image pulls in progress including their per layer progress.'
+complete -c crio-status -n '__fish_seen_subcommand_from pulls pull p' -f -l abort -s a -r -d 'abort the image pull with the provided ID for all waiting callers'
+complete -c crio-status -n '__fish_seen_subcommand_from registries registry r' -f -l help -s h -d 'show help'
+complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'registries registry r' -d 'Display the health of the registries used for image pulls.'
+complete -c crio-status -n '__fish_seen_subcommand_from events event e' -f -l help -s h -d 'show help'
+complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'events event e' -d 'Display the container events recorded in the journal.'
+complete -c crio-status -n '__fish_seen_subcommand_from events event e' -f -l seq -r -d 'only display the events having a greater sequence number'
+complete -c crio-status -n '__fish_seen_subcommand_from events event e' -f -l since -r -d 'only display the events created since the provided duration (e.g. 10m) or RFC 3339 timestamp'
+complete -c crio-status -n '__fish_seen_subcommand_from events event e' -f -l follow -s f -d 'keep displaying new events as they get recorded'
+complete -c crio-status -n '__fish_seen_subcommand_from reload' -f -l help -s h -d 'show help'
+complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'reload' -d 'Reload the configuration of CRI-O and display the applied changes.'
+complete -c crio-status -n '__fish_seen_subcommand_from reload' -f -l dry-run -s n -d 'only validate the configuration and display the changes without applying them'
 complete -c crio-status -n '__fish_seen_subcommand_from help h' -f -l help -s h -d 'show help'
 complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'help h' -d 'Shows a list of commands or help for one command'
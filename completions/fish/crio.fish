@@ -2,7 +2,7 @@
 
 function __fish_crio_no_subcommand --description 'Test if there has been any subcommand yet'
     for i in (commandline -opc)
-        if contains -- $i complete completion help h man markdown md config version wipe status config c containers container cs s info i help h
+        if contains -- $i complete completion help h man markdown md config migrate version wipe status config c containers container cs s info i pulls pull p registries registry r events event e reload help h
             return 1
         end
     end
@@ -14,6 +14,12 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l add-inheritable-capabiliti
 complete -c crio -n '__fish_crio_no_subcommand' -f -l additional-devices -r -d 'Devices to add to the containers.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l allowed-devices -r -d 'Devices a user is allowed to specify with the "io.kubernetes.cri-o.Devices" allowed annotation.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l apparmor-profile -r -d 'Name of the apparmor profile to be used as the runtime\'s default. This only takes effect if the user does not specify a profile via the Kubernetes Pod\'s metadata annotation.'
+complete -c crio -n '__fish_crio_no_subcommand' -l audit-log -r -d 'Destination of the audit records of exec, exec_sync, attach and port forward sessions, which is either an absolute file path or "journald". Set to an empty string to disable the audit log.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l audit-log-journald-identifier -r -d 'Syslog identifier of the audit records written to journald.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l audit-log-max-files -r -d 'Number of rotated audit log files to keep.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l audit-log-max-size -r -d 'Size in bytes after which the audit log file gets rotated. Set to 0 to disable the rotation.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l audit-log-namespaces -r -d 'List of pod namespaces to be audited. All pods are audited if empty.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l audit-log-operations -r -d 'List of audited operations, which can be "exec", "exec_sync", "attach" and "port_forward". All operations are audited if empty.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l big-files-temporary-dir -r -d 'Path to the temporary directory to use for storing big files, used to store image blobs and data streams related to containers image management.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l bind-mount-prefix -r -d 'A prefix to use for the source of the bind mounts. This option would be useful if you were running CRI-O in a container. And had \'/\' mounted on \'/host\' in your container. Then if you ran CRI-O with the \'--bind-mount-prefix=/host\' option, CRI-O would add /host to any bind mounts it is handed over CRI. If Kubernetes asked to have \'/var/lib/foobar\' bind mounted into the container, then CRI-O would bind mount \'/host/var/lib/foobar\'. Since CRI-O itself is running in a container with \'/\' or the host mounted on \'/host\', the container would end up with \'/var/lib/foobar\' from the host mounted in the container rather then \'/var/lib/foobar\' from the CRI-O container.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l blockio-config-file -r -d 'Path to the blockio class configuration file for configuring the cgroup blockio controller.'
@@ -39,6 +45,7 @@ complete -c crio -n '__fish_crio_no_subcommand' -l conmon -r -d 'Path to the con
 complete -c crio -n '__fish_crio_no_subcommand' -f -l conmon-cgroup -r -d 'cgroup to be used for conmon process. This option is deprecated and will be removed in the future.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l conmon-env -r -d 'Environment variable list for the conmon process, used for passing necessary environment variables to conmon or the runtime. This option is deprecated and will be removed in the future.'
 complete -c crio -n '__fish_crio_no_subcommand' -l container-attach-socket-dir -r -d 'Path to directory for container attach sockets.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l container-events-journal-size -r -d 'Maximum number of container events kept in the journal below the run root, which allows clients to replay missed events. Set to 0 to disable the journal.'
 complete -c crio -n '__fish_crio_no_subcommand' -l container-exits-dir -r -d 'Path to directory in which container exit files are written to by conmon.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l ctr-stop-timeout -r -d 'The minimal amount of time in seconds to wait before issuing a timeout regarding the proper termination of the container. The lowest possible value is 30s, whereas lower values are not considered by CRI-O.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l decryption-keys-path -r -d 'Path to load keys for image decryption.'
@@ -82,6 +89,11 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l hooks-dir -r -d 'Set the O
     Kubernetes configuration are considered. Bind mounts that CRI-O
     inserts by default (e.g. \'/dev/shm\') are not considered.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l hostnetwork-disable-selinux -d 'Determines whether SELinux should be disabled within a pod when it is running in the host network namespace.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l image-gc-high-threshold-percent -r -d 'Disk usage of the storage root in percent, which triggers the image garbage collection. The value 0 disables the disk usage based image garbage collection.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l image-gc-interval -r -d 'Interval in which the image garbage collection runs.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l image-gc-low-threshold-percent -r -d 'Disk usage of the storage root in percent, which the image garbage collection tries to reach once triggered.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l image-gc-max-age -r -d 'Maximum time an image can be unused before it gets removed by the image garbage collection. The value 0 disables the age based image garbage collection.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l image-gc-min-age -r -d 'Minimum time an image has to be unused before it can be removed by the image garbage collection.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l image-volumes -r -d 'Image volume handling (\'mkdir\', \'bind\', or \'ignore\')
     1. mkdir: A directory is created inside the container root filesystem for
        the volumes.
@@ -112,6 +124,9 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l log-format -r -d 'Set the
 complete -c crio -n '__fish_crio_no_subcommand' -f -l log-journald -d 'Log to systemd journal (journald) in addition to kubernetes log file.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l log-level -s l -r -d 'Log messages above specified level: trace, debug, info, warn, error, fatal or panic.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l log-size-max -r -d 'Maximum log size in bytes for a container. If it is positive, it must be >= 8192 to match/exceed conmon read buffer. This option is deprecated. The Kubelet flag \'--container-log-max-size\' should be used instead.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l max-concurrent-pulls -r -d 'Maximum number of image pulls running in parallel on the node. The value 0 disables the limit.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l max-concurrent-pulls-per-namespace -r -d 'Maximum number of image pulls running in parallel for a single Kubernetes namespace. The value 0 disables the limit.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l max-concurrent-pulls-per-registry -r -d 'Maximum number of image pulls running in parallel from a single registry host. The value 0 disables the limit.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l metrics-cert -r -d 'Certificate for the secure metrics endpoint.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l metrics-collectors -r -d 'Enabled metrics collectors.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l metrics-key -r -d 'Certificate key for the secure metrics endpoint.'
@@ -140,10 +155,13 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l profile-port -r -d 'Port f
 complete -c crio -n '__fish_crio_no_subcommand' -f -l rdt-config-file -r -d 'Path to the RDT configuration file for configuring the resctrl pseudo-filesystem.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l read-only -d 'Setup all unprivileged containers to run as read-only. Automatically mounts the containers\' tmpfs on \'/run\', \'/tmp\' and \'/var/tmp\'.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l registry -r -d 'Registry to be prepended when pulling unqualified images. Can be specified multiple times.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l registry-circuit-breaker-cooldown -r -d 'Time an unhealthy registry is skipped by image pulls.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l registry-failure-threshold -r -d 'Number of consecutive failed requests to a registry, after which it is skipped by image pulls for the registry-circuit-breaker-cooldown. The value 0 disables skipping unhealthy registries.'
 complete -c crio -n '__fish_crio_no_subcommand' -l root -s r -r -d 'The CRI-O root directory.'
 complete -c crio -n '__fish_crio_no_subcommand' -l runroot -r -d 'The CRI-O state directory.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l runtimes -r -d 'OCI runtimes, format is \'runtime_name:runtime_path:runtime_root:runtime_type:privileged_without_host_devices:runtime_config_path\'.'
 complete -c crio -n '__fish_crio_no_subcommand' -l seccomp-profile -r -d 'Path to the seccomp.json profile to be used as the runtime\'s default. If not specified, then the internal default seccomp profile will be used.'
+complete -c crio -n '__fish_crio_no_subcommand' -l seccomp-profile-record-dir -r -d 'Directory where the seccomp profiles recorded by the seccomp notifier action "record" get written to on container exit.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l seccomp-use-default-when-empty -d 'Use the default seccomp profile when an empty one is specified. This option is currently deprecated, and will be replaced by the SeccompDefault FeatureGate in Kubernetes.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l selinux -d 'Enable selinux support.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l separate-pull-cgroup -r -d '[EXPERIMENTAL] Pull in new cgroup.'
@@ -196,7 +214,16 @@ complete -c crio -n '__fish_seen_subcommand_from config' -f -l migrate-defaults
     Please note that the migration will overwrite any fields that have changed
     defaults between versions. To save a custom configuration change, it should
     be in a drop-in configuration file instead.
-    Possible values: "1.17"'
+    Possible values: all versions starting from "1.17"'
+complete -c crio -n '__fish_seen_subcommand_from config' -f -l show-origin -d 'Output the effective configuration options together with their origin, which is either the default, a configuration file, a drop-in file, a command line flag or an environment variable.'
+complete -c crio -n '__fish_seen_subcommand_from migrate' -f -l help -s h -d 'show help'
+complete -r -c crio -n '__fish_seen_subcommand_from config' -a 'migrate' -d 'Migrate the configuration file selected via the global \'--config,-c\' command
+line argument from a previous CRI-O version to the current one. Every changed,
+renamed and removed option is printed to stdout. The configuration file itself
+is not modified, but the migrated options can be written into a drop-in
+configuration file.'
+complete -c crio -n '__fish_seen_subcommand_from migrate' -f -l from -s f -r -d 'The CRI-O version the configuration file has been used with, supported are all versions starting from 1.17.'
+complete -c crio -n '__fish_seen_subcommand_from migrate' -l drop-in -r -d 'Write the migrated options into the provided drop-in configuration file, which should be sorted last in the configuration directory.'
 complete -c crio -n '__fish_seen_subcommand_from version' -f -l help -s h -d 'show help'
 complete -r -c crio -n '__fish_crio_no_subcommand' -a 'version' -d 'display detailed version information'
 complete -c crio -n '__fish_seen_subcommand_from version' -f -l json -s j -d 'print JSON instead of text'
@@ -208,10 +235,24 @@ complete -r -c crio -n '__fish_crio_no_subcommand' -a 'status' -d 'Display statu
 complete -c crio -n '__fish_seen_subcommand_from status' -l socket -s s -r -d 'absolute path to the unix socket'
 complete -c crio -n '__fish_seen_subcommand_from config c' -f -l help -s h -d 'show help'
 complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'config c' -d 'Show the configuration of CRI-O as a TOML string.'
+complete -c crio -n '__fish_seen_subcommand_from config c' -f -l show-origin -d 'show the effective configuration options together with their origin'
 complete -c crio -n '__fish_seen_subcommand_from containers container cs s' -f -l help -s h -d 'show help'
 complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'containers container cs s' -d 'Display detailed information about the provided container ID.'
 complete -c crio -n '__fish_seen_subcommand_from containers container cs s' -f -l id -s i -r -d 'the container ID'
 complete -c crio -n '__fish_seen_subcommand_from info i' -f -l help -s h -d 'show help'
 complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'info i' -d 'Retrieve generic information about CRI-O, such as the cgroup and storage driver.'
+complete -c crio -n '__fish_seen_subcommand_from pulls pull p' -f -l help -s h -d 'show help'
+complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'pulls pull p' -d 'Display the image pulls in progress including their per layer progress.'
+complete -c crio -n '__fish_seen_subcommand_from pulls pull p' -f -l abort -s a -r -d 'abort the image pull with the provided ID for all waiting callers'
+complete -c crio -n '__fish_seen_subcommand_from registries registry r' -f -l help -s h -d 'show help'
+complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'registries registry r' -d 'Display the health of the registries used for image pulls.'
+complete -c crio -n '__fish_seen_subcommand_from events event e' -f -l help -s h -d 'show help'
+complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'events event e' -d 'Display the container events recorded in the journal.'
+complete -c crio -n '__fish_seen_subcommand_from events event e' -f -l seq -r -d 'only display the events having a greater sequence number'
+complete -c crio -n '__fish_seen_subcommand_from events event e' -f -l since -r -d 'only display the events created since the provided duration (e.g. 10m) or RFC 3339 timestamp'
+complete -c crio -n '__fish_seen_subcommand_from events event e' -f -l follow -s f -d 'keep displaying new events as they get recorded'
+complete -c crio -n '__fish_seen_subcommand_from reload' -f -l help -s h -d 'show help'
+complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'reload' -d 'Reload the configuration of CRI-O and display the applied changes.'
+complete -c crio -n '__fish_seen_subcommand_from reload' -f -l dry-run -s n -d 'only validate the configuration and display the changes without applying them'
 complete -c crio -n '__fish_seen_subcommand_from help h' -f -l help -s h -d 'show help'
 complete -r -c crio -n '__fish_crio_no_subcommand' -a 'help h' -d 'Shows a list of commands or help for one command'
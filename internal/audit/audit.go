@@ -0,0 +1,215 @@
+// Package audit records exec, attach and port forward sessions as structured
+// JSON records.
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Operation is an audited container operation.
+type Operation string
+
+const (
+	// OperationExec is an interactive exec session.
+	OperationExec Operation = "exec"
+	// OperationExecSync is a synchronous exec, for example used by probes.
+	OperationExecSync Operation = "exec_sync"
+	// OperationAttach is an attach session.
+	OperationAttach Operation = "attach"
+	// OperationPortForward is a port forward session.
+	OperationPortForward Operation = "port_forward"
+)
+
+// Operations are all audited operations.
+var Operations = []Operation{OperationExec, OperationExecSync, OperationAttach, OperationPortForward}
+
+// DestinationJournald is the destination for writing the records to journald.
+const DestinationJournald = "journald"
+
+// Record is a single audit record.
+type Record struct {
+	Timestamp     time.Time `json:"timestamp"`
+	Operation     Operation `json:"operation"`
+	ContainerID   string    `json:"container_id,omitempty"`
+	ContainerName string    `json:"container_name,omitempty"`
+	PodID         string    `json:"pod_id,omitempty"`
+	PodName       string    `json:"pod_name,omitempty"`
+	PodNamespace  string    `json:"pod_namespace,omitempty"`
+	PodUID        string    `json:"pod_uid,omitempty"`
+	Command       []string  `json:"command,omitempty"`
+	TTY           bool      `json:"tty"`
+	Stdin         bool      `json:"stdin"`
+	Port          int32     `json:"port,omitempty"`
+	Protocol      string    `json:"protocol,omitempty"`
+	// Duration of the session in nanoseconds.
+	Duration time.Duration `json:"duration_ns"`
+	// ExitCode of the command, if known.
+	ExitCode *int32 `json:"exit_code,omitempty"`
+	// BytesIn are the bytes transferred from the client to the container.
+	BytesIn int64 `json:"bytes_in"`
+	// BytesOut are the bytes transferred from the container to the client.
+	BytesOut int64  `json:"bytes_out"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Config is the configuration of the audit log.
+type Config struct {
+	// Destination is either the absolute path of the audit log file,
+	// DestinationJournald or empty to disable the audit log.
+	Destination string
+	// JournaldIdentifier is the syslog identifier of the journald records.
+	JournaldIdentifier string
+	// MaxSize is the size in bytes after which the audit log file gets
+	// rotated. A value of zero disables the rotation.
+	MaxSize int64
+	// MaxFiles is the number of rotated audit log files to keep.
+	MaxFiles int
+	// Operations to be audited. All operations are audited if empty.
+	Operations []string
+	// Namespaces of the pods to be audited. All pods are audited if empty.
+	Namespaces []string
+}
+
+// Validate verifies the audit log configuration.
+func (c *Config) Validate() error {
+	if c.Destination != "" && c.Destination != DestinationJournald && !filepath.IsAbs(c.Destination) {
+		return fmt.Errorf("audit log %q must be an absolute path or %q", c.Destination, DestinationJournald)
+	}
+	if c.Destination == DestinationJournald && c.JournaldIdentifier == "" {
+		return errors.New("audit log journald identifier must not be empty")
+	}
+	if c.MaxSize < 0 {
+		return fmt.Errorf("audit log max size %d must not be negative", c.MaxSize)
+	}
+	if c.MaxFiles < 0 {
+		return fmt.Errorf("audit log max files %d must not be negative", c.MaxFiles)
+	}
+	for _, op := range c.Operations {
+		if !isOperation(op) {
+			return fmt.Errorf("invalid audit log operation %q, must be one of %v", op, Operations)
+		}
+	}
+	return nil
+}
+
+func isOperation(op string) bool {
+	for _, known := range Operations {
+		if op == string(known) {
+			return true
+		}
+	}
+	return false
+}
+
+// sink is the destination of the encoded records.
+type sink interface {
+	Write(record *Record, line []byte) error
+	Close() error
+}
+
+// Logger writes the audit records. A nil logger discards all records.
+type Logger struct {
+	operations map[Operation]bool
+	namespaces map[string]bool
+
+	mutex sync.Mutex
+	sink  sink
+}
+
+// New creates a new audit logger. It returns nil if the audit log is disabled.
+func New(config *Config) (*Logger, error) {
+	if config.Destination == "" {
+		return nil, nil
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		s   sink
+		err error
+	)
+	if config.Destination == DestinationJournald {
+		s, err = newJournaldSink(config.JournaldIdentifier)
+	} else {
+		s, err = newFileSink(config.Destination, config.MaxSize, config.MaxFiles)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Logger{sink: s}
+	if len(config.Operations) > 0 {
+		l.operations = make(map[Operation]bool)
+		for _, op := range config.Operations {
+			l.operations[Operation(op)] = true
+		}
+	}
+	if len(config.Namespaces) > 0 {
+		l.namespaces = make(map[string]bool)
+		for _, namespace := range config.Namespaces {
+			l.namespaces[namespace] = true
+		}
+	}
+	return l, nil
+}
+
+// Enabled returns true if the operation in the pod namespace gets audited.
+func (l *Logger) Enabled(op Operation, namespace string) bool {
+	if l == nil {
+		return false
+	}
+	if l.operations != nil && !l.operations[op] {
+		return false
+	}
+	if l.namespaces != nil && !l.namespaces[namespace] {
+		return false
+	}
+	return true
+}
+
+// Log writes the record if its operation and namespace get audited. Failures
+// are logged, because they must not fail the audited operation.
+func (l *Logger) Log(record *Record) {
+	if !l.Enabled(record.Operation, record.PodNamespace) {
+		return
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		logrus.Errorf("Unable to encode audit record: %v", err)
+		return
+	}
+	line = append(line, '\n')
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.sink == nil {
+		return
+	}
+	if err := l.sink.Write(record, line); err != nil {
+		logrus.Errorf("Unable to write audit record: %v", err)
+	}
+}
+
+// Close closes the audit log.
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.sink == nil {
+		return nil
+	}
+	err := l.sink.Close()
+	l.sink = nil
+	return err
+}
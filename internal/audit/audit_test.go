@@ -0,0 +1,236 @@
+package audit_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cri-o/cri-o/internal/audit"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// The actual test suite
+var _ = t.Describe("Audit", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(t.MustTempDir("audit"), "audit.log")
+	})
+
+	readRecords := func(file string) []audit.Record {
+		content, err := os.ReadFile(file)
+		Expect(err).To(BeNil())
+		records := []audit.Record{}
+		for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+			if line == "" {
+				continue
+			}
+			var record audit.Record
+			Expect(json.Unmarshal([]byte(line), &record)).To(BeNil())
+			records = append(records, record)
+		}
+		return records
+	}
+
+	t.Describe("Validate", func() {
+		It("should succeed with the default config", func() {
+			// Given
+			sut := &audit.Config{}
+
+			// When
+			err := sut.Validate()
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail with a relative path", func() {
+			// Given
+			sut := &audit.Config{Destination: "audit.log"}
+
+			// When
+			err := sut.Validate()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with an empty journald identifier", func() {
+			// Given
+			sut := &audit.Config{Destination: audit.DestinationJournald}
+
+			// When
+			err := sut.Validate()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with a negative max size", func() {
+			// Given
+			sut := &audit.Config{Destination: path, MaxSize: -1}
+
+			// When
+			err := sut.Validate()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with an unknown operation", func() {
+			// Given
+			sut := &audit.Config{Destination: path, Operations: []string{"logs"}}
+
+			// When
+			err := sut.Validate()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	t.Describe("Logger", func() {
+		It("should be nil if disabled", func() {
+			// Given
+			// When
+			sut, err := audit.New(&audit.Config{})
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut).To(BeNil())
+			Expect(sut.Enabled(audit.OperationExec, "default")).To(BeFalse())
+			sut.Log(&audit.Record{Operation: audit.OperationExec})
+			Expect(sut.Close()).To(BeNil())
+		})
+
+		It("should write the records as JSON lines", func() {
+			// Given
+			sut, err := audit.New(&audit.Config{Destination: path})
+			Expect(err).To(BeNil())
+			exitCode := int32(3)
+
+			// When
+			sut.Log(&audit.Record{
+				Operation:    audit.OperationExec,
+				ContainerID:  "ctr",
+				PodNamespace: "default",
+				Command:      []string{"sh", "-c", "exit 3"},
+				TTY:          true,
+				ExitCode:     &exitCode,
+				BytesOut:     10,
+			})
+			sut.Log(&audit.Record{Operation: audit.OperationAttach})
+			Expect(sut.Close()).To(BeNil())
+
+			// Then
+			records := readRecords(path)
+			Expect(records).To(HaveLen(2))
+			Expect(records[0].Operation).To(Equal(audit.OperationExec))
+			Expect(records[0].ContainerID).To(Equal("ctr"))
+			Expect(records[0].Command).To(Equal([]string{"sh", "-c", "exit 3"}))
+			Expect(records[0].TTY).To(BeTrue())
+			Expect(*records[0].ExitCode).To(Equal(exitCode))
+			Expect(records[0].BytesOut).To(Equal(int64(10)))
+			Expect(records[1].Operation).To(Equal(audit.OperationAttach))
+			Expect(records[1].ExitCode).To(BeNil())
+		})
+
+		It("should filter operations and namespaces", func() {
+			// Given
+			sut, err := audit.New(&audit.Config{
+				Destination: path,
+				Operations:  []string{string(audit.OperationExec)},
+				Namespaces:  []string{"audited"},
+			})
+			Expect(err).To(BeNil())
+
+			// When
+			sut.Log(&audit.Record{Operation: audit.OperationExec, PodNamespace: "audited", ContainerID: "1"})
+			sut.Log(&audit.Record{Operation: audit.OperationExec, PodNamespace: "other", ContainerID: "2"})
+			sut.Log(&audit.Record{Operation: audit.OperationAttach, PodNamespace: "audited", ContainerID: "3"})
+			Expect(sut.Close()).To(BeNil())
+
+			// Then
+			records := readRecords(path)
+			Expect(records).To(HaveLen(1))
+			Expect(records[0].ContainerID).To(Equal("1"))
+		})
+
+		It("should rotate the log file", func() {
+			// Given
+			sut, err := audit.New(&audit.Config{Destination: path, MaxSize: 1, MaxFiles: 2})
+			Expect(err).To(BeNil())
+
+			// When
+			for _, id := range []string{"1", "2", "3", "4"} {
+				sut.Log(&audit.Record{Operation: audit.OperationExec, ContainerID: id})
+			}
+			Expect(sut.Close()).To(BeNil())
+
+			// Then
+			Expect(readRecords(path)[0].ContainerID).To(Equal("4"))
+			Expect(readRecords(path + ".1")[0].ContainerID).To(Equal("3"))
+			Expect(readRecords(path + ".2")[0].ContainerID).To(Equal("2"))
+			Expect(path + ".3").NotTo(BeAnExistingFile())
+		})
+
+		It("should drop the log file without rotated files", func() {
+			// Given
+			sut, err := audit.New(&audit.Config{Destination: path, MaxSize: 1})
+			Expect(err).To(BeNil())
+
+			// When
+			sut.Log(&audit.Record{Operation: audit.OperationExec, ContainerID: "1"})
+			sut.Log(&audit.Record{Operation: audit.OperationExec, ContainerID: "2"})
+			Expect(sut.Close()).To(BeNil())
+
+			// Then
+			records := readRecords(path)
+			Expect(records).To(HaveLen(1))
+			Expect(records[0].ContainerID).To(Equal("2"))
+			Expect(path + ".1").NotTo(BeAnExistingFile())
+		})
+	})
+
+	t.Describe("Counter", func() {
+		It("should count the bytes of the streams", func() {
+			// Given
+			var sut audit.Counter
+			out := &bytes.Buffer{}
+			reader := sut.Reader(strings.NewReader("input"))
+			writer := sut.WriteCloser(nopWriteCloser{out})
+
+			// When
+			_, err := io.Copy(writer, reader)
+			Expect(err).To(BeNil())
+			sut.Add(1, 2)
+
+			// Then
+			Expect(out.String()).To(Equal("input"))
+			Expect(sut.BytesIn()).To(Equal(int64(6)))
+			Expect(sut.BytesOut()).To(Equal(int64(7)))
+		})
+
+		It("should keep nil streams", func() {
+			// Given
+			var sut audit.Counter
+
+			// When
+			// Then
+			Expect(sut.Reader(nil)).To(BeNil())
+			Expect(sut.WriteCloser(nil)).To(BeNil())
+		})
+	})
+})
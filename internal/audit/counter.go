@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// Counter counts the bytes transferred through the wrapped streams of a
+// session.
+type Counter struct {
+	in  atomic.Int64
+	out atomic.Int64
+}
+
+// BytesIn returns the bytes transferred from the client to the container.
+func (c *Counter) BytesIn() int64 {
+	return c.in.Load()
+}
+
+// BytesOut returns the bytes transferred from the container to the client.
+func (c *Counter) BytesOut() int64 {
+	return c.out.Load()
+}
+
+// Add adds the bytes transferred without using the wrapped streams.
+func (c *Counter) Add(in, out int64) {
+	c.in.Add(in)
+	c.out.Add(out)
+}
+
+// Reader wraps the input stream of the client. A nil reader is kept as it is.
+func (c *Counter) Reader(r io.Reader) io.Reader {
+	if r == nil {
+		return nil
+	}
+	return &countingReader{Reader: r, count: &c.in}
+}
+
+// WriteCloser wraps an output stream to the client. A nil writer is kept as
+// it is.
+func (c *Counter) WriteCloser(w io.WriteCloser) io.WriteCloser {
+	if w == nil {
+		return nil
+	}
+	return &countingWriteCloser{WriteCloser: w, count: &c.out}
+}
+
+// ReadWriteCloser wraps a bidirectional stream of the client.
+func (c *Counter) ReadWriteCloser(rwc io.ReadWriteCloser) io.ReadWriteCloser {
+	return &countingReadWriteCloser{
+		ReadWriteCloser: rwc,
+		in:              &c.in,
+		out:             &c.out,
+	}
+}
+
+type countingReader struct {
+	io.Reader
+	count *atomic.Int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.count.Add(int64(n))
+	return n, err
+}
+
+type countingWriteCloser struct {
+	io.WriteCloser
+	count *atomic.Int64
+}
+
+func (w *countingWriteCloser) Write(p []byte) (int, error) {
+	n, err := w.WriteCloser.Write(p)
+	w.count.Add(int64(n))
+	return n, err
+}
+
+type countingReadWriteCloser struct {
+	io.ReadWriteCloser
+	in  *atomic.Int64
+	out *atomic.Int64
+}
+
+func (rwc *countingReadWriteCloser) Read(p []byte) (int, error) {
+	n, err := rwc.ReadWriteCloser.Read(p)
+	rwc.in.Add(int64(n))
+	return n, err
+}
+
+func (rwc *countingReadWriteCloser) Write(p []byte) (int, error) {
+	n, err := rwc.ReadWriteCloser.Write(p)
+	rwc.out.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,124 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/journal"
+)
+
+// fileSink writes the records to a file, which gets rotated after reaching
+// the maximum size. The rotated files get the suffixes .1 to .maxFiles, where
+// .1 is the most recent one.
+type fileSink struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	file *os.File
+	size int64
+}
+
+func newFileSink(path string, maxSize int64, maxFiles int) (*fileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("create audit log directory: %w", err)
+	}
+	s := &fileSink{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("stat audit log: %w", err)
+	}
+	s.file = file
+	s.size = info.Size()
+	return nil
+}
+
+func (s *fileSink) Write(_ *Record, line []byte) error {
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return fmt.Errorf("rotate audit log: %w", err)
+		}
+	}
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	return err
+}
+
+// rotate shifts the rotated files by one, moves the current file to the first
+// rotated one and reopens the file.
+func (s *fileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	if s.maxFiles == 0 {
+		if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return s.open()
+	}
+
+	for i := s.maxFiles - 1; i >= 1; i-- {
+		if err := os.Rename(s.rotatedPath(i), s.rotatedPath(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if err := os.Rename(s.path, s.rotatedPath(1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return s.open()
+}
+
+func (s *fileSink) rotatedPath(i int) string {
+	return s.path + "." + strconv.Itoa(i)
+}
+
+func (s *fileSink) Close() error {
+	return s.file.Close()
+}
+
+// journaldSink sends the records to journald, where the message is the JSON
+// encoded record. The identity of the container is added as journal fields.
+type journaldSink struct {
+	identifier string
+}
+
+func newJournaldSink(identifier string) (*journaldSink, error) {
+	if !journal.Enabled() {
+		return nil, errors.New("journald is not available")
+	}
+	return &journaldSink{identifier: identifier}, nil
+}
+
+func (s *journaldSink) Write(record *Record, line []byte) error {
+	vars := map[string]string{
+		"SYSLOG_IDENTIFIER":         s.identifier,
+		"CRIO_AUDIT_OPERATION":      string(record.Operation),
+		"CRIO_AUDIT_CONTAINER_ID":   record.ContainerID,
+		"CRIO_AUDIT_CONTAINER_NAME": record.ContainerName,
+		"CRIO_AUDIT_POD_ID":         record.PodID,
+		"CRIO_AUDIT_POD_NAME":       record.PodName,
+		"CRIO_AUDIT_POD_NAMESPACE":  record.PodNamespace,
+		"CRIO_AUDIT_POD_UID":        record.PodUID,
+	}
+	return journal.Send(strings.TrimSuffix(string(line), "\n"), journal.PriInfo, vars)
+}
+
+func (s *journaldSink) Close() error {
+	return nil
+}
@@ -0,0 +1,173 @@
+package bandwidth
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// DefaultInterface is the name of the pod network interface, if the CNI result
+// does not contain it.
+const DefaultInterface = "eth0"
+
+// latency is the maximum time a packet can wait in the egress queue, which is
+// the same as used by the CNI bandwidth plugin.
+const latency = 25 * time.Millisecond
+
+// Limits are the bandwidth limits of a pod, which are applied by CRI-O on the
+// pod network interface if the CNI network does not support the bandwidth
+// capability.
+type Limits struct {
+	// Interface is the name of the pod network interface inside of the pod
+	// network namespace.
+	Interface string `json:"interface"`
+
+	// IngressRate and IngressBurst limit the traffic received by the pod. The
+	// rate is in bits per second and the burst in bits.
+	IngressRate  uint64 `json:"ingressRate,omitempty"`
+	IngressBurst uint64 `json:"ingressBurst,omitempty"`
+
+	// EgressRate and EgressBurst limit the traffic sent by the pod. The rate
+	// is in bits per second and the burst in bits.
+	EgressRate  uint64 `json:"egressRate,omitempty"`
+	EgressBurst uint64 `json:"egressBurst,omitempty"`
+}
+
+// Apply applies the limits on the pod network interface inside of the network
+// namespace at netNsPath. The egress traffic is shaped by a token bucket
+// filter root qdisc, while the ingress traffic gets policed. Applying limits
+// again replaces the previously applied ones.
+func Apply(netNsPath string, limits *Limits) error {
+	return ns.WithNetNSPath(netNsPath, func(ns.NetNS) error {
+		link, err := netlink.LinkByName(limits.Interface)
+		if err != nil {
+			return fmt.Errorf("get pod interface %s: %w", limits.Interface, err)
+		}
+
+		if limits.EgressRate > 0 {
+			if err := netlink.QdiscReplace(egressQdisc(link, limits)); err != nil {
+				return fmt.Errorf("set egress bandwidth limit: %w", err)
+			}
+		}
+
+		if limits.IngressRate > 0 {
+			if err := netlink.QdiscReplace(ingressQdisc(link)); err != nil {
+				return fmt.Errorf("add ingress qdisc: %w", err)
+			}
+			if err := netlink.FilterReplace(ingressFilter(link, limits)); err != nil {
+				return fmt.Errorf("set ingress bandwidth limit: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Remove removes the limits from the pod network interface inside of the
+// network namespace at netNsPath. Already removed network namespaces,
+// interfaces or qdiscs are ignored.
+func Remove(netNsPath string, limits *Limits) error {
+	err := ns.WithNetNSPath(netNsPath, func(ns.NetNS) error {
+		link, err := netlink.LinkByName(limits.Interface)
+		if err != nil {
+			var notFoundErr netlink.LinkNotFoundError
+			if errors.As(err, &notFoundErr) {
+				return nil
+			}
+			return fmt.Errorf("get pod interface %s: %w", limits.Interface, err)
+		}
+
+		if limits.EgressRate > 0 {
+			if err := qdiscDel(egressQdisc(link, limits)); err != nil {
+				return fmt.Errorf("remove egress bandwidth limit: %w", err)
+			}
+		}
+
+		if limits.IngressRate > 0 {
+			// Removing the ingress qdisc removes its filters as well.
+			if err := qdiscDel(ingressQdisc(link)); err != nil {
+				return fmt.Errorf("remove ingress bandwidth limit: %w", err)
+			}
+		}
+
+		return nil
+	})
+
+	var notExistErr ns.NSPathNotExistErr
+	if errors.As(err, &notExistErr) {
+		return nil
+	}
+	return err
+}
+
+func qdiscDel(qdisc netlink.Qdisc) error {
+	if err := netlink.QdiscDel(qdisc); err != nil && !errors.Is(err, unix.ENOENT) && !errors.Is(err, unix.EINVAL) {
+		return err
+	}
+	return nil
+}
+
+// egressQdisc returns the token bucket filter root qdisc of the link.
+func egressQdisc(link netlink.Link, limits *Limits) *netlink.Tbf {
+	rate := limits.EgressRate / 8
+	burst := burstBytes(rate, limits.EgressBurst)
+	return &netlink.Tbf{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(1, 0),
+			Parent:    netlink.HANDLE_ROOT,
+		},
+		Rate:   rate,
+		Limit:  saturateUint32(float64(rate)*latency.Seconds() + float64(burst)),
+		Buffer: netlink.Xmittime(rate, burst),
+	}
+}
+
+// ingressQdisc returns the ingress qdisc of the link.
+func ingressQdisc(link netlink.Link) *netlink.Ingress {
+	return &netlink.Ingress{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(0xffff, 0),
+			Parent:    netlink.HANDLE_INGRESS,
+		},
+	}
+}
+
+// ingressFilter returns the filter of the ingress qdisc, which drops all
+// packets exceeding the limit.
+func ingressFilter(link netlink.Link, limits *Limits) *netlink.U32 {
+	rate := limits.IngressRate / 8
+	police := netlink.NewPoliceAction()
+	police.Rate = saturateUint32(float64(rate))
+	police.Burst = burstBytes(rate, limits.IngressBurst)
+	police.ExceedAction = netlink.TC_POLICE_SHOT
+
+	// A filter without selector matches all packets.
+	return &netlink.U32{
+		FilterAttrs: netlink.FilterAttrs{
+			LinkIndex: link.Attrs().Index,
+			Parent:    netlink.MakeHandle(0xffff, 0),
+			Priority:  1,
+			Protocol:  unix.ETH_P_ALL,
+		},
+		Actions: []netlink.Action{police},
+	}
+}
+
+// burstBytes converts the burst in bits to bytes. The burst is limited to the
+// largest one the kernel is able to represent as transmission time of the
+// rate in bytes per second.
+func burstBytes(rate, burst uint64) uint32 {
+	maxBurst := float64(rate) * float64(math.MaxUint32) / netlink.TickInUsec() / netlink.TIME_UNITS_PER_SEC
+	return saturateUint32(math.Min(float64(burst/8), maxBurst))
+}
+
+func saturateUint32(value float64) uint32 {
+	return uint32(math.Min(value, math.MaxUint32))
+}
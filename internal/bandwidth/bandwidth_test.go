@@ -0,0 +1,201 @@
+package bandwidth_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+
+	"github.com/cri-o/cri-o/internal/bandwidth"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+)
+
+// The actual test suite
+var _ = t.Describe("Bandwidth", func() {
+	const netNsDir = "/var/run/netns"
+	var (
+		netNsName string
+		netNsPath string
+		limits    *bandwidth.Limits
+	)
+
+	// inNetNs runs the function inside of the test network namespace.
+	inNetNs := func(f func()) {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		origin, err := netns.Get()
+		Expect(err).To(BeNil())
+		defer origin.Close()
+		handle, err := netns.GetFromPath(netNsPath)
+		Expect(err).To(BeNil())
+		defer handle.Close()
+		Expect(netns.Set(handle)).To(BeNil())
+		defer func() { Expect(netns.Set(origin)).To(BeNil()) }()
+		f()
+	}
+
+	qdiscs := func() (qdiscs []netlink.Qdisc) {
+		inNetNs(func() {
+			link, err := netlink.LinkByName(bandwidth.DefaultInterface)
+			Expect(err).To(BeNil())
+			qdiscs, err = netlink.QdiscList(link)
+			Expect(err).To(BeNil())
+		})
+		return qdiscs
+	}
+
+	// skipWithoutPolicing skips the test if the kernel does not support the
+	// ingress policing, for example if the modules are not available.
+	skipWithoutPolicing := func() {
+		ingressOnly := &bandwidth.Limits{
+			Interface:    limits.Interface,
+			IngressRate:  limits.IngressRate,
+			IngressBurst: limits.IngressBurst,
+		}
+		if err := bandwidth.Apply(netNsPath, ingressOnly); err != nil {
+			Skip("ingress policing not supported: " + err.Error())
+		}
+		Expect(bandwidth.Remove(netNsPath, ingressOnly)).To(BeNil())
+	}
+
+	BeforeEach(func() {
+		netNsName = fmt.Sprintf("crio-bandwidth-test-%d", GinkgoRandomSeed())
+		netNsPath = filepath.Join(netNsDir, netNsName)
+
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		origin, err := netns.Get()
+		Expect(err).To(BeNil())
+		defer origin.Close()
+		handle, err := netns.NewNamed(netNsName)
+		if err != nil {
+			Skip("network namespaces not supported: " + err.Error())
+		}
+		defer handle.Close()
+		defer func() { Expect(netns.Set(origin)).To(BeNil()) }()
+
+		veth := &netlink.Veth{
+			LinkAttrs: netlink.LinkAttrs{Name: bandwidth.DefaultInterface},
+			PeerName:  "peer0",
+		}
+		Expect(netlink.LinkAdd(veth)).To(BeNil())
+		Expect(netlink.LinkSetUp(veth)).To(BeNil())
+
+		limits = &bandwidth.Limits{
+			Interface:    bandwidth.DefaultInterface,
+			IngressRate:  1000000,
+			IngressBurst: 1000000,
+			EgressRate:   2000000,
+			EgressBurst:  2000000,
+		}
+	})
+
+	AfterEach(func() {
+		Expect(netns.DeleteNamed(netNsName)).To(BeNil())
+	})
+
+	It("should apply egress and ingress limits", func() {
+		// Given
+		skipWithoutPolicing()
+		// When
+		err := bandwidth.Apply(netNsPath, limits)
+
+		// Then
+		Expect(err).To(BeNil())
+		var tbf *netlink.Tbf
+		var ingress *netlink.Ingress
+		for _, qdisc := range qdiscs() {
+			switch q := qdisc.(type) {
+			case *netlink.Tbf:
+				tbf = q
+			case *netlink.Ingress:
+				ingress = q
+			}
+		}
+		Expect(tbf).NotTo(BeNil())
+		Expect(tbf.Rate).To(BeEquivalentTo(limits.EgressRate / 8))
+		Expect(ingress).NotTo(BeNil())
+	})
+
+	It("should replace already applied limits", func() {
+		// Given
+		skipWithoutPolicing()
+		Expect(bandwidth.Apply(netNsPath, limits)).To(BeNil())
+		limits.EgressRate = 4000000
+
+		// When
+		err := bandwidth.Apply(netNsPath, limits)
+
+		// Then
+		Expect(err).To(BeNil())
+		found := false
+		for _, qdisc := range qdiscs() {
+			if tbf, ok := qdisc.(*netlink.Tbf); ok {
+				Expect(tbf.Rate).To(BeEquivalentTo(limits.EgressRate / 8))
+				found = true
+			}
+		}
+		Expect(found).To(BeTrue())
+	})
+
+	It("should apply only the egress limit", func() {
+		// Given
+		limits.IngressRate = 0
+
+		// When
+		err := bandwidth.Apply(netNsPath, limits)
+
+		// Then
+		Expect(err).To(BeNil())
+		for _, qdisc := range qdiscs() {
+			Expect(qdisc).NotTo(BeAssignableToTypeOf(&netlink.Ingress{}))
+		}
+	})
+
+	It("should fail to apply without interface", func() {
+		// Given
+		limits.Interface = "missing"
+
+		// When
+		err := bandwidth.Apply(netNsPath, limits)
+
+		// Then
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should remove the limits", func() {
+		// Given
+		skipWithoutPolicing()
+		Expect(bandwidth.Apply(netNsPath, limits)).To(BeNil())
+
+		// When
+		err := bandwidth.Remove(netNsPath, limits)
+
+		// Then
+		Expect(err).To(BeNil())
+		for _, qdisc := range qdiscs() {
+			Expect(qdisc).NotTo(BeAssignableToTypeOf(&netlink.Tbf{}))
+			Expect(qdisc).NotTo(BeAssignableToTypeOf(&netlink.Ingress{}))
+		}
+	})
+
+	It("should succeed to remove limits which are not applied", func() {
+		// Given
+		// When
+		err := bandwidth.Remove(netNsPath, limits)
+
+		// Then
+		Expect(err).To(BeNil())
+	})
+
+	It("should succeed to remove limits without network namespace", func() {
+		// Given
+		// When
+		err := bandwidth.Remove(filepath.Join(netNsDir, "missing"), limits)
+
+		// Then
+		Expect(err).To(BeNil())
+	})
+})
@@ -6,6 +6,9 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -23,6 +26,12 @@ type CrioClient interface {
 	DaemonInfo() (types.CrioInfo, error)
 	ContainerInfo(string) (*types.ContainerInfo, error)
 	ConfigInfo() (string, error)
+	PullsInfo() ([]types.PullInfo, error)
+	AbortPull(string) error
+	RegistriesInfo() ([]types.RegistryHealthInfo, error)
+	ContainerEvents(uint64, string) ([]types.ContainerEventInfo, error)
+	ReloadConfig(bool) (*types.ConfigReloadInfo, error)
+	ConfigOptions() ([]types.ConfigOption, error)
 }
 
 type crioClientImpl struct {
@@ -123,3 +132,151 @@ func (c *crioClientImpl) ConfigInfo() (string, error) {
 	}
 	return string(body), nil
 }
+
+// ConfigOptions returns the effective configuration options of cri-o together
+// with their origin by querying the cri-o config endpoint.
+func (c *crioClientImpl) ConfigOptions() ([]types.ConfigOption, error) {
+	req, err := c.getRequest(server.InspectConfigEndpoint + "?format=json")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	options := []types.ConfigOption{}
+	if err := json.NewDecoder(resp.Body).Decode(&options); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+// PullsInfo returns the image pulls in progress by querying the cri-o pulls
+// endpoint.
+func (c *crioClientImpl) PullsInfo() ([]types.PullInfo, error) {
+	req, err := c.getRequest(server.InspectPullsEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	pulls := []types.PullInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(&pulls); err != nil {
+		return nil, err
+	}
+	return pulls, nil
+}
+
+// AbortPull aborts the image pull in progress with the provided ID.
+func (c *crioClientImpl) AbortPull(id string) error {
+	req, err := c.getRequest(server.InspectPullsAbortEndpoint + "/" + id)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("abort pull %s: %s", id, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// RegistriesInfo returns the health of the registries used for image pulls by
+// querying the cri-o registries endpoint.
+func (c *crioClientImpl) RegistriesInfo() ([]types.RegistryHealthInfo, error) {
+	req, err := c.getRequest(server.InspectRegistriesEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	registries := []types.RegistryHealthInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(&registries); err != nil {
+		return nil, err
+	}
+	return registries, nil
+}
+
+// ContainerEvents returns the journaled container events having a sequence
+// number greater than seq and which have been created at or after since, by
+// querying the cri-o events endpoint. The time can be provided in RFC 3339
+// format or in Unix nanoseconds, where an empty value matches all events.
+func (c *crioClientImpl) ContainerEvents(seq uint64, since string) ([]types.ContainerEventInfo, error) {
+	query := url.Values{}
+	if seq > 0 {
+		query.Set("seq", strconv.FormatUint(seq, 10))
+	}
+	if since != "" {
+		query.Set("since", since)
+	}
+	path := server.InspectEventsEndpoint
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	req, err := c.getRequest(path)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("get container events: %s", strings.TrimSpace(string(body)))
+	}
+	events := []types.ContainerEventInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ReloadConfig reloads the configuration of cri-o by querying the cri-o config
+// reload endpoint. The changes are only validated but not applied if dryRun is
+// true.
+func (c *crioClientImpl) ReloadConfig(dryRun bool) (*types.ConfigReloadInfo, error) {
+	path := server.InspectConfigReloadEndpoint
+	if dryRun {
+		path += "?dry-run=true"
+	}
+	req, err := c.getRequest(path)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("reload config: %s", strings.TrimSpace(string(body)))
+	}
+	reload := &types.ConfigReloadInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(reload); err != nil {
+		return nil, err
+	}
+	return reload, nil
+}
@@ -71,6 +71,13 @@ type CgroupManager interface {
 	// It returns the cgroupfs parent that conmon was put into
 	// so that CRI-O can clean the parent cgroup of the newly added conmon once the process terminates (systemd handles this for us)
 	MoveConmonToCgroup(cid, cgroupParent, conmonCgroup string, pid int, resources *rspec.LinuxResources) (string, error)
+	// ConmonCgroupAbsolutePath takes the container ID, cgroup parent and conmon's cgroup (from the config).
+	// It returns the cgroup path on disk conmon gets moved into by MoveConmonToCgroup.
+	ConmonCgroupAbsolutePath(cid, cgroupParent, conmonCgroup string) (string, error)
+	// PopulateConmonCgroupStats takes the container ID, sandbox parent cgroup, conmon's cgroup (from the config)
+	// and sandbox stats object. It adds the usage of conmon's cgroup to the object, if conmon is not placed
+	// within the sandbox parent cgroup, which already accounts for it.
+	PopulateConmonCgroupStats(cid, sbParent, conmonCgroup string, stats *types.PodSandboxStats) error
 	// CreateSandboxCgroup takes the sandbox parent, and sandbox ID.
 	// It creates a new cgroup for that sandbox, which is useful when spoofing an infra container.
 	CreateSandboxCgroup(sbParent, containerID string) error
@@ -223,3 +230,7 @@ func removeSandboxCgroup(sbParent, containerCgroup string) error {
 func containerCgroupPath(id string) string {
 	return CrioPrefix + "-" + id
 }
+
+func conmonCgroupPath(id string) string {
+	return CrioPrefix + "-conmon-" + id
+}
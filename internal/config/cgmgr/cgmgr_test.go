@@ -6,6 +6,7 @@ import (
 	"github.com/cri-o/cri-o/internal/config/cgmgr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
 const (
@@ -167,6 +168,31 @@ var _ = t.Describe("Cgmgr", func() {
 				Expect(err).To(Not(BeNil()))
 			})
 		})
+		t.Describe("ConmonCgroupAbsolutePath", func() {
+			It("should be below the sandbox parent", func() {
+				// Given
+				// When
+				cgPath, err := sut.ConmonCgroupAbsolutePath(cID, "/"+genericSandboxParent, "pod")
+
+				// Then
+				Expect(err).To(BeNil())
+				Expect(cgPath).To(Equal("/" + genericSandboxParent + "/crio-conmon-" + cID))
+			})
+		})
+		t.Describe("PopulateConmonCgroupStats", func() {
+			It("should not add anything", func() {
+				// Given
+				stats := &types.PodSandboxStats{Linux: &types.LinuxPodSandboxStats{}}
+
+				// When
+				err := sut.PopulateConmonCgroupStats(cID, genericSandboxParent, "pod", stats)
+
+				// Then
+				Expect(err).To(BeNil())
+				Expect(stats.Linux.Cpu).To(BeNil())
+				Expect(stats.Linux.Memory).To(BeNil())
+			})
+		})
 	})
 	t.Describe("SystemdManager", func() {
 		t.Describe("ContainerCgroupPath", func() {
@@ -252,5 +278,59 @@ var _ = t.Describe("Cgmgr", func() {
 				Expect(err).To(Not(BeNil()))
 			})
 		})
+		t.Describe("ConmonCgroupAbsolutePath", func() {
+			It("should be below the sandbox parent", func() {
+				// Given
+				// When
+				cgPath, err := sut.ConmonCgroupAbsolutePath(cID, "kubepods-pod123.slice", "pod")
+
+				// Then
+				Expect(err).To(BeNil())
+				Expect(cgPath).To(Equal("/kubepods.slice/kubepods-pod123.slice/crio-conmon-" + cID + ".scope"))
+			})
+			It("should be below the conmon slice", func() {
+				// Given
+				// When
+				cgPath, err := sut.ConmonCgroupAbsolutePath(cID, "kubepods-pod123.slice", "conmon.slice")
+
+				// Then
+				Expect(err).To(BeNil())
+				Expect(cgPath).To(Equal("/conmon.slice/crio-conmon-" + cID + ".scope"))
+			})
+			It("should contain default system.slice", func() {
+				// Given
+				// When
+				cgPath, err := sut.ConmonCgroupAbsolutePath(cID, "", "")
+
+				// Then
+				Expect(err).To(BeNil())
+				Expect(cgPath).To(Equal("/system.slice/crio-conmon-" + cID + ".scope"))
+			})
+		})
+		t.Describe("PopulateConmonCgroupStats", func() {
+			It("should not add anything if conmon is within the sandbox", func() {
+				// Given
+				stats := &types.PodSandboxStats{Linux: &types.LinuxPodSandboxStats{}}
+
+				// When
+				err := sut.PopulateConmonCgroupStats(cID, "kubepods-pod123.slice", "pod", stats)
+
+				// Then
+				Expect(err).To(BeNil())
+				Expect(stats.Linux.Cpu).To(BeNil())
+				Expect(stats.Linux.Memory).To(BeNil())
+			})
+			It("should fail if the conmon scope does not exist", func() {
+				// Given
+				stats := &types.PodSandboxStats{Linux: &types.LinuxPodSandboxStats{}}
+
+				// When
+				err := sut.PopulateConmonCgroupStats(cID, "kubepods-pod123.slice", "conmon.slice", stats)
+
+				// Then
+				Expect(err).NotTo(BeNil())
+				Expect(stats.Linux.Cpu).To(BeNil())
+			})
+		})
 	})
 })
@@ -94,7 +94,7 @@ func (m *CgroupfsManager) PopulateSandboxCgroupStats(sbParent string, stats *typ
 // It attempts to move conmon to the correct cgroup.
 // It returns the cgroupfs parent that conmon was put into
 // so that CRI-O can clean the cgroup path of the newly added conmon once the process terminates (systemd handles this for us)
-func (*CgroupfsManager) MoveConmonToCgroup(cid, cgroupParent, conmonCgroup string, pid int, resources *rspec.LinuxResources) (cgroupPathToClean string, _ error) {
+func (m *CgroupfsManager) MoveConmonToCgroup(cid, cgroupParent, conmonCgroup string, pid int, resources *rspec.LinuxResources) (cgroupPathToClean string, _ error) {
 	if conmonCgroup != utils.PodCgroupName && conmonCgroup != "" {
 		return "", fmt.Errorf("conmon cgroup %s invalid for cgroupfs", conmonCgroup)
 	}
@@ -103,7 +103,10 @@ func (*CgroupfsManager) MoveConmonToCgroup(cid, cgroupParent, conmonCgroup strin
 		resources = &rspec.LinuxResources{}
 	}
 
-	cgroupPath := fmt.Sprintf("%s/crio-conmon-%s", cgroupParent, cid)
+	cgroupPath, err := m.ConmonCgroupAbsolutePath(cid, cgroupParent, conmonCgroup)
+	if err != nil {
+		return "", err
+	}
 	control, err := cgroups.New(cgroupPath, &cgcfgs.Resources{})
 	if err != nil {
 		logrus.Warnf("Failed to add conmon to cgroupfs sandbox cgroup: %v", err)
@@ -130,6 +133,17 @@ func (*CgroupfsManager) MoveConmonToCgroup(cid, cgroupParent, conmonCgroup strin
 	return cgroupPath, nil
 }
 
+// ConmonCgroupAbsolutePath returns the cgroup of conmon below the cgroup parent.
+func (*CgroupfsManager) ConmonCgroupAbsolutePath(cid, cgroupParent, _ string) (string, error) {
+	return fmt.Sprintf("%s/%s", cgroupParent, conmonCgroupPath(cid)), nil
+}
+
+// PopulateConmonCgroupStats does nothing, because conmon is always placed
+// within the sandbox parent cgroup and therefore already accounted for.
+func (*CgroupfsManager) PopulateConmonCgroupStats(string, string, string, *types.PodSandboxStats) error {
+	return nil
+}
+
 func setWorkloadSettings(cgPath string, resources *rspec.LinuxResources) (err error) {
 	if resources.CPU == nil {
 		return nil
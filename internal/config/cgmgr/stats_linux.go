@@ -29,6 +29,50 @@ func populateSandboxCgroupStatsFromPath(cgroupPath string, stats *types.PodSandb
 	return err
 }
 
+// addSandboxCgroupStatsFromPath adds the CPU, memory and process usage of the
+// cgroup to the already populated sandbox stats.
+func addSandboxCgroupStatsFromPath(cgroupPath string, stats *types.PodSandboxStats) error {
+	cgroupStats, err := cgroupStatsFromPath(cgroupPath)
+	if err != nil {
+		return err
+	}
+	systemNano := time.Now().UnixNano()
+	memory, err := createMemoryStats(systemNano, cgroupStats, cgroupPath)
+	if err != nil {
+		return err
+	}
+
+	if stats.Linux.Cpu == nil {
+		stats.Linux.Cpu = createCPUStats(systemNano, &libctrcgroups.Stats{})
+	}
+	stats.Linux.Cpu.UsageCoreNanoSeconds.Value += cgroupStats.CpuStats.CpuUsage.TotalUsage
+
+	if stats.Linux.Process == nil {
+		stats.Linux.Process = createProcessUsage(systemNano, &libctrcgroups.Stats{})
+	}
+	stats.Linux.Process.ProcessCount.Value += cgroupStats.PidsStats.Current
+
+	if stats.Linux.Memory == nil {
+		stats.Linux.Memory = memory
+		return nil
+	}
+	for _, field := range []struct {
+		total *types.UInt64Value
+		value uint64
+	}{
+		{stats.Linux.Memory.UsageBytes, memory.UsageBytes.Value},
+		{stats.Linux.Memory.WorkingSetBytes, memory.WorkingSetBytes.Value},
+		{stats.Linux.Memory.RssBytes, memory.RssBytes.Value},
+		{stats.Linux.Memory.PageFaults, memory.PageFaults.Value},
+		{stats.Linux.Memory.MajorPageFaults, memory.MajorPageFaults.Value},
+	} {
+		if field.total != nil {
+			field.total.Value += field.value
+		}
+	}
+	return nil
+}
+
 func populateContainerCgroupStatsFromPath(cgroupPath string, stats *types.ContainerStats) error {
 	// checks cgroup just for the container, not the entire pod
 	cgroupStats, err := cgroupStatsFromPath(cgroupPath)
@@ -100,7 +100,7 @@ func (m *SystemdManager) MoveConmonToCgroup(cid, cgroupParent, conmonCgroup stri
 	if strings.HasSuffix(conmonCgroup, ".slice") {
 		cgroupParent = conmonCgroup
 	}
-	conmonUnitName := fmt.Sprintf("crio-conmon-%s.scope", cid)
+	conmonUnitName := conmonCgroupPath(cid) + ".scope"
 
 	// Set the systemd KillSignal to SIGPIPE that conmon ignores.
 	// This helps during node shutdown so that conmon waits for the container
@@ -144,6 +144,36 @@ func (m *SystemdManager) MoveConmonToCgroup(cid, cgroupParent, conmonCgroup stri
 	return "", nil
 }
 
+// ConmonCgroupAbsolutePath returns the path of conmon's scope, which is
+// placed in the conmon cgroup if it is a slice, otherwise in the cgroup parent.
+func (*SystemdManager) ConmonCgroupAbsolutePath(cid, cgroupParent, conmonCgroup string) (string, error) {
+	parent := cgroupParent
+	if strings.HasSuffix(conmonCgroup, ".slice") {
+		parent = conmonCgroup
+	}
+	if parent == "" {
+		parent = defaultSystemdParent
+	}
+	cgroup, err := systemd.ExpandSlice(parent)
+	if err != nil {
+		return "", fmt.Errorf("expanding systemd slice to get conmon %s cgroup: %w", cid, err)
+	}
+	return filepath.Join(cgroup, conmonCgroupPath(cid)+".scope"), nil
+}
+
+// PopulateConmonCgroupStats adds the usage of conmon's scope to the sandbox
+// stats, if conmon is placed in a dedicated slice instead of the sandbox one.
+func (m *SystemdManager) PopulateConmonCgroupStats(cid, sbParent, conmonCgroup string, stats *types.PodSandboxStats) error {
+	if !strings.HasSuffix(conmonCgroup, ".slice") {
+		return nil
+	}
+	cgPath, err := m.ConmonCgroupAbsolutePath(cid, sbParent, conmonCgroup)
+	if err != nil {
+		return err
+	}
+	return addSandboxCgroupStatsFromPath(cgPath, stats)
+}
+
 // SandboxCgroupPath takes the sandbox parent, and sandbox ID. It
 // returns the cgroup parent, cgroup path, and error.
 // It also checks there is enough memory in the given cgroup
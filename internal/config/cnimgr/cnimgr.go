@@ -2,9 +2,11 @@ package cnimgr
 
 import (
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
+	"github.com/containernetworking/cni/libcni"
 	"github.com/cri-o/ocicni/pkg/ocicni"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
@@ -15,7 +17,11 @@ type CNIManager struct {
 	lastError error
 	watchers  []chan bool
 	shutdown  bool
-	mutex     sync.RWMutex
+	// podCIDRs are the pod CIDRs of the node as provided by the kubelet
+	podCIDRs []string
+	// networkDir is the directory containing the CNI network configurations
+	networkDir string
+	mutex      sync.RWMutex
 }
 
 func New(defaultNetwork, networkDir string, pluginDirs ...string) (*CNIManager, error) {
@@ -27,7 +33,8 @@ func New(defaultNetwork, networkDir string, pluginDirs ...string) (*CNIManager,
 		return nil, fmt.Errorf("initialize CNI plugin: %w", err)
 	}
 	mgr := &CNIManager{
-		plugin: plugin,
+		plugin:     plugin,
+		networkDir: networkDir,
 	}
 	go mgr.pollUntilReady()
 	return mgr, nil
@@ -68,6 +75,59 @@ func (c *CNIManager) Plugin() ocicni.CNIPlugin {
 	return c.plugin
 }
 
+// PodCIDRs returns the currently configured pod CIDRs of the node.
+func (c *CNIManager) PodCIDRs() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return append([]string(nil), c.podCIDRs...)
+}
+
+// SetPodCIDRs sets the pod CIDRs of the node, which will be passed to the CNI
+// plugins as ipRanges capability on every subsequent network setup. It returns
+// true if the pod CIDRs have been changed.
+func (c *CNIManager) SetPodCIDRs(cidrs []string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if slices.Equal(c.podCIDRs, cidrs) {
+		return false
+	}
+	c.podCIDRs = append([]string(nil), cidrs...)
+	return true
+}
+
+// PodIPRanges returns the configured pod CIDRs in the format of the CNI
+// ipRanges capability. Every CIDR results in its own range set, which allows
+// IPAM plugins to allocate one address per IP family.
+func (c *CNIManager) PodIPRanges() [][]ocicni.IpRange {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if len(c.podCIDRs) == 0 {
+		return nil
+	}
+	ranges := make([][]ocicni.IpRange, 0, len(c.podCIDRs))
+	for _, cidr := range c.podCIDRs {
+		ranges = append(ranges, []ocicni.IpRange{{Subnet: cidr}})
+	}
+	return ranges
+}
+
+// DefaultNetworkHasCapability returns true if any plugin of the default
+// network configuration list announces support for the provided CNI
+// capability, for example "bandwidth".
+func (c *CNIManager) DefaultNetworkHasCapability(capability string) (bool, error) {
+	name := c.plugin.GetDefaultNetworkName()
+	list, err := libcni.LoadConfList(c.networkDir, name)
+	if err != nil {
+		return false, fmt.Errorf("load CNI network %s: %w", name, err)
+	}
+	for _, plugin := range list.Plugins {
+		if plugin.Network.Capabilities[capability] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // Add watcher creates a new watcher for the CNI manager
 // said watcher will send a `true` value if the CNI plugin was successfully ready
 // or `false` if the server shutdown first
@@ -0,0 +1,60 @@
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/cri-o/cri-o/pkg/config"
+)
+
+// WriteDropIn writes the changed and renamed options of the migrated config
+// into the drop-in configuration file at path. Runtime handlers are written as
+// a whole, because a drop-in replaces the complete handler table.
+func WriteDropIn(path string, cfg *config.Config, changes []Change) error {
+	tables := map[string]any{}
+	for i := range changes {
+		change := &changes[i]
+		switch change.Kind {
+		case ChangeKindChanged:
+			setDropInValue(tables, cfg, change.key, change.value)
+		case ChangeKindRenamed:
+			setDropInValue(tables, cfg, change.key, change.cleared)
+			setDropInValue(tables, cfg, change.newKey, change.value)
+		}
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString("# Migrated configuration options written by \"crio config migrate\".\n")
+	if err := toml.NewEncoder(&buffer).Encode(tables); err != nil {
+		return fmt.Errorf("encode drop-in: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create drop-in directory: %w", err)
+	}
+	return os.WriteFile(path, buffer.Bytes(), 0o644)
+}
+
+// setDropInValue sets the value of the option key in the nested tables.
+func setDropInValue(tables map[string]any, cfg *config.Config, key toml.Key, value any) {
+	const handlerKeyLen = 4 // crio.runtime.runtimes.<name>
+	if len(key) > handlerKeyLen && key[0] == "crio" && key[1] == "runtime" && key[2] == "runtimes" {
+		if handler, ok := cfg.Runtimes[key[3]]; ok {
+			key, value = key[:handlerKeyLen], handler
+		}
+	}
+
+	table := tables
+	for _, part := range key[:len(key)-1] {
+		next, ok := table[part].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			table[part] = next
+		}
+		table = next
+	}
+	table[key[len(key)-1]] = value
+}
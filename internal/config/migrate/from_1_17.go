@@ -3,15 +3,19 @@ package migrate
 import (
 	"strings"
 
+	"github.com/BurntSushi/toml"
 	"github.com/cri-o/cri-o/internal/config/apparmor"
 	"github.com/cri-o/cri-o/pkg/config"
 	"github.com/sirupsen/logrus"
 )
 
 // migrateFrom1_17 migrates a config from the 1.17.x version
-func migrateFrom1_17(cfg *config.Config) error {
+func migrateFrom1_17(m *migration) error {
+	cfg := m.cfg
+
 	// Remove NET_RAW and SYS_CHROOT capability by default
 	// https://github.com/cri-o/cri-o/pull/3119
+	oldDefaultCapabilities := cfg.DefaultCapabilities
 	newDefaultCapabilities := []string{}
 	logrus.Infof("Checking for NET_RAW and SYS_CHROOT capabilities, which have been removed per default")
 	for _, cap := range cfg.DefaultCapabilities {
@@ -22,6 +26,9 @@ func migrateFrom1_17(cfg *config.Config) error {
 		newDefaultCapabilities = append(newDefaultCapabilities, cap)
 	}
 	cfg.DefaultCapabilities = newDefaultCapabilities
+	if len(newDefaultCapabilities) != len(oldDefaultCapabilities) {
+		m.changed(toml.Key{"crio", "runtime", "default_capabilities"}, oldDefaultCapabilities, newDefaultCapabilities)
+	}
 
 	// Change AppArmor profile to not contain version info any more
 	// https://github.com/cri-o/cri-o/pull/3287
@@ -29,6 +36,7 @@ func migrateFrom1_17(cfg *config.Config) error {
 	if cfg.ApparmorProfile != apparmor.DefaultProfile && strings.Contains(
 		cfg.ApparmorProfile, apparmor.DefaultProfile,
 	) {
+		m.changed(toml.Key{"crio", "runtime", "apparmor_profile"}, cfg.ApparmorProfile, apparmor.DefaultProfile)
 		cfg.ApparmorProfile = apparmor.DefaultProfile
 		logrus.Infof(`Changing "apparmor_profile" to %q`, cfg.ApparmorProfile)
 	}
@@ -37,6 +45,7 @@ func migrateFrom1_17(cfg *config.Config) error {
 	const newLogLevel = "info"
 	logrus.Infof("Checking for the log level, which has changed from error to info")
 	if cfg.LogLevel == "error" {
+		m.changed(toml.Key{"crio", "runtime", "log_level"}, cfg.LogLevel, newLogLevel)
 		cfg.LogLevel = newLogLevel
 		logrus.Infof(`Changing "log_level" to %q`, newLogLevel)
 	}
@@ -46,6 +55,7 @@ func migrateFrom1_17(cfg *config.Config) error {
 	logrus.Infof("Checking for ctr_stop_timeout, which now has a minimum value of 30")
 	const newCtrStopTimeout = 30
 	if cfg.CtrStopTimeout < newCtrStopTimeout {
+		m.changed(toml.Key{"crio", "runtime", "ctr_stop_timeout"}, cfg.CtrStopTimeout, int64(newCtrStopTimeout))
 		cfg.CtrStopTimeout = newCtrStopTimeout
 		logrus.Infof(`Changing "ctr_stop_timeout" to %d`, cfg.CtrStopTimeout)
 	}
@@ -55,6 +65,7 @@ func migrateFrom1_17(cfg *config.Config) error {
 	logrus.Infof("Checking for namespaces_dir, which now should be /var/run instead of /var/run/crio/ns")
 	newNamespacesDir := "/var/run"
 	if cfg.NamespacesDir == "/var/run/crio/ns" {
+		m.changed(toml.Key{"crio", "runtime", "namespaces_dir"}, cfg.NamespacesDir, newNamespacesDir)
 		cfg.NamespacesDir = newNamespacesDir
 		logrus.Infof(`Changing "namespaces_dir" to %s`, cfg.NamespacesDir)
 	}
@@ -63,9 +74,24 @@ func migrateFrom1_17(cfg *config.Config) error {
 	// https://github.com/cri-o/cri-o/pull/4550
 	logrus.Infof("Checking for pause_image, which now should be %s instead of registry.k8s.io/pause:3.1 or 3.2", config.DefaultPauseImage)
 	if cfg.PauseImage == "registry.k8s.io/pause:3.1" || cfg.PauseImage == "registry.k8s.io/pause:3.2" {
+		m.changed(toml.Key{"crio", "image", "pause_image"}, cfg.PauseImage, config.DefaultPauseImage)
 		cfg.PauseImage = config.DefaultPauseImage
 		logrus.Infof(`Changing "pause_image" to %s`, cfg.PauseImage)
 	}
 
+	// Move the deprecated plugin_dir into plugin_dirs
+	logrus.Infof("Checking for plugin_dir, which has been replaced by plugin_dirs")
+	if cfg.PluginDir != "" {
+		pluginDirs := append(append([]string{}, cfg.PluginDirs...), cfg.PluginDir)
+		m.renamed(
+			toml.Key{"crio", "network", "plugin_dir"},
+			toml.Key{"crio", "network", "plugin_dirs"},
+			cfg.PluginDir, pluginDirs, "",
+		)
+		cfg.PluginDirs = pluginDirs
+		cfg.PluginDir = ""
+		logrus.Infof(`Moving "plugin_dir" into "plugin_dirs"`)
+	}
+
 	return nil
 }
@@ -0,0 +1,77 @@
+package migrate
+
+import (
+	"sort"
+
+	"github.com/BurntSushi/toml"
+	"github.com/cri-o/cri-o/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+// previousPauseImages are the former default pause images.
+var previousPauseImages = map[string]bool{
+	"k8s.gcr.io/pause:3.2":      true,
+	"k8s.gcr.io/pause:3.5":      true,
+	"k8s.gcr.io/pause:3.6":      true,
+	"registry.k8s.io/pause:3.6": true,
+	"registry.k8s.io/pause:3.7": true,
+	"registry.k8s.io/pause:3.8": true,
+}
+
+// migrateFrom1_27 migrates a config from the 1.27.x version
+func migrateFrom1_27(m *migration) error {
+	cfg := m.cfg
+
+	// Move the conmon options into the monitor options of the runtime handlers
+	logrus.Infof("Checking for conmon, conmon_cgroup and conmon_env, which have been replaced by the runtime handler monitor options")
+	names := []string{}
+	for name, handler := range cfg.Runtimes {
+		if handler.RuntimeType == config.DefaultRuntimeType || handler.RuntimeType == "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		handler := cfg.Runtimes[name]
+		if cfg.Conmon != "" {
+			m.renamed(
+				toml.Key{"crio", "runtime", "conmon"},
+				toml.Key{"crio", "runtime", "runtimes", name, "monitor_path"},
+				cfg.Conmon, cfg.Conmon, "",
+			)
+			handler.MonitorPath = cfg.Conmon
+			logrus.Infof(`Moving "conmon" into "monitor_path" of runtime %q`, name)
+		}
+		if cfg.ConmonCgroup != "" {
+			m.renamed(
+				toml.Key{"crio", "runtime", "conmon_cgroup"},
+				toml.Key{"crio", "runtime", "runtimes", name, "monitor_cgroup"},
+				cfg.ConmonCgroup, cfg.ConmonCgroup, "",
+			)
+			handler.MonitorCgroup = cfg.ConmonCgroup
+			logrus.Infof(`Moving "conmon_cgroup" into "monitor_cgroup" of runtime %q`, name)
+		}
+		if len(cfg.ConmonEnv) != 0 {
+			m.renamed(
+				toml.Key{"crio", "runtime", "conmon_env"},
+				toml.Key{"crio", "runtime", "runtimes", name, "monitor_env"},
+				cfg.ConmonEnv, cfg.ConmonEnv, []string{},
+			)
+			handler.MonitorEnv = cfg.ConmonEnv
+			logrus.Infof(`Moving "conmon_env" into "monitor_env" of runtime %q`, name)
+		}
+	}
+	cfg.Conmon = ""
+	cfg.ConmonCgroup = ""
+	cfg.ConmonEnv = nil
+
+	// Upgrade pause image
+	logrus.Infof("Checking for pause_image, which now should be %s instead of a previous default", config.DefaultPauseImage)
+	if previousPauseImages[cfg.PauseImage] {
+		m.changed(toml.Key{"crio", "image", "pause_image"}, cfg.PauseImage, config.DefaultPauseImage)
+		cfg.PauseImage = config.DefaultPauseImage
+		logrus.Infof(`Changing "pause_image" to %s`, cfg.PauseImage)
+	}
+
+	return nil
+}
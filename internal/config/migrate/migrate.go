@@ -1,24 +1,228 @@
 package migrate
 
 import (
+	"bytes"
 	"fmt"
+	"reflect"
+	"strings"
 
+	"github.com/BurntSushi/toml"
+	"github.com/blang/semver/v4"
+	"github.com/cri-o/cri-o/internal/version"
 	"github.com/cri-o/cri-o/pkg/config"
+	"github.com/sirupsen/logrus"
 )
 
 // All possible migration scenarios
 const (
 	FromPrevious = From1_17
 	From1_17     = "1.17"
+	From1_27     = "1.27"
 )
 
+// step is a migration of the configuration from the release version to the
+// next one.
+type step struct {
+	version string
+	migrate func(*migration) error
+}
+
+// steps is the chain of migrations ordered by their version. Migrating from a
+// version runs all steps starting from that version.
+var steps = []step{
+	{From1_17, migrateFrom1_17},
+	{From1_27, migrateFrom1_27},
+}
+
+// ChangeKind is the kind of a configuration option change.
+type ChangeKind string
+
+const (
+	// ChangeKindChanged is used for options whose value has been changed.
+	ChangeKindChanged ChangeKind = "changed"
+
+	// ChangeKindRenamed is used for options which have been replaced by
+	// another option.
+	ChangeKindRenamed ChangeKind = "renamed"
+
+	// ChangeKindRemoved is used for options which are not supported any more.
+	ChangeKindRemoved ChangeKind = "removed"
+)
+
+// Change is a change of a configuration option done by a migration.
+type Change struct {
+	// Version is the version of the migration step which did the change. It
+	// is empty for removed options.
+	Version string
+
+	// Kind is the kind of the change.
+	Kind ChangeKind
+
+	// Option is the TOML key of the changed option, for example
+	// "crio.runtime.log_level".
+	Option string
+
+	// NewOption is the TOML key of the option replacing a renamed option.
+	NewOption string
+
+	// Old and New are the TOML encoded values of the option before and after
+	// the change.
+	Old, New string
+
+	key, newKey toml.Key
+	value       any
+	cleared     any
+}
+
+// String returns a human readable representation of the change.
+func (c *Change) String() string {
+	switch c.Kind {
+	case ChangeKindRenamed:
+		return fmt.Sprintf("[%s] renamed %s to %s: %s -> %s", c.Version, c.Option, c.NewOption, c.Old, c.New)
+	case ChangeKindRemoved:
+		return fmt.Sprintf("removed %s", c.Option)
+	default:
+		return fmt.Sprintf("[%s] changed %s: %s -> %s", c.Version, c.Option, c.Old, c.New)
+	}
+}
+
+// migration is the state of a running migration.
+type migration struct {
+	cfg     *config.Config
+	version string
+	changes []Change
+}
+
+// changed records the change of the option value, if any.
+func (m *migration) changed(key toml.Key, old, new any) {
+	if reflect.DeepEqual(old, new) {
+		return
+	}
+	m.changes = append(m.changes, Change{
+		Version: m.version,
+		Kind:    ChangeKindChanged,
+		Option:  key.String(),
+		Old:     formatValue(old),
+		New:     formatValue(new),
+		key:     key,
+		value:   new,
+	})
+}
+
+// renamed records that the old value of the option key has been moved to the
+// option newKey, resulting in its new value. The cleared value is the one
+// which disables the old option.
+func (m *migration) renamed(key, newKey toml.Key, old, new, cleared any) {
+	m.changes = append(m.changes, Change{
+		Version:   m.version,
+		Kind:      ChangeKindRenamed,
+		Option:    key.String(),
+		NewOption: newKey.String(),
+		Old:       formatValue(old),
+		New:       formatValue(new),
+		key:       key,
+		newKey:    newKey,
+		value:     new,
+		cleared:   cleared,
+	})
+}
+
 // Config migrates the provided config from the provided scenario to the
 // current one.
 func Config(cfg *config.Config, from string) error {
-	// 1.17 specific settings
-	if from == From1_17 {
-		return migrateFrom1_17(cfg)
+	changes, err := Migrate(cfg, from)
+	if err != nil {
+		return err
 	}
+	for i := range changes {
+		logrus.Infof("Migrated option: %s", changes[i].String())
+	}
+	return nil
+}
 
-	return fmt.Errorf("unsupported migration version %q", from)
+// Migrate runs all migration steps from the provided version to the current
+// one on the config and returns the changes.
+func Migrate(cfg *config.Config, from string) ([]Change, error) {
+	fromVersion, err := parseVersion(from)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &migration{cfg: cfg}
+	for _, s := range steps {
+		stepVersion, err := parseVersion(s.version)
+		if err != nil {
+			return nil, err
+		}
+		if stepVersion.LT(fromVersion) {
+			continue
+		}
+		m.version = s.version
+		if err := s.migrate(m); err != nil {
+			return nil, fmt.Errorf("migrate from %s: %w", s.version, err)
+		}
+	}
+	return m.changes, nil
+}
+
+// File loads the configuration file at the provided path, migrates it from the
+// provided version to the current one and returns the migrated config together
+// with the changes, including the options which are not supported any more.
+func File(path, from string) (*config.Config, []Change, error) {
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("create default config: %w", err)
+	}
+	if err := cfg.UpdateFromFile(path); err != nil {
+		return nil, nil, fmt.Errorf("update config from file: %w", err)
+	}
+
+	changes, err := Migrate(cfg, from)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unknown, err := config.UnknownOptions(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get unknown options: %w", err)
+	}
+	for _, option := range unknown {
+		changes = append(changes, Change{Kind: ChangeKindRemoved, Option: option})
+	}
+
+	return cfg, changes, nil
+}
+
+// parseVersion parses a "major.minor" release version and verifies that it
+// can be migrated from.
+func parseVersion(v string) (semver.Version, error) {
+	parsed, err := semver.ParseTolerant(v)
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("unsupported migration version %q: %w", v, err)
+	}
+	current := semver.MustParse(version.Version)
+	oldest := semver.MustParse(From1_17 + ".0")
+	parsed.Patch, parsed.Pre, parsed.Build = 0, nil, nil
+	if parsed.LT(oldest) || parsed.Major != current.Major || parsed.Minor > current.Minor {
+		return semver.Version{}, fmt.Errorf(
+			"unsupported migration version %q, must be between %s and %d.%d",
+			v, From1_17, current.Major, current.Minor,
+		)
+	}
+	return parsed, nil
+}
+
+// formatValue returns the TOML representation of an option value.
+func formatValue(value any) string {
+	const name = "v"
+	var buffer bytes.Buffer
+	if err := toml.NewEncoder(&buffer).Encode(map[string]any{name: value}); err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	formatted := strings.TrimSpace(strings.TrimPrefix(buffer.String(), name+" = "))
+	if formatted == "" {
+		// Empty slices are omitted by the encoder
+		return "[]"
+	}
+	return formatted
 }
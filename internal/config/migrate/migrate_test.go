@@ -0,0 +1,136 @@
+package migrate_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/cri-o/cri-o/internal/config/migrate"
+	"github.com/cri-o/cri-o/pkg/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("Migrate", func() {
+	var sut *config.Config
+
+	BeforeEach(func() {
+		var err error
+		sut, err = config.DefaultConfig()
+		Expect(err).To(BeNil())
+	})
+
+	writeConfig := func(content string) string {
+		path := filepath.Join(t.MustTempDir("crio-migrate"), "crio.conf")
+		Expect(os.WriteFile(path, []byte(content), 0o644)).To(BeNil())
+		return path
+	}
+
+	changesByOption := func(changes []migrate.Change) map[string]migrate.Change {
+		res := map[string]migrate.Change{}
+		for _, change := range changes {
+			res[change.Option] = change
+		}
+		return res
+	}
+
+	It("should succeed without changes on the default config", func() {
+		// Given
+		// When
+		changes, err := migrate.Migrate(sut, migrate.From1_17)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(changes).To(BeEmpty())
+	})
+
+	It("should fail on unsupported versions", func() {
+		for _, from := range []string{"1.16", "2.0", "1.999", "invalid"} {
+			// Given
+			// When
+			_, err := migrate.Migrate(sut, from)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("unsupported migration version"))
+		}
+	})
+
+	It("should run all steps starting from the version", func() {
+		// Given
+		sut.LogLevel = "error"
+		sut.ConmonCgroup = "pod"
+
+		// When
+		changes, err := migrate.Migrate(sut, "1.20")
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(sut.LogLevel).To(Equal("error"))
+		Expect(sut.ConmonCgroup).To(BeEmpty())
+		Expect(sut.Runtimes["runc"].MonitorCgroup).To(Equal("pod"))
+		Expect(changes).To(HaveLen(1))
+		Expect(changes[0].Version).To(Equal(migrate.From1_27))
+		Expect(changes[0].Kind).To(Equal(migrate.ChangeKindRenamed))
+		Expect(changes[0].Option).To(Equal("crio.runtime.conmon_cgroup"))
+		Expect(changes[0].NewOption).To(Equal("crio.runtime.runtimes.runc.monitor_cgroup"))
+	})
+
+	It("should report changed options", func() {
+		// Given
+		sut.LogLevel = "error"
+		sut.PauseImage = "registry.k8s.io/pause:3.1"
+
+		// When
+		changes, err := migrate.Migrate(sut, migrate.From1_17)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(sut.LogLevel).To(Equal("info"))
+		Expect(sut.PauseImage).To(Equal(config.DefaultPauseImage))
+		byOption := changesByOption(changes)
+		Expect(byOption).To(HaveKey("crio.runtime.log_level"))
+		Expect(byOption["crio.runtime.log_level"].Kind).To(Equal(migrate.ChangeKindChanged))
+		Expect(byOption["crio.runtime.log_level"].Old).To(Equal(`"error"`))
+		Expect(byOption["crio.runtime.log_level"].New).To(Equal(`"info"`))
+		Expect(byOption).To(HaveKey("crio.image.pause_image"))
+	})
+
+	It("should report removed options of a file", func() {
+		// Given
+		path := writeConfig("[crio.runtime]\nmanage_ns_lifecycle = true\nlog_level = \"error\"\n")
+
+		// When
+		cfg, changes, err := migrate.File(path, migrate.From1_17)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(cfg.LogLevel).To(Equal("info"))
+		byOption := changesByOption(changes)
+		Expect(byOption).To(HaveKey("crio.runtime.manage_ns_lifecycle"))
+		Expect(byOption["crio.runtime.manage_ns_lifecycle"].Kind).To(Equal(migrate.ChangeKindRemoved))
+		Expect(byOption["crio.runtime.manage_ns_lifecycle"].Version).To(BeEmpty())
+	})
+
+	It("should write the migrated options into a drop-in", func() {
+		// Given
+		path := writeConfig("[crio.runtime]\nlog_level = \"error\"\nconmon_cgroup = \"pod\"\n")
+		cfg, changes, err := migrate.File(path, migrate.From1_17)
+		Expect(err).To(BeNil())
+		dropIn := filepath.Join(t.MustTempDir("crio-migrate"), "crio.conf.d", "99-migrated.conf")
+
+		// When
+		err = migrate.WriteDropIn(dropIn, cfg, changes)
+
+		// Then
+		Expect(err).To(BeNil())
+		migrated, err := config.DefaultConfig()
+		Expect(err).To(BeNil())
+		Expect(migrated.UpdateFromFile(path)).To(BeNil())
+		Expect(migrated.UpdateFromDropInFile(dropIn)).To(BeNil())
+		Expect(migrated.LogLevel).To(Equal("info"))
+		Expect(migrated.ConmonCgroup).To(BeEmpty())
+		Expect(migrated.Runtimes["runc"].MonitorCgroup).To(Equal("pod"))
+		Expect(migrated.Runtimes["runc"].RuntimeRoot).To(Equal(cfg.Runtimes["runc"].RuntimeRoot))
+	})
+})
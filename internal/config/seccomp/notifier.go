@@ -31,13 +31,24 @@ type Notifier struct {
 	timer          *time.Timer
 	timeLock       sync.Mutex
 	stopContainers bool
+	record         bool
 }
 
+// recordAllowedSyscalls are the syscalls which cannot be traced by the
+// notifier in record mode, because the runtime requires them for passing the
+// seccomp file descriptor. They are always part of the recorded profile.
+var recordAllowedSyscalls = []string{"write"}
+
 // StopContainers returns if the notifier should stop containers or not.
 func (n *Notifier) StopContainers() bool {
 	return n.stopContainers
 }
 
+// Record returns if the notifier records the used syscalls into a profile.
+func (n *Notifier) Record() bool {
+	return n.record
+}
+
 // Close can be used to close the notifier listener.
 func (n *Notifier) Close() error {
 	return n.listener.Close()
@@ -69,6 +80,43 @@ func (n *Notifier) UsedSyscalls() string {
 	return strings.Join(res, ", ")
 }
 
+// Profile returns a minimal seccomp profile which allows all syscalls used so
+// far. The default action and architectures are taken from the base profile,
+// which is usually the profile of the container.
+func (n *Notifier) Profile(base *specs.LinuxSeccomp) *specs.LinuxSeccomp {
+	names := append([]string{}, recordAllowedSyscalls...)
+	n.syscalls.Range(func(syscall, _ any) bool {
+		if s, ok := syscall.(string); ok && !containsString(names, s) {
+			names = append(names, s)
+		}
+		return true
+	})
+	sort.Strings(names)
+
+	profile := &specs.LinuxSeccomp{
+		DefaultAction: specs.ActErrno,
+		Syscalls: []specs.LinuxSyscall{{
+			Names:  names,
+			Action: specs.ActAllow,
+		}},
+	}
+	if base != nil {
+		profile.DefaultAction = base.DefaultAction
+		profile.DefaultErrnoRet = base.DefaultErrnoRet
+		profile.Architectures = base.Architectures
+	}
+	return profile
+}
+
+func containsString(list []string, value string) bool {
+	for _, s := range list {
+		if s == value {
+			return true
+		}
+	}
+	return false
+}
+
 // OnExpired calls the provided callback if the internal timer has been
 // expired. It refreshes the timer for each call of this method.
 func (n *Notifier) OnExpired(callback func()) {
@@ -114,7 +162,8 @@ func (c *Config) injectNotifier(
 	if containerID == "" || sandboxAnnotations == nil || msgChan == nil {
 		return nil, nil
 	}
-	if _, ok := sandboxAnnotations[annotations.SeccompNotifierActionAnnotation]; !ok {
+	action, ok := sandboxAnnotations[annotations.SeccompNotifierActionAnnotation]
+	if !ok {
 		return nil, nil
 	}
 
@@ -147,6 +196,10 @@ func (c *Config) injectNotifier(
 		}
 	}
 
+	if action == annotations.SeccompNotifierActionRecord {
+		profile.Syscalls = recordSyscalls(profile.Syscalls)
+	}
+
 	profile.ListenerPath = filepath.Join(c.NotifierPath(), containerID)
 
 	notifier, err := NewNotifier(ctx, msgChan, containerID, profile.ListenerPath, sandboxAnnotations)
@@ -157,6 +210,39 @@ func (c *Config) injectNotifier(
 	return notifier, nil
 }
 
+// recordSyscalls overrides the allowed syscalls to notify the CRI-O server as
+// well, except the recordAllowedSyscalls, which have to stay allowed.
+func recordSyscalls(syscalls []specs.LinuxSyscall) []specs.LinuxSyscall {
+	res := make([]specs.LinuxSyscall, 0, len(syscalls))
+	for _, syscall := range syscalls {
+		if syscall.Action != specs.ActAllow {
+			res = append(res, syscall)
+			continue
+		}
+
+		notify, allow := []string{}, []string{}
+		for _, name := range syscall.Names {
+			if containsString(recordAllowedSyscalls, name) {
+				allow = append(allow, name)
+			} else {
+				notify = append(notify, name)
+			}
+		}
+		if len(notify) > 0 {
+			notifySyscall := syscall
+			notifySyscall.Names = notify
+			notifySyscall.Action = specs.ActNotify
+			res = append(res, notifySyscall)
+		}
+		if len(allow) > 0 {
+			allowSyscall := syscall
+			allowSyscall.Names = allow
+			res = append(res, allowSyscall)
+		}
+	}
+	return res
+}
+
 // NewNotifier starts the notifier for the provided arguments.
 func NewNotifier(
 	ctx context.Context,
@@ -164,6 +250,12 @@ func NewNotifier(
 	containerID, listenerPath string,
 	annotationMap map[string]string,
 ) (*Notifier, error) {
+	action, ok := annotationMap[annotations.SeccompNotifierActionAnnotation]
+	if !ok {
+		return nil, fmt.Errorf("%s annotation not set on container", annotations.SeccompNotifierActionAnnotation)
+	}
+	record := action == annotations.SeccompNotifierActionRecord
+
 	log.Infof(ctx, "Waiting for seccomp file descriptor on container %s", containerID)
 	listener, err := net.Listen("unix", listenerPath)
 	if err != nil {
@@ -204,21 +296,17 @@ func NewNotifier(
 			}
 
 			log.Infof(ctx, "Received new seccomp fd: %v", newFd)
-			go handler(ctx, containerID, msgChan, libseccomp.ScmpFd(newFd))
+			go handler(ctx, containerID, msgChan, libseccomp.ScmpFd(newFd), record)
 		}
 	}()
 
-	action, ok := annotationMap[annotations.SeccompNotifierActionAnnotation]
-	if !ok {
-		return nil, fmt.Errorf("%s annotation not set on container", annotations.SeccompNotifierActionAnnotation)
-	}
-
 	return &Notifier{
 		listener:       listener,
 		syscalls:       sync.Map{},
 		timer:          nil,
 		timeLock:       sync.Mutex{},
 		stopContainers: action == annotations.SeccompNotifierActionStop,
+		record:         record,
 	}, nil
 }
 
@@ -227,11 +315,17 @@ func handler(
 	containerID string,
 	msgChan chan Notification,
 	fd libseccomp.ScmpFd,
+	record bool,
 ) {
 	defer unix.Close(int(fd))
 	for {
 		req, err := libseccomp.NotifReceive(fd)
 		if err != nil {
+			if record {
+				// All processes of the container have exited.
+				log.Debugf(ctx, "Stopping seccomp recording for container %s: %v", containerID, err)
+				return
+			}
 			log.Errorf(ctx, "Unable to receive notification: %v", err)
 			continue
 		}
@@ -239,6 +333,11 @@ func handler(
 		syscall, err := req.Data.Syscall.GetName()
 		if err != nil {
 			log.Errorf(ctx, "Unable to decode syscall %v: %v", req.Data.Syscall, err)
+			// Do not block the process on a syscall we cannot report, but
+			// still deny it if the profile forbids it.
+			if err := respond(fd, req.ID, record); err != nil {
+				log.Errorf(ctx, "Unable to send notification response: %v", err)
+			}
 			continue
 		}
 
@@ -249,21 +348,12 @@ func handler(
 
 		msgChan <- Notification{ctx, containerID, syscall}
 
-		resp := &libseccomp.ScmpNotifResp{
-			ID:    req.ID,
-			Error: int32(unix.ENOSYS),
-			Val:   uint64(0), // -1
-			Flags: 0,
-		}
-
-		// TOCTOU check
-		if err := libseccomp.NotifIDValid(fd, req.ID); err != nil {
-			log.Errorf(ctx, "TOCTOU check failed: req.ID is no longer valid: %v", err)
+		if err := respond(fd, req.ID, record); err != nil {
+			log.Errorf(ctx, "Unable to send notification response: %v", err)
 			continue
 		}
 
-		if err = libseccomp.NotifRespond(fd, resp); err != nil {
-			log.Errorf(ctx, "Unable to send notification response: %v", err)
+		if record {
 			continue
 		}
 
@@ -272,6 +362,36 @@ func handler(
 	}
 }
 
+// respond sends the response to the notification with the provided ID.
+func respond(fd libseccomp.ScmpFd, id uint64, record bool) error {
+	if !record {
+		// TOCTOU check
+		if err := libseccomp.NotifIDValid(fd, id); err != nil {
+			return fmt.Errorf("TOCTOU check failed: req.ID is no longer valid: %w", err)
+		}
+	}
+	return libseccomp.NotifRespond(fd, notifResponse(id, record))
+}
+
+// notifResponse returns the response to the notification with the provided
+// ID. Only in record mode the kernel executes the syscall as it would without
+// the notifier. Otherwise the notifier only receives syscalls which are
+// forbidden by the profile, so they fail with ENOSYS.
+func notifResponse(id uint64, record bool) *libseccomp.ScmpNotifResp {
+	if record {
+		return &libseccomp.ScmpNotifResp{
+			ID:    id,
+			Flags: libseccomp.NotifRespFlagContinue,
+		}
+	}
+	return &libseccomp.ScmpNotifResp{
+		ID:    id,
+		Error: int32(unix.ENOSYS),
+		Val:   uint64(0), // -1
+		Flags: 0,
+	}
+}
+
 func handleNewMessage(sockfd int) (uintptr, error) {
 	const maxNameLen = 16384
 	stateBuf := make([]byte, maxNameLen)
@@ -0,0 +1,84 @@
+//go:build linux && cgo
+// +build linux,cgo
+
+package seccomp_test
+
+import (
+	"github.com/cri-o/cri-o/internal/config/seccomp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	libseccomp "github.com/seccomp/libseccomp-golang"
+	"golang.org/x/sys/unix"
+)
+
+// The actual test suite
+var _ = t.Describe("Notifier", func() {
+	t.Describe("Profile", func() {
+		It("should allow the used syscalls", func() {
+			// Given
+			sut := &seccomp.Notifier{}
+			sut.AddSyscall("mkdir")
+			sut.AddSyscall("chmod")
+			sut.AddSyscall("mkdir")
+
+			// When
+			profile := sut.Profile(&specs.LinuxSeccomp{
+				DefaultAction: specs.ActErrno,
+				Architectures: []specs.Arch{specs.ArchX86_64},
+				Syscalls: []specs.LinuxSyscall{{
+					Names:  []string{"read"},
+					Action: specs.ActNotify,
+				}},
+			})
+
+			// Then
+			Expect(profile.DefaultAction).To(Equal(specs.ActErrno))
+			Expect(profile.Architectures).To(Equal([]specs.Arch{specs.ArchX86_64}))
+			Expect(profile.ListenerPath).To(BeEmpty())
+			Expect(profile.Syscalls).To(HaveLen(1))
+			Expect(profile.Syscalls[0].Action).To(Equal(specs.ActAllow))
+			Expect(profile.Syscalls[0].Names).To(Equal([]string{"chmod", "mkdir", "write"}))
+		})
+
+		It("should use errno as default action without base profile", func() {
+			// Given
+			sut := &seccomp.Notifier{}
+
+			// When
+			profile := sut.Profile(nil)
+
+			// Then
+			Expect(profile.DefaultAction).To(Equal(specs.ActErrno))
+			Expect(profile.Syscalls[0].Names).To(Equal([]string{"write"}))
+		})
+	})
+
+	t.Describe("NotifResponse", func() {
+		It("should deny the syscall outside of record mode", func() {
+			// Given
+			const id = 42
+
+			// When
+			resp := seccomp.NotifResponse(id, false)
+
+			// Then
+			Expect(resp.ID).To(BeEquivalentTo(id))
+			Expect(resp.Flags).To(BeZero())
+			Expect(resp.Error).To(BeEquivalentTo(unix.ENOSYS))
+		})
+
+		It("should continue the syscall in record mode", func() {
+			// Given
+			const id = 42
+
+			// When
+			resp := seccomp.NotifResponse(id, true)
+
+			// Then
+			Expect(resp.ID).To(BeEquivalentTo(id))
+			Expect(resp.Flags).To(Equal(libseccomp.NotifRespFlagContinue))
+			Expect(resp.Error).To(BeZero())
+		})
+	})
+})
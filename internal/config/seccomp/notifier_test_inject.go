@@ -0,0 +1,17 @@
+//go:build test && linux && cgo
+// +build test,linux,cgo
+
+// All *_inject.go files are meant to be used by tests only. Purpose of this
+// files is to provide a way to inject mocked data into the current setup.
+
+package seccomp
+
+import (
+	libseccomp "github.com/seccomp/libseccomp-golang"
+)
+
+// NotifResponse returns the response of the notifier to the notification
+// with the provided ID.
+func NotifResponse(id uint64, record bool) *libseccomp.ScmpNotifResp {
+	return notifResponse(id, record)
+}
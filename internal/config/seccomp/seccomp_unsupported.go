@@ -6,6 +6,7 @@ package seccomp
 import (
 	"context"
 
+	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/opencontainers/runtime-tools/generate"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
@@ -42,6 +43,12 @@ func (c *Config) Setup(
 	return nil, "", nil
 }
 
+// IsDisabled returns true if seccomp is disabled either via the missing
+// `seccomp` buildtag or globally by the system.
+func (c *Config) IsDisabled() bool {
+	return !c.enabled
+}
+
 // SetUseDefaultWhenEmpty uses the default seccomp profile if true is passed as
 // argument, otherwise unconfined.
 func (c *Config) SetUseDefaultWhenEmpty(to bool) {
@@ -99,6 +106,14 @@ func (*Notifier) StopContainers() bool {
 	return false
 }
 
+func (*Notifier) Record() bool {
+	return false
+}
+
+func (*Notifier) Profile(base *specs.LinuxSeccomp) *specs.LinuxSeccomp {
+	return nil
+}
+
 func (*Notifier) OnExpired(callback func()) {
 }
 
@@ -1,17 +1,21 @@
 package criocli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/cri-o/cri-o/internal/config/migrate"
 	"github.com/cri-o/cri-o/pkg/config"
+	"github.com/cri-o/cri-o/pkg/types"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 )
 
 var from string
 
+const showOriginArg = "show-origin"
+
 var ConfigCommand = &cli.Command{
 	Name: "config",
 	Usage: `Outputs a commented version of the configuration file that could be used
@@ -22,6 +26,10 @@ it later with **--config**. Global options will modify the output.`,
 			Name:  "default",
 			Usage: "Output the default configuration (without taking into account any configuration options).",
 		},
+		&cli.BoolFlag{
+			Name:  showOriginArg,
+			Usage: "Output the effective configuration options together with their origin, which is either the default, a configuration file, a drop-in file, a command line flag or an environment variable.",
+		},
 		&cli.StringFlag{
 			Name:        "migrate-defaults",
 			Aliases:     []string{"m"},
@@ -40,10 +48,11 @@ it later with **--config**. Global options will modify the output.`,
     Please note that the migration will overwrite any fields that have changed
     defaults between versions. To save a custom configuration change, it should
     be in a drop-in configuration file instead.
-    Possible values: %q`, migrate.From1_17),
+    Possible values: all versions starting from %q`, migrate.From1_17),
 			Value: migrate.FromPrevious,
 		},
 	},
+	Subcommands: []*cli.Command{migrateCommand},
 	Action: func(c *cli.Context) error {
 		logrus.SetFormatter(&logrus.TextFormatter{
 			DisableTimestamp: true,
@@ -75,7 +84,78 @@ it later with **--config**. Global options will modify the output.`,
 			return err
 		}
 
+		if c.Bool(showOriginArg) {
+			options, err := conf.Options()
+			if err != nil {
+				return err
+			}
+			printConfigOptions(options)
+			return nil
+		}
+
 		// Output the commented config.
 		return conf.WriteTemplate(c.Bool("default"), os.Stdout)
 	},
 }
+
+var migrateCommand = &cli.Command{
+	Name: "migrate",
+	Usage: `Migrate the configuration file selected via the global '--config,-c' command
+line argument from a previous CRI-O version to the current one. Every changed,
+renamed and removed option is printed to stdout. The configuration file itself
+is not modified, but the migrated options can be written into a drop-in
+configuration file.`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "from",
+			Aliases:  []string{"f"},
+			Required: true,
+			Usage: fmt.Sprintf(
+				"The CRI-O version the configuration file has been used with, supported are all versions starting from %s.",
+				migrate.From1_17,
+			),
+		},
+		&cli.StringFlag{
+			Name:      "drop-in",
+			TakesFile: true,
+			Usage:     "Write the migrated options into the provided drop-in configuration file, which should be sorted last in the configuration directory.",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.SetLevel(logrus.WarnLevel)
+
+		path := c.String("config")
+		if path == "" {
+			return errors.New("no configuration file selected via --config")
+		}
+
+		conf, changes, err := migrate.File(path, c.String("from"))
+		if err != nil {
+			return fmt.Errorf("migrate config: %w", err)
+		}
+
+		if len(changes) == 0 {
+			fmt.Println("no changes")
+		}
+		for i := range changes {
+			fmt.Println(changes[i].String())
+		}
+
+		if dropIn := c.String("drop-in"); dropIn != "" {
+			if err := migrate.WriteDropIn(dropIn, conf, changes); err != nil {
+				return fmt.Errorf("write drop-in: %w", err)
+			}
+			fmt.Printf("Wrote migrated options to %s\n", dropIn)
+		}
+
+		return nil
+	},
+}
+
+// printConfigOptions prints the configuration options in the form
+// "origin<TAB>key = value".
+func printConfigOptions(options []types.ConfigOption) {
+	for _, option := range options {
+		fmt.Printf("%s\t%s = %s\n", option.Origin, option.Option, option.Value)
+	}
+}
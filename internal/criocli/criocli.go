@@ -162,6 +162,9 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("seccomp-profile") {
 		config.SeccompProfile = ctx.String("seccomp-profile")
 	}
+	if ctx.IsSet("seccomp-profile-record-dir") {
+		config.SeccompProfileRecordDir = ctx.String("seccomp-profile-record-dir")
+	}
 	if ctx.IsSet("seccomp-use-default-when-empty") {
 		config.SeccompUseDefaultWhenEmpty = ctx.Bool("seccomp-use-default-when-empty")
 	}
@@ -387,6 +390,36 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("big-files-temporary-dir") {
 		config.BigFilesTemporaryDir = ctx.String("big-files-temporary-dir")
 	}
+	if ctx.IsSet("image-gc-high-threshold-percent") {
+		config.ImageGCHighThresholdPercent = ctx.Int("image-gc-high-threshold-percent")
+	}
+	if ctx.IsSet("image-gc-low-threshold-percent") {
+		config.ImageGCLowThresholdPercent = ctx.Int("image-gc-low-threshold-percent")
+	}
+	if ctx.IsSet("image-gc-min-age") {
+		config.ImageGCMinAge = ctx.Duration("image-gc-min-age")
+	}
+	if ctx.IsSet("image-gc-max-age") {
+		config.ImageGCMaxAge = ctx.Duration("image-gc-max-age")
+	}
+	if ctx.IsSet("image-gc-interval") {
+		config.ImageGCInterval = ctx.Duration("image-gc-interval")
+	}
+	if ctx.IsSet("max-concurrent-pulls") {
+		config.MaxConcurrentPulls = ctx.Int("max-concurrent-pulls")
+	}
+	if ctx.IsSet("max-concurrent-pulls-per-registry") {
+		config.MaxConcurrentPullsPerRegistry = ctx.Int("max-concurrent-pulls-per-registry")
+	}
+	if ctx.IsSet("max-concurrent-pulls-per-namespace") {
+		config.MaxConcurrentPullsPerNamespace = ctx.Int("max-concurrent-pulls-per-namespace")
+	}
+	if ctx.IsSet("registry-failure-threshold") {
+		config.RegistryFailureThreshold = ctx.Int("registry-failure-threshold")
+	}
+	if ctx.IsSet("registry-circuit-breaker-cooldown") {
+		config.RegistryCircuitBreakerCooldown = ctx.Duration("registry-circuit-breaker-cooldown")
+	}
 	if ctx.IsSet("separate-pull-cgroup") {
 		config.SeparatePullCgroup = ctx.String("separate-pull-cgroup")
 	}
@@ -399,6 +432,27 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("enable-pod-events") {
 		config.EnablePodEvents = ctx.Bool("enable-pod-events")
 	}
+	if ctx.IsSet("container-events-journal-size") {
+		config.ContainerEventsJournalSize = ctx.Int("container-events-journal-size")
+	}
+	if ctx.IsSet("audit-log") {
+		config.AuditLog = ctx.String("audit-log")
+	}
+	if ctx.IsSet("audit-log-journald-identifier") {
+		config.AuditLogJournaldIdentifier = ctx.String("audit-log-journald-identifier")
+	}
+	if ctx.IsSet("audit-log-max-size") {
+		config.AuditLogMaxSize = ctx.Int64("audit-log-max-size")
+	}
+	if ctx.IsSet("audit-log-max-files") {
+		config.AuditLogMaxFiles = ctx.Int("audit-log-max-files")
+	}
+	if ctx.IsSet("audit-log-operations") {
+		config.AuditLogOperations = StringSliceTrySplit(ctx, "audit-log-operations")
+	}
+	if ctx.IsSet("audit-log-namespaces") {
+		config.AuditLogNamespaces = StringSliceTrySplit(ctx, "audit-log-namespaces")
+	}
 	if ctx.IsSet("hostnetwork-disable-selinux") {
 		config.HostNetworkDisableSELinux = ctx.Bool("hostnetwork-disable-selinux")
 	}
@@ -408,6 +462,8 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("disable-hostport-mapping") {
 		config.DisableHostPortMapping = ctx.Bool("disable-hostport-mapping")
 	}
+
+	setFlagOrigins(config, ctx)
 	return nil
 }
 
@@ -640,6 +696,12 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			EnvVars:   []string{"CONTAINER_SECCOMP_PROFILE"},
 			TakesFile: true,
 		},
+		&cli.StringFlag{
+			Name:      "seccomp-profile-record-dir",
+			Usage:     "Directory where the seccomp profiles recorded by the seccomp notifier action \"record\" get written to on container exit.",
+			EnvVars:   []string{"CONTAINER_SECCOMP_PROFILE_RECORD_DIR"},
+			TakesFile: true,
+		},
 		&cli.BoolFlag{
 			Name:    "seccomp-use-default-when-empty",
 			Usage:   "Use the default seccomp profile when an empty one is specified. This option is currently deprecated, and will be replaced by the SeccompDefault FeatureGate in Kubernetes.",
@@ -902,6 +964,66 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			EnvVars: []string{"CONTAINER_BIG_FILES_TEMPORARY_DIR"},
 			Value:   defConf.BigFilesTemporaryDir,
 		},
+		&cli.IntFlag{
+			Name:    "image-gc-high-threshold-percent",
+			Usage:   "Disk usage of the storage root in percent, which triggers the image garbage collection. The value 0 disables the disk usage based image garbage collection.",
+			EnvVars: []string{"CONTAINER_IMAGE_GC_HIGH_THRESHOLD_PERCENT"},
+			Value:   defConf.ImageGCHighThresholdPercent,
+		},
+		&cli.IntFlag{
+			Name:    "image-gc-low-threshold-percent",
+			Usage:   "Disk usage of the storage root in percent, which the image garbage collection tries to reach once triggered.",
+			EnvVars: []string{"CONTAINER_IMAGE_GC_LOW_THRESHOLD_PERCENT"},
+			Value:   defConf.ImageGCLowThresholdPercent,
+		},
+		&cli.DurationFlag{
+			Name:    "image-gc-min-age",
+			Usage:   "Minimum time an image has to be unused before it can be removed by the image garbage collection.",
+			EnvVars: []string{"CONTAINER_IMAGE_GC_MIN_AGE"},
+			Value:   defConf.ImageGCMinAge,
+		},
+		&cli.DurationFlag{
+			Name:    "image-gc-max-age",
+			Usage:   "Maximum time an image can be unused before it gets removed by the image garbage collection. The value 0 disables the age based image garbage collection.",
+			EnvVars: []string{"CONTAINER_IMAGE_GC_MAX_AGE"},
+			Value:   defConf.ImageGCMaxAge,
+		},
+		&cli.DurationFlag{
+			Name:    "image-gc-interval",
+			Usage:   "Interval in which the image garbage collection runs.",
+			EnvVars: []string{"CONTAINER_IMAGE_GC_INTERVAL"},
+			Value:   defConf.ImageGCInterval,
+		},
+		&cli.IntFlag{
+			Name:    "max-concurrent-pulls",
+			Usage:   "Maximum number of image pulls running in parallel on the node. The value 0 disables the limit.",
+			EnvVars: []string{"CONTAINER_MAX_CONCURRENT_PULLS"},
+			Value:   defConf.MaxConcurrentPulls,
+		},
+		&cli.IntFlag{
+			Name:    "max-concurrent-pulls-per-registry",
+			Usage:   "Maximum number of image pulls running in parallel from a single registry host. The value 0 disables the limit.",
+			EnvVars: []string{"CONTAINER_MAX_CONCURRENT_PULLS_PER_REGISTRY"},
+			Value:   defConf.MaxConcurrentPullsPerRegistry,
+		},
+		&cli.IntFlag{
+			Name:    "max-concurrent-pulls-per-namespace",
+			Usage:   "Maximum number of image pulls running in parallel for a single Kubernetes namespace. The value 0 disables the limit.",
+			EnvVars: []string{"CONTAINER_MAX_CONCURRENT_PULLS_PER_NAMESPACE"},
+			Value:   defConf.MaxConcurrentPullsPerNamespace,
+		},
+		&cli.IntFlag{
+			Name:    "registry-failure-threshold",
+			Usage:   "Number of consecutive failed requests to a registry, after which it is skipped by image pulls for the registry-circuit-breaker-cooldown. The value 0 disables skipping unhealthy registries.",
+			EnvVars: []string{"CONTAINER_REGISTRY_FAILURE_THRESHOLD"},
+			Value:   defConf.RegistryFailureThreshold,
+		},
+		&cli.DurationFlag{
+			Name:    "registry-circuit-breaker-cooldown",
+			Usage:   "Time an unhealthy registry is skipped by image pulls.",
+			EnvVars: []string{"CONTAINER_REGISTRY_CIRCUIT_BREAKER_COOLDOWN"},
+			Value:   defConf.RegistryCircuitBreakerCooldown,
+		},
 		&cli.BoolFlag{
 			Name:    "read-only",
 			Usage:   "Setup all unprivileged containers to run as read-only. Automatically mounts the containers' tmpfs on '/run', '/tmp' and '/var/tmp'.",
@@ -1141,6 +1263,49 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			Usage:   "If true, CRI-O starts sending the container events to the kubelet",
 			EnvVars: []string{"ENABLE_POD_EVENTS"},
 		},
+		&cli.IntFlag{
+			Name:    "container-events-journal-size",
+			Usage:   "Maximum number of container events kept in the journal below the run root, which allows clients to replay missed events. Set to 0 to disable the journal.",
+			Value:   defConf.ContainerEventsJournalSize,
+			EnvVars: []string{"CONTAINER_EVENTS_JOURNAL_SIZE"},
+		},
+		&cli.StringFlag{
+			Name:      "audit-log",
+			Usage:     `Destination of the audit records of exec, exec_sync, attach and port forward sessions, which is either an absolute file path or "journald". Set to an empty string to disable the audit log.`,
+			Value:     defConf.AuditLog,
+			EnvVars:   []string{"CONTAINER_AUDIT_LOG"},
+			TakesFile: true,
+		},
+		&cli.StringFlag{
+			Name:    "audit-log-journald-identifier",
+			Usage:   "Syslog identifier of the audit records written to journald.",
+			Value:   defConf.AuditLogJournaldIdentifier,
+			EnvVars: []string{"CONTAINER_AUDIT_LOG_JOURNALD_IDENTIFIER"},
+		},
+		&cli.Int64Flag{
+			Name:    "audit-log-max-size",
+			Usage:   "Size in bytes after which the audit log file gets rotated. Set to 0 to disable the rotation.",
+			Value:   defConf.AuditLogMaxSize,
+			EnvVars: []string{"CONTAINER_AUDIT_LOG_MAX_SIZE"},
+		},
+		&cli.IntFlag{
+			Name:    "audit-log-max-files",
+			Usage:   "Number of rotated audit log files to keep.",
+			Value:   defConf.AuditLogMaxFiles,
+			EnvVars: []string{"CONTAINER_AUDIT_LOG_MAX_FILES"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "audit-log-operations",
+			Usage:   `List of audited operations, which can be "exec", "exec_sync", "attach" and "port_forward". All operations are audited if empty.`,
+			Value:   cli.NewStringSlice(defConf.AuditLogOperations...),
+			EnvVars: []string{"CONTAINER_AUDIT_LOG_OPERATIONS"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "audit-log-namespaces",
+			Usage:   "List of pod namespaces to be audited. All pods are audited if empty.",
+			Value:   cli.NewStringSlice(defConf.AuditLogNamespaces...),
+			EnvVars: []string{"CONTAINER_AUDIT_LOG_NAMESPACES"},
+		},
 		&cli.StringFlag{
 			Name:  "irqbalance-config-restore-file",
 			Value: defConf.IrqBalanceConfigRestoreFile,
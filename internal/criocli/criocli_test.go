@@ -159,4 +159,52 @@ var _ = t.Describe("CLI Flags", func() {
 		// Then
 		Expect(config.RuntimeConfig.DisableHostPortMapping).To(Equal(true))
 	})
+
+	It("Flag test origin of log-level", func() {
+		// Default Config
+		app.Flags, app.Metadata, err = criocli.GetFlagsAndMetadata()
+		Expect(err).To(BeNil())
+		config, err := criocli.GetConfigFromContext(ctx)
+		Expect(err).To(BeNil())
+
+		// Then
+		Expect(config.OptionOrigin("crio.runtime.log_level").String()).To(Equal("default"))
+
+		// Set Config & Merge
+		setFlag := &cli.StringFlag{
+			Name:       "log-level",
+			Value:      "debug",
+			HasBeenSet: true,
+		}
+		err = setFlag.Apply(flagSet)
+		Expect(err).To(BeNil())
+		ctx.Command.Flags = append(commandFlags, setFlag)
+		config, err = criocli.GetAndMergeConfigFromContext(ctx)
+		Expect(err).To(BeNil())
+
+		// Then
+		Expect(config.OptionOrigin("crio.runtime.log_level").String()).To(Equal("flag:--log-level"))
+	})
+
+	It("Flag test origin of log-level from environment", func() {
+		// Default Config
+		app.Flags, app.Metadata, err = criocli.GetFlagsAndMetadata()
+		Expect(err).To(BeNil())
+
+		// Set Config & Merge
+		GinkgoT().Setenv("CONTAINER_LOG_LEVEL", "debug")
+		setFlag := &cli.StringFlag{
+			Name:    "log-level",
+			EnvVars: []string{"CONTAINER_LOG_LEVEL"},
+		}
+		err = setFlag.Apply(flagSet)
+		Expect(err).To(BeNil())
+		ctx.Command.Flags = append(commandFlags, setFlag)
+		config, err := criocli.GetAndMergeConfigFromContext(ctx)
+		Expect(err).To(BeNil())
+
+		// Then
+		Expect(config.LogLevel).To(Equal("debug"))
+		Expect(config.OptionOrigin("crio.runtime.log_level").String()).To(Equal("env:CONTAINER_LOG_LEVEL"))
+	})
 })
@@ -0,0 +1,184 @@
+package criocli
+
+import (
+	"os"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	libconfig "github.com/cri-o/cri-o/pkg/config"
+	"github.com/urfave/cli/v2"
+)
+
+// flagConfigKeys maps the command line flags to the TOML key of the
+// configuration option they override.
+var flagConfigKeys = map[string]string{
+	"conmon":                                 "crio.runtime.conmon",
+	"pause-command":                          "crio.image.pause_command",
+	"pause-image":                            "crio.image.pause_image",
+	"pause-image-auth-file":                  "crio.image.pause_image_auth_file",
+	"global-auth-file":                       "crio.image.global_auth_file",
+	"signature-policy":                       "crio.image.signature_policy",
+	"signature-policy-dir":                   "crio.image.signature_policy_dir",
+	"root":                                   "crio.root",
+	"runroot":                                "crio.runroot",
+	"storage-driver":                         "crio.storage_driver",
+	"storage-opt":                            "crio.storage_option",
+	"insecure-registry":                      "crio.image.insecure_registries",
+	"registry":                               "crio.image.registries",
+	"default-transport":                      "crio.image.default_transport",
+	"listen":                                 "crio.api.listen",
+	"stream-address":                         "crio.api.stream_address",
+	"stream-port":                            "crio.api.stream_port",
+	"default-runtime":                        "crio.runtime.default_runtime",
+	"decryption-keys-path":                   "crio.runtime.decryption_keys_path",
+	"selinux":                                "crio.runtime.selinux",
+	"imagestore":                             "crio.imagestore",
+	"seccomp-profile":                        "crio.runtime.seccomp_profile",
+	"seccomp-profile-record-dir":             "crio.runtime.seccomp_profile_record_dir",
+	"seccomp-use-default-when-empty":         "crio.runtime.seccomp_use_default_when_empty",
+	"apparmor-profile":                       "crio.runtime.apparmor_profile",
+	"blockio-config-file":                    "crio.runtime.blockio_config_file",
+	"blockio-reload":                         "crio.runtime.blockio_reload",
+	"irqbalance-config-file":                 "crio.runtime.irqbalance_config_file",
+	"rdt-config-file":                        "crio.runtime.rdt_config_file",
+	"cgroup-manager":                         "crio.runtime.cgroup_manager",
+	"conmon-cgroup":                          "crio.runtime.conmon_cgroup",
+	"hooks-dir":                              "crio.runtime.hooks_dir",
+	"default-mounts-file":                    "crio.runtime.default_mounts_file",
+	"default-capabilities":                   "crio.runtime.default_capabilities",
+	"add-inheritable-capabilities":           "crio.runtime.add_inheritable_capabilities",
+	"default-sysctls":                        "crio.runtime.default_sysctls",
+	"default-ulimits":                        "crio.runtime.default_ulimits",
+	"pids-limit":                             "crio.runtime.pids_limit",
+	"log-size-max":                           "crio.runtime.log_size_max",
+	"log-journald":                           "crio.runtime.log_to_journald",
+	"cni-default-network":                    "crio.network.cni_default_network",
+	"cni-config-dir":                         "crio.network.network_dir",
+	"cni-plugin-dir":                         "crio.network.plugin_dirs",
+	"image-volumes":                          "crio.image.image_volumes",
+	"read-only":                              "crio.runtime.read_only",
+	"bind-mount-prefix":                      "crio.runtime.bind_mount_prefix",
+	"uid-mappings":                           "crio.runtime.uid_mappings",
+	"minimum-mappable-uid":                   "crio.runtime.minimum_mappable_uid",
+	"gid-mappings":                           "crio.runtime.gid_mappings",
+	"minimum-mappable-gid":                   "crio.runtime.minimum_mappable_gid",
+	"log-level":                              "crio.runtime.log_level",
+	"log-filter":                             "crio.runtime.log_filter",
+	"log-dir":                                "crio.log_dir",
+	"additional-devices":                     "crio.runtime.additional_devices",
+	"allowed-devices":                        "crio.runtime.allowed_devices",
+	"cdi-spec-dirs":                          "crio.runtime.cdi_spec_dirs",
+	"device-ownership-from-security-context": "crio.runtime.device_ownership_from_security_context",
+	"conmon-env":                             "crio.runtime.conmon_env",
+	"default-env":                            "crio.runtime.default_env",
+	"container-attach-socket-dir":            "crio.runtime.container_attach_socket_dir",
+	"container-exits-dir":                    "crio.runtime.container_exits_dir",
+	"enable-criu-support":                    "crio.runtime.enable_criu_support",
+	"ctr-stop-timeout":                       "crio.runtime.ctr_stop_timeout",
+	"grpc-max-recv-msg-size":                 "crio.api.grpc_max_recv_msg_size",
+	"grpc-max-send-msg-size":                 "crio.api.grpc_max_send_msg_size",
+	"drop-infra-ctr":                         "crio.runtime.drop_infra_ctr",
+	"namespaces-dir":                         "crio.runtime.namespaces_dir",
+	"pinns-path":                             "crio.runtime.pinns_path",
+	"no-pivot":                               "crio.runtime.no_pivot",
+	"stream-enable-tls":                      "crio.api.stream_enable_tls",
+	"stream-tls-ca":                          "crio.api.stream_tls_ca",
+	"stream-tls-cert":                        "crio.api.stream_tls_cert",
+	"stream-tls-key":                         "crio.api.stream_tls_key",
+	"stream-idle-timeout":                    "crio.api.stream_idle_timeout",
+	"version-file":                           "crio.version_file",
+	"version-file-persist":                   "crio.version_file_persist",
+	"clean-shutdown-file":                    "crio.clean_shutdown_file",
+	"absent-mount-sources-to-reject":         "crio.runtime.absent_mount_sources_to_reject",
+	"irqbalance-config-restore-file":         "crio.runtime.irqbalance_config_restore_file",
+	"internal-wipe":                          "crio.internal_wipe",
+	"internal-repair":                        "crio.internal_repair",
+	"enable-metrics":                         "crio.metrics.enable_metrics",
+	"metrics-port":                           "crio.metrics.metrics_port",
+	"metrics-socket":                         "crio.metrics.metrics_socket",
+	"metrics-cert":                           "crio.metrics.metrics_cert",
+	"metrics-key":                            "crio.metrics.metrics_key",
+	"metrics-collectors":                     "crio.metrics.metrics_collectors",
+	"enable-tracing":                         "crio.tracing.enable_tracing",
+	"tracing-endpoint":                       "crio.tracing.tracing_endpoint",
+	"tracing-sampling-rate-per-million":      "crio.tracing.tracing_sampling_rate_per_million",
+	"enable-nri":                             "crio.nri.enable_nri",
+	"nri-listen":                             "crio.nri.nri_listen",
+	"nri-plugin-dir":                         "crio.nri.nri_plugin_dir",
+	"nri-plugin-config-dir":                  "crio.nri.nri_plugin_config_dir",
+	"nri-disable-connections":                "crio.nri.nri_disable_connections",
+	"nri-plugin-registration-timeout":        "crio.nri.nri_plugin_registration_timeout",
+	"nri-plugin-request-timeout":             "crio.nri.nri_plugin_request_timeout",
+	"big-files-temporary-dir":                "crio.image.big_files_temporary_dir",
+	"image-gc-high-threshold-percent":        "crio.image.image_gc_high_threshold_percent",
+	"image-gc-low-threshold-percent":         "crio.image.image_gc_low_threshold_percent",
+	"image-gc-min-age":                       "crio.image.image_gc_min_age",
+	"image-gc-max-age":                       "crio.image.image_gc_max_age",
+	"image-gc-interval":                      "crio.image.image_gc_interval",
+	"max-concurrent-pulls":                   "crio.image.max_concurrent_pulls",
+	"max-concurrent-pulls-per-registry":      "crio.image.max_concurrent_pulls_per_registry",
+	"max-concurrent-pulls-per-namespace":     "crio.image.max_concurrent_pulls_per_namespace",
+	"registry-failure-threshold":             "crio.image.registry_failure_threshold",
+	"registry-circuit-breaker-cooldown":      "crio.image.registry_circuit_breaker_cooldown",
+	"separate-pull-cgroup":                   "crio.runtime.separate_pull_cgroup",
+	"infra-ctr-cpuset":                       "crio.runtime.infra_ctr_cpuset",
+	"stats-collection-period":                "crio.stats.stats_collection_period",
+	"enable-pod-events":                      "crio.runtime.enable_pod_events",
+	"container-events-journal-size":          "crio.runtime.container_events_journal_size",
+	"audit-log":                              "crio.runtime.audit_log",
+	"audit-log-journald-identifier":          "crio.runtime.audit_log_journald_identifier",
+	"audit-log-max-size":                     "crio.runtime.audit_log_max_size",
+	"audit-log-max-files":                    "crio.runtime.audit_log_max_files",
+	"audit-log-operations":                   "crio.runtime.audit_log_operations",
+	"audit-log-namespaces":                   "crio.runtime.audit_log_namespaces",
+	"hostnetwork-disable-selinux":            "crio.runtime.hostnetwork_disable_selinux",
+	"pinned-images":                          "crio.image.pinned_images",
+	"disable-hostport-mapping":               "crio.runtime.disable_hostport_mapping",
+}
+
+// setFlagOrigins records the origin of all configuration options overridden by
+// the command line flags or their environment variables.
+func setFlagOrigins(config *libconfig.Config, ctx *cli.Context) {
+	for name, key := range flagConfigKeys {
+		if ctx.IsSet(name) {
+			config.SetOptionOrigin(key, flagOrigin(ctx, name))
+		}
+	}
+
+	if ctx.IsSet("runtimes") {
+		origin := flagOrigin(ctx, "runtimes")
+		for _, r := range StringSliceTrySplit(ctx, "runtimes") {
+			name, _, _ := strings.Cut(r, ":")
+			config.SetOptionOrigin(toml.Key{"crio", "runtime", "runtimes", name}.String(), origin)
+		}
+	}
+}
+
+// flagOrigin returns the origin of the set flag with the provided name, which
+// is its environment variable if the value has been taken from there.
+func flagOrigin(ctx *cli.Context, name string) libconfig.Origin {
+	flags := []cli.Flag{}
+	if ctx.App != nil {
+		flags = append(flags, ctx.App.Flags...)
+	}
+	if ctx.Command != nil {
+		flags = append(flags, ctx.Command.Flags...)
+	}
+
+	for _, flag := range flags {
+		if flag.Names()[0] != name || !flag.IsSet() {
+			continue
+		}
+		envFlag, ok := flag.(cli.DocGenerationFlag)
+		if !ok {
+			break
+		}
+		for _, env := range envFlag.GetEnvVars() {
+			if _, ok := os.LookupEnv(env); ok {
+				return libconfig.Origin{Kind: libconfig.OriginEnv, Source: env}
+			}
+		}
+	}
+
+	return libconfig.Origin{Kind: libconfig.OriginFlag, Source: "--" + name}
+}
@@ -3,6 +3,7 @@ package criocli
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/cri-o/cri-o/internal/client"
 
@@ -12,6 +13,11 @@ import (
 const (
 	defaultSocket = "/var/run/crio/crio.sock"
 	idArg         = "id"
+	abortArg      = "abort"
+	dryRunArg     = "dry-run"
+	followArg     = "follow"
+	seqArg        = "seq"
+	sinceArg      = "since"
 	socketArg     = "socket"
 )
 
@@ -36,8 +42,12 @@ var StatusCommand = &cli.Command{
 	Subcommands: []*cli.Command{{
 		Action:  configSubCommand,
 		Aliases: []string{"c"},
-		Name:    "config",
-		Usage:   "Show the configuration of CRI-O as a TOML string.",
+		Flags: []cli.Flag{&cli.BoolFlag{
+			Name:  showOriginArg,
+			Usage: "show the effective configuration options together with their origin",
+		}},
+		Name:  "config",
+		Usage: "Show the configuration of CRI-O as a TOML string.",
 	}, {
 		Action:  containers,
 		Aliases: []string{"container", "cs", "s"},
@@ -53,6 +63,46 @@ var StatusCommand = &cli.Command{
 		Aliases: []string{"i"},
 		Name:    "info",
 		Usage:   "Retrieve generic information about CRI-O, such as the cgroup and storage driver.",
+	}, {
+		Action:  pulls,
+		Aliases: []string{"pull", "p"},
+		Flags: []cli.Flag{&cli.StringFlag{
+			Name:    abortArg,
+			Aliases: []string{"a"},
+			Usage:   "abort the image pull with the provided ID for all waiting callers",
+		}},
+		Name:  "pulls",
+		Usage: "Display the image pulls in progress including their per layer progress.",
+	}, {
+		Action:  registries,
+		Aliases: []string{"registry", "r"},
+		Name:    "registries",
+		Usage:   "Display the health of the registries used for image pulls.",
+	}, {
+		Action:  containerEvents,
+		Aliases: []string{"event", "e"},
+		Flags: []cli.Flag{&cli.Uint64Flag{
+			Name:  seqArg,
+			Usage: "only display the events having a greater sequence number",
+		}, &cli.StringFlag{
+			Name:  sinceArg,
+			Usage: "only display the events created since the provided duration (e.g. 10m) or RFC 3339 timestamp",
+		}, &cli.BoolFlag{
+			Name:    followArg,
+			Aliases: []string{"f"},
+			Usage:   "keep displaying new events as they get recorded",
+		}},
+		Name:  "events",
+		Usage: "Display the container events recorded in the journal.",
+	}, {
+		Action: reloadConfig,
+		Flags: []cli.Flag{&cli.BoolFlag{
+			Name:    dryRunArg,
+			Aliases: []string{"n"},
+			Usage:   "only validate the configuration and display the changes without applying them",
+		}},
+		Name:  "reload",
+		Usage: "Reload the configuration of CRI-O and display the applied changes.",
 	}},
 }
 
@@ -62,6 +112,15 @@ func configSubCommand(c *cli.Context) error {
 		return err
 	}
 
+	if c.Bool(showOriginArg) {
+		options, err := crioClient.ConfigOptions()
+		if err != nil {
+			return err
+		}
+		printConfigOptions(options)
+		return nil
+	}
+
 	info, err := crioClient.ConfigInfo()
 	if err != nil {
 		return err
@@ -127,6 +186,9 @@ func info(c *cli.Context) error {
 	fmt.Printf("storage driver: %s\n", info.StorageDriver)
 	fmt.Printf("storage graph root: %s\n", info.StorageRoot)
 	fmt.Printf("storage image: %s\n", info.StorageImage)
+	if len(info.PodCIDRs) > 0 {
+		fmt.Printf("pod CIDRs: %s\n", strings.Join(info.PodCIDRs, ", "))
+	}
 
 	fmt.Printf("default GID mappings (format <container>:<host>:<size>):\n")
 	for _, m := range info.DefaultIDMappings.Gids {
@@ -140,6 +202,150 @@ func info(c *cli.Context) error {
 	return nil
 }
 
+func pulls(c *cli.Context) error {
+	crioClient, err := crioClient(c)
+	if err != nil {
+		return err
+	}
+
+	if id := c.String(abortArg); id != "" {
+		if err := crioClient.AbortPull(id); err != nil {
+			return err
+		}
+		fmt.Printf("aborted pull %s\n", id)
+		return nil
+	}
+
+	pulls, err := crioClient.PullsInfo()
+	if err != nil {
+		return err
+	}
+
+	for _, pull := range pulls {
+		fmt.Printf("id: %s\n", pull.ID)
+		fmt.Printf("image: %s\n", pull.Image)
+		if pull.Namespace != "" {
+			fmt.Printf("namespace: %s\n", pull.Namespace)
+		}
+		fmt.Printf("started: %v\n", time.Unix(0, pull.StartedAt))
+		fmt.Printf("waiters: %d\n", pull.Waiters)
+		if pull.Aborted {
+			fmt.Printf("aborted: true\n")
+		}
+		fmt.Printf("layers:\n")
+		for _, layer := range pull.Layers {
+			status := "pulling"
+			switch {
+			case layer.Skipped:
+				status = "skipped"
+			case layer.Done:
+				status = "done"
+			}
+			if layer.Size > 0 {
+				fmt.Printf("  %s: %s %d/%d bytes (%.2f%%)\n", layer.Digest, status, layer.Offset, layer.Size,
+					float64(layer.Offset)/float64(layer.Size)*100)
+			} else {
+				fmt.Printf("  %s: %s %d bytes\n", layer.Digest, status, layer.Offset)
+			}
+		}
+	}
+
+	return nil
+}
+
+func registries(c *cli.Context) error {
+	crioClient, err := crioClient(c)
+	if err != nil {
+		return err
+	}
+
+	registries, err := crioClient.RegistriesInfo()
+	if err != nil {
+		return err
+	}
+
+	for _, registry := range registries {
+		state := "healthy"
+		switch {
+		case registry.CircuitOpen:
+			state = fmt.Sprintf("unhealthy, skipped until %v", time.Unix(0, registry.OpenUntil))
+		case !registry.Healthy:
+			state = "degraded"
+		}
+		fmt.Printf("registry: %s\n", registry.Registry)
+		fmt.Printf("state: %s\n", state)
+		fmt.Printf("consecutive failures: %d\n", registry.ConsecutiveFailures)
+		fmt.Printf("total failures: %d\n", registry.TotalFailures)
+		fmt.Printf("total successes: %d\n", registry.TotalSuccesses)
+		if registry.LastFailure != 0 {
+			fmt.Printf("last failure: %v\n", time.Unix(0, registry.LastFailure))
+			fmt.Printf("last error: %s\n", registry.LastError)
+		}
+	}
+
+	return nil
+}
+
+func containerEvents(c *cli.Context) error {
+	crioClient, err := crioClient(c)
+	if err != nil {
+		return err
+	}
+
+	seq := c.Uint64(seqArg)
+	since := c.String(sinceArg)
+	if duration, err := time.ParseDuration(since); err == nil {
+		since = time.Now().Add(-duration).Format(time.RFC3339Nano)
+	}
+
+	for {
+		events, err := crioClient.ContainerEvents(seq, since)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			pod := event.SandboxID
+			if event.PodName != "" {
+				pod = event.PodNamespace + "/" + event.PodName
+			}
+			fmt.Printf("%d %s %s container=%s pod=%s\n",
+				event.Seq, time.Unix(0, event.CreatedAt).Format(time.RFC3339Nano),
+				event.EventType, event.ContainerID, pod)
+			seq = event.Seq
+		}
+
+		if !c.Bool(followArg) {
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+func reloadConfig(c *cli.Context) error {
+	crioClient, err := crioClient(c)
+	if err != nil {
+		return err
+	}
+
+	reload, err := crioClient.ReloadConfig(c.Bool(dryRunArg))
+	if err != nil {
+		return err
+	}
+
+	if len(reload.Changes) == 0 {
+		fmt.Println("no changes")
+	}
+	for _, change := range reload.Changes {
+		fmt.Printf("%s: %q -> %q\n", change.Option, change.Old, change.New)
+	}
+	if reload.DryRun {
+		fmt.Println("dry run, no changes applied")
+	}
+
+	return nil
+}
+
 func crioClient(c *cli.Context) (client.CrioClient, error) {
 	return client.New(c.String(socketArg))
 }
@@ -0,0 +1,241 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/google/renameio"
+	"github.com/sirupsen/logrus"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// Entry is a container event recorded in the journal.
+type Entry struct {
+	// Seq is the sequence number of the event, which is strictly increasing
+	// over the lifetime of the journal.
+	Seq uint64
+	// Event is the recorded container event.
+	Event *types.ContainerEventResponse
+}
+
+// record is the on-disk representation of an entry. Every record is stored as
+// a single JSON line, where the event is the protobuf encoded CRI message.
+type record struct {
+	Seq   uint64 `json:"seq"`
+	Event []byte `json:"event"`
+}
+
+// Journal is a bounded ring of container events which is persisted to disk.
+// It keeps the latest events in memory and appends every event to the journal
+// file. The file gets compacted to the events of the ring as soon as it
+// contains twice as many records as the ring, which bounds its size.
+type Journal struct {
+	path string
+	size int
+
+	mutex sync.Mutex
+	file  *os.File
+	// ring contains the latest events, where start is the index of the
+	// oldest one.
+	ring    []Entry
+	start   int
+	records int
+	nextSeq uint64
+}
+
+// Open opens the journal at path, which keeps at most size events. Existing
+// events of the journal file are loaded, so that the sequence numbers continue
+// across restarts.
+func Open(path string, size int) (*Journal, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid journal size %d", size)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("create journal directory: %w", err)
+	}
+
+	j := &Journal{
+		path:    path,
+		size:    size,
+		ring:    make([]Entry, 0, size),
+		nextSeq: 1,
+	}
+	if err := j.load(); err != nil {
+		return nil, fmt.Errorf("load journal %s: %w", path, err)
+	}
+	// Rewrite the file once to drop outdated or partially written records.
+	if err := j.compact(); err != nil {
+		return nil, fmt.Errorf("compact journal %s: %w", path, err)
+	}
+	return j, nil
+}
+
+func (j *Journal) load() error {
+	content, err := os.ReadFile(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(nil, len(content)+1)
+	for scanner.Scan() {
+		entry, err := unmarshal(scanner.Bytes())
+		if err != nil {
+			logrus.Warnf("Skipping invalid record of container events journal %s: %v", j.path, err)
+			continue
+		}
+		if entry.Seq < j.nextSeq {
+			continue
+		}
+		j.push(entry)
+		j.nextSeq = entry.Seq + 1
+	}
+	return scanner.Err()
+}
+
+// Append records the event and returns its sequence number.
+func (j *Journal) Append(event *types.ContainerEventResponse) (uint64, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if j.file == nil {
+		return 0, errors.New("journal is closed")
+	}
+
+	seq := j.nextSeq
+	line, err := marshal(Entry{Seq: seq, Event: event})
+	if err != nil {
+		return 0, err
+	}
+	// Keep a copy, because the caller may reuse the event.
+	recorded, err := unmarshal(line)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := j.file.Write(line); err != nil {
+		return 0, fmt.Errorf("write journal %s: %w", j.path, err)
+	}
+	j.nextSeq++
+	j.records++
+	j.push(recorded)
+
+	if j.records >= 2*j.size {
+		if err := j.compact(); err != nil {
+			return seq, fmt.Errorf("compact journal %s: %w", j.path, err)
+		}
+	}
+	return seq, nil
+}
+
+// Since returns the recorded events having a sequence number greater than seq
+// and which have been created at or after the provided time in nanoseconds.
+// A zero value disables the corresponding filter.
+func (j *Journal) Since(seq uint64, createdAt int64) []Entry {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	entries := []Entry{}
+	for i := 0; i < len(j.ring); i++ {
+		entry := j.ring[(j.start+i)%len(j.ring)]
+		if entry.Seq <= seq || entry.Event.CreatedAt < createdAt {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// LastSeq returns the sequence number of the latest event, or zero if the
+// journal is empty.
+func (j *Journal) LastSeq() uint64 {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.nextSeq - 1
+}
+
+// Close closes the journal file. Further appends will fail.
+func (j *Journal) Close() error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// push adds the entry to the ring and overwrites the oldest entry if the ring
+// is full.
+func (j *Journal) push(entry Entry) {
+	if len(j.ring) < j.size {
+		j.ring = append(j.ring, entry)
+		return
+	}
+	j.ring[j.start] = entry
+	j.start = (j.start + 1) % j.size
+}
+
+// compact replaces the journal file by the entries of the ring. Must be called
+// with the mutex held.
+func (j *Journal) compact() error {
+	buf := bytes.Buffer{}
+	for i := 0; i < len(j.ring); i++ {
+		line, err := marshal(j.ring[(j.start+i)%len(j.ring)])
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+	}
+
+	if j.file != nil {
+		if err := j.file.Close(); err != nil {
+			return err
+		}
+		j.file = nil
+	}
+	if err := renameio.WriteFile(j.path, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	j.file = file
+	j.records = len(j.ring)
+	return nil
+}
+
+func marshal(entry Entry) ([]byte, error) {
+	event, err := entry.Event.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("marshal event %d: %w", entry.Seq, err)
+	}
+	line, err := json.Marshal(&record{Seq: entry.Seq, Event: event})
+	if err != nil {
+		return nil, fmt.Errorf("marshal record %d: %w", entry.Seq, err)
+	}
+	return append(line, '\n'), nil
+}
+
+func unmarshal(line []byte) (Entry, error) {
+	rec := record{}
+	if err := json.Unmarshal(line, &rec); err != nil {
+		return Entry{}, fmt.Errorf("unmarshal record: %w", err)
+	}
+	event := &types.ContainerEventResponse{}
+	if err := event.Unmarshal(rec.Event); err != nil {
+		return Entry{}, fmt.Errorf("unmarshal event %d: %w", rec.Seq, err)
+	}
+	return Entry{Seq: rec.Seq, Event: event}, nil
+}
@@ -0,0 +1,161 @@
+package events_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cri-o/cri-o/internal/events"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// The actual test suite
+var _ = t.Describe("Journal", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(t.MustTempDir("journal"), "events")
+	})
+
+	event := func(id string, createdAt int64) *types.ContainerEventResponse {
+		return &types.ContainerEventResponse{
+			ContainerId:        id,
+			ContainerEventType: types.ContainerEventType_CONTAINER_STARTED_EVENT,
+			CreatedAt:          createdAt,
+		}
+	}
+
+	ids := func(entries []events.Entry) []string {
+		res := []string{}
+		for _, entry := range entries {
+			res = append(res, entry.Event.ContainerId)
+		}
+		return res
+	}
+
+	It("should fail with an invalid size", func() {
+		// Given
+		// When
+		sut, err := events.Open(path, 0)
+
+		// Then
+		Expect(err).NotTo(BeNil())
+		Expect(sut).To(BeNil())
+	})
+
+	It("should assign increasing sequence numbers", func() {
+		// Given
+		sut, err := events.Open(path, 10)
+		Expect(err).To(BeNil())
+		defer sut.Close()
+
+		// When
+		first, err := sut.Append(event("1", 1))
+		Expect(err).To(BeNil())
+		second, err := sut.Append(event("2", 2))
+		Expect(err).To(BeNil())
+
+		// Then
+		Expect(first).To(BeEquivalentTo(1))
+		Expect(second).To(BeEquivalentTo(2))
+		Expect(sut.LastSeq()).To(BeEquivalentTo(2))
+	})
+
+	It("should keep only the latest events", func() {
+		// Given
+		sut, err := events.Open(path, 2)
+		Expect(err).To(BeNil())
+		defer sut.Close()
+
+		// When
+		for i, id := range []string{"1", "2", "3", "4", "5"} {
+			_, err := sut.Append(event(id, int64(i)))
+			Expect(err).To(BeNil())
+		}
+
+		// Then
+		Expect(ids(sut.Since(0, 0))).To(Equal([]string{"4", "5"}))
+		content, err := os.ReadFile(path)
+		Expect(err).To(BeNil())
+		Expect(strings.Count(string(content), "\n")).To(BeNumerically("<", 4))
+	})
+
+	It("should filter by sequence number and time", func() {
+		// Given
+		sut, err := events.Open(path, 10)
+		Expect(err).To(BeNil())
+		defer sut.Close()
+		for i, id := range []string{"1", "2", "3"} {
+			_, err := sut.Append(event(id, int64(i+1)*100))
+			Expect(err).To(BeNil())
+		}
+
+		// When
+		bySeq := sut.Since(1, 0)
+		byTime := sut.Since(0, 300)
+
+		// Then
+		Expect(ids(bySeq)).To(Equal([]string{"2", "3"}))
+		Expect(ids(byTime)).To(Equal([]string{"3"}))
+	})
+
+	It("should restore the events on reopen", func() {
+		// Given
+		sut, err := events.Open(path, 10)
+		Expect(err).To(BeNil())
+		for _, id := range []string{"1", "2"} {
+			_, err := sut.Append(event(id, 1))
+			Expect(err).To(BeNil())
+		}
+		Expect(sut.Close()).To(BeNil())
+
+		// When
+		sut, err = events.Open(path, 10)
+		Expect(err).To(BeNil())
+		defer sut.Close()
+		seq, err := sut.Append(event("3", 1))
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(seq).To(BeEquivalentTo(3))
+		Expect(ids(sut.Since(0, 0))).To(Equal([]string{"1", "2", "3"}))
+	})
+
+	It("should skip corrupted records on reopen", func() {
+		// Given
+		sut, err := events.Open(path, 10)
+		Expect(err).To(BeNil())
+		_, err = sut.Append(event("1", 1))
+		Expect(err).To(BeNil())
+		Expect(sut.Close()).To(BeNil())
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+		Expect(err).To(BeNil())
+		_, err = file.WriteString(`{"seq":2,"ev`)
+		Expect(err).To(BeNil())
+		Expect(file.Close()).To(BeNil())
+
+		// When
+		sut, err = events.Open(path, 10)
+
+		// Then
+		Expect(err).To(BeNil())
+		defer sut.Close()
+		Expect(ids(sut.Since(0, 0))).To(Equal([]string{"1"}))
+		Expect(sut.LastSeq()).To(BeEquivalentTo(1))
+	})
+
+	It("should fail to append after close", func() {
+		// Given
+		sut, err := events.Open(path, 10)
+		Expect(err).To(BeNil())
+		Expect(sut.Close()).To(BeNil())
+
+		// When
+		_, err = sut.Append(event("1", 1))
+
+		// Then
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -207,21 +207,26 @@ func normalizeRule(rule string) (string, error) {
 }
 
 func (f *fakeIPTables) EnsureRule(position utiliptables.RulePosition, tableName utiliptables.Table, chainName utiliptables.Chain, args ...string) (bool, error) {
+	return f.ensureRule(position, tableName, chainName, makeFakeRule(args))
+}
+
+// makeFakeRule joins the rule arguments and quotes the ones with internal
+// spaces (like comments)
+func makeFakeRule(args []string) string {
 	ruleArgs := make([]string, 0)
 	for _, arg := range args {
-		// quote args with internal spaces (like comments)
 		if strings.Contains(arg, " ") {
 			arg = fmt.Sprintf("%q", arg)
 		}
 		ruleArgs = append(ruleArgs, arg)
 	}
-	return f.ensureRule(position, tableName, chainName, strings.Join(ruleArgs, " "))
+	return strings.Join(ruleArgs, " ")
 }
 
 func (f *fakeIPTables) DeleteRule(tableName utiliptables.Table, chainName utiliptables.Chain, args ...string) error {
 	_, chain, err := f.getChain(tableName, chainName)
 	if err == nil {
-		rule := strings.Join(args, " ")
+		rule := makeFakeRule(args)
 		ruleIdx := findRule(chain, rule)
 		if ruleIdx < 0 {
 			return nil
@@ -0,0 +1,142 @@
+package hostport
+
+import "fmt"
+
+type fakeNFTablesTable struct {
+	chains map[string][]string
+}
+
+// fakeNFTables is an in-memory nftables implementation, which stores the rules
+// in the nft syntax.
+type fakeNFTables struct {
+	family       string
+	tables       map[string]*fakeNFTablesTable
+	transactions []*nftablesTransaction
+}
+
+func newFakeNFTables(family string) *fakeNFTables {
+	return &fakeNFTables{
+		family: family,
+		tables: make(map[string]*fakeNFTablesTable),
+	}
+}
+
+func (f *fakeNFTables) Family() string {
+	return f.family
+}
+
+func (f *fakeNFTables) IsIPv6() bool {
+	return f.family == nftablesFamilyIPv6
+}
+
+func (f *fakeNFTables) Present() error {
+	return nil
+}
+
+// Apply applies the transaction to a copy of the tables, which replaces the
+// tables only if all commands succeeded.
+func (f *fakeNFTables) Apply(tx *nftablesTransaction) error {
+	if tx.family != f.family {
+		return fmt.Errorf("unexpected family %s", tx.family)
+	}
+
+	tables := make(map[string]*fakeNFTablesTable)
+	for name, table := range f.tables {
+		chains := make(map[string][]string)
+		for chain, rules := range table.chains {
+			chains[chain] = append([]string{}, rules...)
+		}
+		tables[name] = &fakeNFTablesTable{chains: chains}
+	}
+
+	for _, cmd := range tx.commands {
+		if err := f.apply(tables, tx.table, cmd); err != nil {
+			return fmt.Errorf("%s %s: %w", cmd.verb, cmd.chain, err)
+		}
+	}
+
+	f.tables = tables
+	f.transactions = append(f.transactions, tx)
+	return nil
+}
+
+func (f *fakeNFTables) apply(tables map[string]*fakeNFTablesTable, tableName string, cmd *nftablesCommand) error {
+	if cmd.chain == "" {
+		if cmd.verb != nftablesAdd {
+			return fmt.Errorf("unsupported table command")
+		}
+		if _, ok := tables[tableName]; !ok {
+			tables[tableName] = &fakeNFTablesTable{chains: make(map[string][]string)}
+		}
+		return nil
+	}
+
+	table, ok := tables[tableName]
+	if !ok {
+		return fmt.Errorf("table %s does not exist", tableName)
+	}
+	chain := cmd.chain
+	rules, exists := table.chains[chain]
+
+	switch {
+	case cmd.rule != nil:
+		if !exists {
+			return fmt.Errorf("chain %s does not exist", chain)
+		}
+		if cmd.rule.jump != "" {
+			if _, ok := table.chains[cmd.rule.jump]; !ok {
+				return fmt.Errorf("jump target %s does not exist", cmd.rule.jump)
+			}
+		}
+		table.chains[chain] = append(rules, cmd.rule.String())
+		return nil
+	case cmd.verb == nftablesAdd:
+		if !exists {
+			table.chains[chain] = []string{}
+		}
+		return nil
+	case cmd.verb == nftablesFlush:
+		if !exists {
+			return fmt.Errorf("chain %s does not exist", chain)
+		}
+		table.chains[chain] = []string{}
+		return nil
+	case cmd.verb == nftablesDelete:
+		if !exists {
+			return fmt.Errorf("chain %s does not exist", chain)
+		}
+		if len(rules) > 0 {
+			return fmt.Errorf("chain %s is not empty", chain)
+		}
+		for _, otherRules := range table.chains {
+			for _, rule := range otherRules {
+				if rule == "jump "+chain {
+					return fmt.Errorf("chain %s is still referenced", chain)
+				}
+			}
+		}
+		delete(table.chains, chain)
+		return nil
+	}
+	return fmt.Errorf("unknown command %s", cmd.verb)
+}
+
+func (f *fakeNFTables) ListChains(table string) ([]string, error) {
+	chains := []string{}
+	if t, ok := f.tables[table]; ok {
+		for chain := range t.chains {
+			chains = append(chains, chain)
+		}
+	}
+	return chains, nil
+}
+
+// getRules returns the rules of the chain in the hostport table.
+func (f *fakeNFTables) getRules(chain string) ([]string, bool) {
+	table, ok := f.tables[nftablesTable]
+	if !ok {
+		return nil, false
+	}
+	rules, ok := table.chains[chain]
+	return rules, ok
+}
@@ -25,7 +25,9 @@ import (
 	"github.com/sirupsen/logrus"
 
 	utiliptables "github.com/cri-o/cri-o/internal/iptables"
+	"github.com/vishvananda/netlink"
 	v1 "k8s.io/api/core/v1"
+	utilnet "k8s.io/utils/net"
 )
 
 const (
@@ -40,6 +42,32 @@ const (
 	crioMasqueradeChainPrefix string = "CRIO-MASQ-"
 )
 
+var (
+	// kubeHostportsJumpArgs are the arguments of the rules in the OUTPUT and
+	// PREROUTING chains jumping to the hostport chain
+	kubeHostportsJumpArgs = []string{
+		"-m", "comment", "--comment", "kube hostport portals",
+		"-m", "addrtype", "--dst-type", "LOCAL",
+		"-j", string(kubeHostportsChain),
+	}
+	// crioMasqueradeJumpArgs are the arguments of the rule in the POSTROUTING
+	// chain jumping to the masquerade chain
+	crioMasqueradeJumpArgs = []string{
+		"-m", "comment", "--comment", "kube hostport masquerading",
+		"-m", "conntrack", "--ctstate", "DNAT",
+		"-j", string(crioMasqueradeChain),
+	}
+)
+
+// kubeHostportsJumpChains are the built-in chains jumping to the hostport chain
+var kubeHostportsJumpChains = []struct {
+	table utiliptables.Table
+	chain utiliptables.Chain
+}{
+	{utiliptables.TableNAT, utiliptables.ChainOutput},
+	{utiliptables.TableNAT, utiliptables.ChainPrerouting},
+}
+
 // PortMapping represents a network port in a container
 type PortMapping struct {
 	HostPort      int32
@@ -54,7 +82,97 @@ type PodPortMapping struct {
 	Name         string
 	PortMappings []*PortMapping
 	HostNetwork  bool
-	IP           net.IP
+	// IP is the primary pod IP of the IP family.
+	IP net.IP
+	// IPs are all pod IPs including their networks, if known. The host ports
+	// of a HostIP within the network of a pod IP are mapped to this pod IP
+	// instead of the primary one.
+	IPs []net.IPNet
+}
+
+// hostportTarget is a (HostIP, pod IP) pair of a port mapping. The traffic to
+// the host port on the HostIP gets forwarded to the pod IP, where an empty
+// HostIP matches all local addresses.
+type hostportTarget struct {
+	hostIP string
+	podIP  net.IP
+}
+
+// listHostAddresses returns the local addresses of the IP family.
+var listHostAddresses = func(isIPv6 bool) ([]net.IP, error) {
+	addrs, err := netlink.AddrList(nil, int(getNetlinkFamily(isIPv6)))
+	if err != nil {
+		return nil, fmt.Errorf("list host addresses: %w", err)
+	}
+	ips := make([]net.IP, 0, len(addrs))
+	for i := range addrs {
+		ips = append(ips, addrs[i].IP)
+	}
+	return ips, nil
+}
+
+// forFamily returns the port mapping for the pod IPs of the IP family, or nil
+// if the pod has no IP of the family.
+func (pod *PodPortMapping) forFamily(isIPv6 bool) *PodPortMapping {
+	mapping := *pod
+	mapping.IP = nil
+	mapping.IPs = nil
+	if pod.IP != nil && utilnet.IsIPv6(pod.IP) == isIPv6 {
+		mapping.IP = pod.IP
+	}
+	for _, ipNet := range pod.IPs {
+		if utilnet.IsIPv6(ipNet.IP) != isIPv6 {
+			continue
+		}
+		if mapping.IP == nil {
+			mapping.IP = ipNet.IP
+		}
+		mapping.IPs = append(mapping.IPs, ipNet)
+	}
+	if mapping.IP == nil {
+		return nil
+	}
+	return &mapping
+}
+
+// getHostportTargets returns the (HostIP, pod IP) pairs of the port mapping.
+// A specific HostIP is mapped to the pod IP of the network containing it, or
+// the primary pod IP otherwise. If the HostIP matches all local addresses, the
+// local addresses within the network of a secondary pod IP are mapped to that
+// pod IP, followed by the remaining addresses mapped to the primary pod IP.
+func getHostportTargets(podPortMapping *PodPortMapping, pm *PortMapping, hostAddrs []net.IP) []hostportTarget {
+	if hostIP := net.ParseIP(pm.HostIP); pm.HostIP != "" && (hostIP == nil || !hostIP.IsUnspecified()) {
+		for _, ipNet := range podPortMapping.IPs {
+			if ipNet.Contains(hostIP) {
+				return []hostportTarget{{hostIP: pm.HostIP, podIP: ipNet.IP}}
+			}
+		}
+		return []hostportTarget{{hostIP: pm.HostIP, podIP: podPortMapping.IP}}
+	}
+
+	targets := []hostportTarget{}
+	for _, ipNet := range podPortMapping.IPs {
+		if ipNet.IP.Equal(podPortMapping.IP) {
+			continue
+		}
+		for _, hostAddr := range hostAddrs {
+			if ipNet.Contains(hostAddr) && !hostAddr.Equal(ipNet.IP) {
+				targets = append(targets, hostportTarget{hostIP: hostAddr.String(), podIP: ipNet.IP})
+			}
+		}
+	}
+	return append(targets, hostportTarget{podIP: podPortMapping.IP})
+}
+
+// getHostAddresses returns the local addresses required to compute the
+// hostport targets of the pod, which are only needed for secondary pod IPs.
+func getHostAddresses(podPortMapping *PodPortMapping, isIPv6 bool) ([]net.IP, error) {
+	for _, ipNet := range podPortMapping.IPs {
+		if !ipNet.IP.Equal(podPortMapping.IP) {
+			return listHostAddresses(isIPv6)
+		}
+	}
+	return nil, nil
 }
 
 // ipFamily refers to a specific family if not empty, i.e. "4" or "6".
@@ -79,6 +197,12 @@ type closeable interface {
 	Close() error
 }
 
+// podSocket is a socket holding a host port for the pod sandbox with the id.
+type podSocket struct {
+	closeable
+	id string
+}
+
 func openLocalPort(hp *hostport) (closeable, error) {
 	// For ports on node IPs, open the actual port and hold it, even though we
 	// use iptables to redirect traffic.
@@ -138,23 +262,11 @@ func ensureKubeHostportChains(iptables utiliptables.Interface, natInterfaceName
 	if _, err := iptables.EnsureChain(utiliptables.TableNAT, kubeHostportsChain); err != nil {
 		return fmt.Errorf("failed to ensure that %s chain %s exists: %w", utiliptables.TableNAT, kubeHostportsChain, err)
 	}
-	tableChainsNeedJumpServices := []struct {
-		table utiliptables.Table
-		chain utiliptables.Chain
-	}{
-		{utiliptables.TableNAT, utiliptables.ChainOutput},
-		{utiliptables.TableNAT, utiliptables.ChainPrerouting},
-	}
-	args := []string{
-		"-m", "comment", "--comment", "kube hostport portals",
-		"-m", "addrtype", "--dst-type", "LOCAL",
-		"-j", string(kubeHostportsChain),
-	}
-	for _, tc := range tableChainsNeedJumpServices {
+	for _, tc := range kubeHostportsJumpChains {
 		// KUBE-HOSTPORTS chain needs to be appended to the system chains.
 		// This ensures KUBE-SERVICES chain gets processed first.
 		// Since rules in KUBE-HOSTPORTS chain matches broader cases, allow the more specific rules to be processed first.
-		if _, err := iptables.EnsureRule(utiliptables.Append, tc.table, tc.chain, args...); err != nil {
+		if _, err := iptables.EnsureRule(utiliptables.Append, tc.table, tc.chain, kubeHostportsJumpArgs...); err != nil {
 			return fmt.Errorf("failed to ensure that %s chain %s jumps to %s: %w", tc.table, tc.chain, kubeHostportsChain, err)
 		}
 	}
@@ -163,12 +275,7 @@ func ensureKubeHostportChains(iptables utiliptables.Interface, natInterfaceName
 	if _, err := iptables.EnsureChain(utiliptables.TableNAT, crioMasqueradeChain); err != nil {
 		return fmt.Errorf("failed to ensure that %s chain %s exists: %w", utiliptables.TableNAT, crioMasqueradeChain, err)
 	}
-	args = []string{
-		"-m", "comment", "--comment", "kube hostport masquerading",
-		"-m", "conntrack", "--ctstate", "DNAT",
-		"-j", string(crioMasqueradeChain),
-	}
-	if _, err := iptables.EnsureRule(utiliptables.Append, utiliptables.TableNAT, utiliptables.ChainPostrouting, args...); err != nil {
+	if _, err := iptables.EnsureRule(utiliptables.Append, utiliptables.TableNAT, utiliptables.ChainPostrouting, crioMasqueradeJumpArgs...); err != nil {
 		return fmt.Errorf("failed to ensure that %s chain %s jumps to %s: %w", utiliptables.TableNAT, utiliptables.ChainPostrouting, crioMasqueradeChain, err)
 	}
 
@@ -178,10 +285,39 @@ func ensureKubeHostportChains(iptables utiliptables.Interface, natInterfaceName
 		if iptables.IsIPv6() {
 			localhost = "::1/128"
 		}
-		args = []string{"-m", "comment", "--comment", "SNAT for localhost access to hostports", "-o", natInterfaceName, "-s", localhost, "-j", "MASQUERADE"}
+		args := []string{"-m", "comment", "--comment", "SNAT for localhost access to hostports", "-o", natInterfaceName, "-s", localhost, "-j", "MASQUERADE"}
 		if _, err := iptables.EnsureRule(utiliptables.Append, utiliptables.TableNAT, crioMasqueradeChain, args...); err != nil {
 			return fmt.Errorf("failed to ensure that %s chain %s jumps to MASQUERADE: %w", utiliptables.TableNAT, utiliptables.ChainPostrouting, err)
 		}
 	}
 	return nil
 }
+
+// removeKubeHostportChains removes the KUBE-HOSTPORTS and CRIO-HOSTPORTS-MASQ
+// chains including the rules jumping to them. It is used to clean up after
+// switching to the nftables based hostport manager.
+func removeKubeHostportChains(iptables utiliptables.Interface) error {
+	logrus.Info("Removing kubelet hostport chains")
+	for _, tc := range kubeHostportsJumpChains {
+		if err := iptables.DeleteRule(tc.table, tc.chain, kubeHostportsJumpArgs...); err != nil {
+			return fmt.Errorf("failed to delete the jump from %s chain %s to %s: %w", tc.table, tc.chain, kubeHostportsChain, err)
+		}
+	}
+	if err := iptables.DeleteRule(utiliptables.TableNAT, utiliptables.ChainPostrouting, crioMasqueradeJumpArgs...); err != nil {
+		return fmt.Errorf("failed to delete the jump from %s chain %s to %s: %w", utiliptables.TableNAT, utiliptables.ChainPostrouting, crioMasqueradeChain, err)
+	}
+
+	for _, chain := range []utiliptables.Chain{kubeHostportsChain, crioMasqueradeChain} {
+		exists, err := iptables.ChainExists(utiliptables.TableNAT, chain)
+		if err != nil || !exists {
+			continue
+		}
+		if err := iptables.FlushChain(utiliptables.TableNAT, chain); err != nil {
+			return fmt.Errorf("failed to flush %s chain %s: %w", utiliptables.TableNAT, chain, err)
+		}
+		if err := iptables.DeleteChain(utiliptables.TableNAT, chain); err != nil {
+			return fmt.Errorf("failed to delete %s chain %s: %w", utiliptables.TableNAT, chain, err)
+		}
+	}
+	return nil
+}
@@ -75,7 +75,6 @@ func (hm *hostportManager) Add(id string, podPortMapping *PodPortMapping, natInt
 	if podPortMapping.IP.To16() == nil {
 		return fmt.Errorf("invalid or missing IP of pod %s", podFullName)
 	}
-	podIP := podPortMapping.IP.String()
 	isIPv6 := utilnet.IsIPv6(podPortMapping.IP)
 
 	// skip if there is no hostport needed
@@ -85,7 +84,7 @@ func (hm *hostportManager) Add(id string, podPortMapping *PodPortMapping, natInt
 	}
 
 	if isIPv6 != hm.iptables.IsIPv6() {
-		return fmt.Errorf("HostPortManager IP family mismatch: %v, isIPv6 - %v", podIP, isIPv6)
+		return fmt.Errorf("HostPortManager IP family mismatch: %v, isIPv6 - %v", podPortMapping.IP, isIPv6)
 	}
 
 	if err := ensureKubeHostportChains(hm.iptables, natInterfaceName); err != nil {
@@ -102,7 +101,7 @@ func (hm *hostportManager) Add(id string, podPortMapping *PodPortMapping, natInt
 		return err
 	}
 	for hostport, socket := range ports {
-		hm.hostPortMap[hostport] = socket
+		hm.hostPortMap[hostport] = &podSocket{closeable: socket, id: id}
 	}
 
 	natChains := bytes.NewBuffer(nil)
@@ -112,7 +111,13 @@ func (hm *hostportManager) Add(id string, podPortMapping *PodPortMapping, natInt
 	existingChains, existingRules, err := getExistingHostportIPTablesRules(hm.iptables)
 	if err != nil {
 		// clean up opened host port if encounter any error
-		return utilerrors.NewAggregate([]error{err, hm.closeHostports(hostportMappings)})
+		return utilerrors.NewAggregate([]error{err, closeHostports(hm.hostPortMap, hostportMappings, isIPv6, id)})
+	}
+
+	hostAddrs, err := getHostAddresses(podPortMapping, isIPv6)
+	if err != nil {
+		// clean up opened host port if encounter any error
+		return utilerrors.NewAggregate([]error{err, closeHostports(hm.hostPortMap, hostportMappings, isIPv6, id)})
 	}
 
 	newChains := []utiliptables.Chain{}
@@ -142,31 +147,40 @@ func (hm *hostportManager) Add(id string, podPortMapping *PodPortMapping, natInt
 			"-j", string(masqChain),
 		)
 
-		// DNAT to the podIP:containerPort
-		hostPortBinding := net.JoinHostPort(podIP, strconv.Itoa(int(pm.ContainerPort)))
-		if pm.HostIP == "" || pm.HostIP == "0.0.0.0" || pm.HostIP == "::" {
-			writeLine(natRules, "-A", string(hpChain),
-				"-m", "comment", "--comment", fmt.Sprintf(`"%s hostport %d"`, podFullName, pm.HostPort),
-				"-m", protocol, "-p", protocol,
-				"-j", "DNAT", fmt.Sprintf("--to-destination=%s", hostPortBinding))
-		} else {
-			writeLine(natRules, "-A", string(hpChain),
+		masqueradedIPs := map[string]bool{}
+		for _, target := range getHostportTargets(podPortMapping, pm, hostAddrs) {
+			// DNAT to the podIP:containerPort
+			targetIP := target.podIP.String()
+			hostPortBinding := net.JoinHostPort(targetIP, strconv.Itoa(int(pm.ContainerPort)))
+			if target.hostIP == "" {
+				writeLine(natRules, "-A", string(hpChain),
+					"-m", "comment", "--comment", fmt.Sprintf(`"%s hostport %d"`, podFullName, pm.HostPort),
+					"-m", protocol, "-p", protocol,
+					"-j", "DNAT", fmt.Sprintf("--to-destination=%s", hostPortBinding))
+			} else {
+				writeLine(natRules, "-A", string(hpChain),
+					"-m", "comment", "--comment", fmt.Sprintf(`"%s hostport %d"`, podFullName, pm.HostPort),
+					"-m", protocol, "-p", protocol, "-d", target.hostIP,
+					"-j", "DNAT", fmt.Sprintf("--to-destination=%s", hostPortBinding))
+			}
+
+			if masqueradedIPs[targetIP] {
+				continue
+			}
+			masqueradedIPs[targetIP] = true
+
+			// SNAT hairpin traffic. There is no "ctorigaddrtype" so we can't
+			// _exactly_ match only the traffic that was definitely DNATted by our
+			// rule as opposed to someone else's. But if the traffic has been DNATted
+			// and has src=dst=podIP then _someone_ needs to masquerade it, and the
+			// worst case here is just that "-j MASQUERADE" gets called twice.
+			writeLine(natRules, "-A", string(masqChain),
 				"-m", "comment", "--comment", fmt.Sprintf(`"%s hostport %d"`, podFullName, pm.HostPort),
-				"-m", protocol, "-p", protocol, "-d", pm.HostIP,
-				"-j", "DNAT", fmt.Sprintf("--to-destination=%s", hostPortBinding))
+				"-m", "conntrack", "--ctorigdstport", fmt.Sprintf("%d", pm.HostPort),
+				"-m", protocol, "-p", protocol, "--dport", fmt.Sprintf("%d", pm.ContainerPort),
+				"-s", targetIP, "-d", targetIP,
+				"-j", "MASQUERADE")
 		}
-
-		// SNAT hairpin traffic. There is no "ctorigaddrtype" so we can't
-		// _exactly_ match only the traffic that was definitely DNATted by our
-		// rule as opposed to someone else's. But if the traffic has been DNATted
-		// and has src=dst=podIP then _someone_ needs to masquerade it, and the
-		// worst case here is just that "-j MASQUERADE" gets called twice.
-		writeLine(natRules, "-A", string(masqChain),
-			"-m", "comment", "--comment", fmt.Sprintf(`"%s hostport %d"`, podFullName, pm.HostPort),
-			"-m", "conntrack", "--ctorigdstport", fmt.Sprintf("%d", pm.HostPort),
-			"-m", protocol, "-p", protocol, "--dport", fmt.Sprintf("%d", pm.ContainerPort),
-			"-s", podIP, "-d", podIP,
-			"-j", "MASQUERADE")
 	}
 
 	// getHostportChain should be able to provide unique hostport chain name using hash
@@ -185,7 +199,7 @@ func (hm *hostportManager) Add(id string, podPortMapping *PodPortMapping, natInt
 
 	if err = hm.syncIPTables(append(natChains.Bytes(), natRules.Bytes()...)); err != nil {
 		// clean up opened host port if encounter any error
-		return utilerrors.NewAggregate([]error{err, hm.closeHostports(hostportMappings)})
+		return utilerrors.NewAggregate([]error{err, closeHostports(hm.hostPortMap, hostportMappings, isIPv6, id)})
 	}
 
 	// Remove conntrack entries just after adding the new iptables rules. If the conntrack entry is removed along with
@@ -248,7 +262,7 @@ func (hm *hostportManager) Remove(id string, podPortMapping *PodPortMapping) (er
 	// exit if there is nothing to remove
 	// don´t forget to clean up opened pod host ports
 	if len(existingChainsToRemove) == 0 {
-		return hm.closeHostports(hostportMappings)
+		return closeHostports(hm.hostPortMap, hostportMappings, hm.iptables.IsIPv6(), id)
 	}
 
 	natChains := bytes.NewBuffer(nil)
@@ -270,7 +284,7 @@ func (hm *hostportManager) Remove(id string, podPortMapping *PodPortMapping) (er
 	}
 
 	// clean up opened pod host ports
-	return hm.closeHostports(hostportMappings)
+	return closeHostports(hm.hostPortMap, hostportMappings, hm.iptables.IsIPv6(), id)
 }
 
 // syncIPTables executes iptables-restore with given lines
@@ -287,6 +301,17 @@ func (hm *hostportManager) syncIPTables(lines []byte) error {
 // If encounter any error, clean up and return the error
 // If all ports are opened successfully, return the hostport and socket mapping
 func (hm *hostportManager) openHostports(podPortMapping *PodPortMapping) (map[hostport]closeable, error) {
+	return openHostports(hm.portOpener, podPortMapping, hm.iptables.IsIPv6())
+}
+
+// closeHostports tries to close all the listed host ports
+func (hm *hostportManager) closeHostports(hostportMappings []*PortMapping) error {
+	return closeHostports(hm.hostPortMap, hostportMappings, hm.iptables.IsIPv6(), "")
+}
+
+// openHostports opens all hostports of the given IP family for the pod using
+// the portOpener.
+func openHostports(portOpener hostportOpener, podPortMapping *PodPortMapping, isIPv6 bool) (map[hostport]closeable, error) {
 	var retErr error
 	ports := make(map[hostport]closeable)
 	for _, pm := range podPortMapping.PortMappings {
@@ -300,12 +325,12 @@ func (hm *hostportManager) openHostports(podPortMapping *PodPortMapping) (map[ho
 		}
 
 		// HostIP IP family is not handled by this port opener
-		if pm.HostIP != "" && utilnet.IsIPv6String(pm.HostIP) != hm.iptables.IsIPv6() {
+		if pm.HostIP != "" && utilnet.IsIPv6String(pm.HostIP) != isIPv6 {
 			continue
 		}
 
-		hp := portMappingToHostport(pm, hm.getIPFamily())
-		socket, err := hm.portOpener(&hp)
+		hp := portMappingToHostport(pm, getIPFamily(isIPv6))
+		socket, err := portOpener(&hp)
 		if err != nil {
 			retErr = fmt.Errorf("cannot open hostport %d for pod %s: %w", pm.HostPort, getPodFullName(podPortMapping), err)
 			break
@@ -325,18 +350,24 @@ func (hm *hostportManager) openHostports(podPortMapping *PodPortMapping) (map[ho
 	return ports, nil
 }
 
-// closeHostports tries to close all the listed host ports
-func (hm *hostportManager) closeHostports(hostportMappings []*PortMapping) error {
+// closeHostports closes the listed host ports of the given IP family and
+// removes them from the hostPortMap. Host ports held for other pods than the
+// one with the id are kept, unless the id is empty.
+func closeHostports(hostPortMap map[hostport]closeable, hostportMappings []*PortMapping, isIPv6 bool, id string) error {
 	errList := []error{}
 	for _, pm := range hostportMappings {
-		hp := portMappingToHostport(pm, hm.getIPFamily())
-		if socket, ok := hm.hostPortMap[hp]; ok {
+		hp := portMappingToHostport(pm, getIPFamily(isIPv6))
+		if socket, ok := hostPortMap[hp]; ok {
+			if owner, ok := socket.(*podSocket); ok && id != "" && owner.id != id {
+				logrus.Infof("Host port %s is held for pod sandbox %s", hp.String(), owner.id)
+				continue
+			}
 			logrus.Infof("Closing host port %s", hp.String())
 			if err := socket.Close(); err != nil {
 				errList = append(errList, fmt.Errorf("failed to close host port %s: %w", hp.String(), err))
 				continue
 			}
-			delete(hm.hostPortMap, hp)
+			delete(hostPortMap, hp)
 		} else {
 			logrus.Infof("Host port %s does not have an open socket", hp.String())
 		}
@@ -344,10 +375,10 @@ func (hm *hostportManager) closeHostports(hostportMappings []*PortMapping) error
 	return utilerrors.NewAggregate(errList)
 }
 
-// getIPFamily returns the hostPortManager IP family
-func (hm *hostportManager) getIPFamily() ipFamily {
+// getIPFamily returns the IP family of the hostport managers
+func getIPFamily(isIPv6 bool) ipFamily {
 	family := IPv4
-	if hm.iptables.IsIPv6() {
+	if isIPv6 {
 		family = IPv6
 	}
 	return family
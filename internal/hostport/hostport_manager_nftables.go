@@ -0,0 +1,426 @@
+package hostport
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	utiliptables "github.com/cri-o/cri-o/internal/iptables"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	utilnet "k8s.io/utils/net"
+)
+
+const (
+	// the nftables table containing all hostport rules
+	nftablesTable = "crio-hostports"
+
+	// the chain dispatching to the hostport chains of the pods
+	nftablesHostportsChain = "hostports"
+	// prefix for the hostport chains of the pods
+	nftablesHostportChainPrefix = "hostport-"
+
+	// the chain dispatching to the masquerade chains
+	nftablesMasqueradeChain = "masquerading"
+	// prefix for the masquerade chains
+	nftablesMasqueradeChainPrefix = "masquerade-"
+)
+
+type nftablesHostportManager struct {
+	hostPortMap map[hostport]closeable
+	nftables    nftablesInterface
+	portOpener  hostportOpener
+	// legacy is the iptables based manager which still owns the hostport
+	// rules of pods created before switching to nftables, if there are any.
+	legacy *hostportManager
+	mu     sync.Mutex
+}
+
+// newNFTablesHostportManager creates a new HostPortManager which programs the
+// hostport rules of every pod in a single nftables transaction. Hostport rules
+// of the iptables based manager are migrated if their pod gets added again and
+// removed together with their pod. The iptables hostport chains get removed as
+// soon as there are no more rules left.
+func newNFTablesHostportManager(nft nftablesInterface, iptables utiliptables.Interface) HostPortManager {
+	return &nftablesHostportManager{
+		hostPortMap: make(map[hostport]closeable),
+		nftables:    nft,
+		portOpener:  openLocalPort,
+		legacy:      newLegacyHostportManager(iptables),
+	}
+}
+
+// newLegacyHostportManager returns an iptables based manager for the existing
+// hostport rules, or nil if there are none.
+func newLegacyHostportManager(iptables utiliptables.Interface) *hostportManager {
+	if iptables == nil || !iptables.Present() {
+		return nil
+	}
+	legacy := &hostportManager{
+		hostPortMap: make(map[hostport]closeable),
+		iptables:    iptables,
+		portOpener:  openLocalPort,
+	}
+	if !legacy.pruneChains() {
+		return nil
+	}
+	logrus.Infof("Found iptables hostport rules of existing pods, which will be removed together with their pods")
+	return legacy
+}
+
+// pruneChains removes the iptables hostport chains if they do not contain any
+// pod rules anymore and returns true if there are rules left.
+func (hm *hostportManager) pruneChains() bool {
+	existingChains, _, err := getExistingHostportIPTablesRules(hm.iptables)
+	if err != nil {
+		logrus.Debugf("Unable to retrieve existing iptables hostport rules: %v", err)
+		return false
+	}
+	for chain := range existingChains {
+		if strings.HasPrefix(string(chain), kubeHostportChainPrefix) ||
+			strings.HasPrefix(string(chain), crioMasqueradeChainPrefix) {
+			return true
+		}
+	}
+	if len(existingChains) > 0 {
+		if err := removeKubeHostportChains(hm.iptables); err != nil {
+			logrus.Warnf("Unable to remove iptables hostport chains: %v", err)
+		}
+	}
+	return false
+}
+
+func (hm *nftablesHostportManager) Add(id string, podPortMapping *PodPortMapping, natInterfaceName string) (err error) {
+	if podPortMapping == nil || podPortMapping.HostNetwork {
+		return nil
+	}
+	podFullName := getPodFullName(podPortMapping)
+	// IP.To16() returns nil if IP is not a valid IPv4 or IPv6 address
+	if podPortMapping.IP.To16() == nil {
+		return fmt.Errorf("invalid or missing IP of pod %s", podFullName)
+	}
+	isIPv6 := utilnet.IsIPv6(podPortMapping.IP)
+
+	// skip if there is no hostport needed
+	hostportMappings := gatherHostportMappings(podPortMapping, isIPv6)
+	if len(hostportMappings) == 0 {
+		return nil
+	}
+
+	if isIPv6 != hm.nftables.IsIPv6() {
+		return fmt.Errorf("HostPortManager IP family mismatch: %v, isIPv6 - %v", podPortMapping.IP, isIPv6)
+	}
+
+	// Ensure atomicity for port opening and nftables operations
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	// try to open hostports
+	ports, err := openHostports(hm.portOpener, podPortMapping, isIPv6)
+	if err != nil {
+		return err
+	}
+	for hostport, socket := range ports {
+		hm.hostPortMap[hostport] = &podSocket{closeable: socket, id: id}
+	}
+
+	existingChains, err := hm.nftables.ListChains(nftablesTable)
+	if err != nil {
+		// clean up opened host port if encounter any error
+		return utilerrors.NewAggregate([]error{err, closeHostports(hm.hostPortMap, hostportMappings, isIPv6, id)})
+	}
+
+	hostAddrs, err := getHostAddresses(podPortMapping, isIPv6)
+	if err != nil {
+		// clean up opened host port if encounter any error
+		return utilerrors.NewAggregate([]error{err, closeHostports(hm.hostPortMap, hostportMappings, isIPv6, id)})
+	}
+
+	hpChain := getNFTablesPodChain(nftablesHostportChainPrefix, id)
+	masqChain := getNFTablesPodChain(nftablesMasqueradeChainPrefix, id)
+
+	tx := hm.newTransaction()
+	hm.addBaseChains(tx)
+	if natInterfaceName != "" && natInterfaceName != "lo" {
+		hm.addLocalhostMasquerade(tx, natInterfaceName)
+		existingChains = append(existingChains, getNFTablesLocalhostChain(natInterfaceName))
+	}
+
+	tx.addChain(hpChain)
+	tx.flushChain(hpChain)
+	tx.addChain(masqChain)
+	tx.flushChain(masqChain)
+
+	conntrackPortsToRemove := []int{}
+	for _, pm := range hostportMappings {
+		comment := fmt.Sprintf("%s hostport %d", podFullName, pm.HostPort)
+		if pm.Protocol == v1.ProtocolUDP {
+			conntrackPortsToRemove = append(conntrackPortsToRemove, int(pm.HostPort))
+		}
+
+		masqueradedIPs := map[string]bool{}
+		for _, target := range getHostportTargets(podPortMapping, pm, hostAddrs) {
+			// DNAT to the podIP:containerPort
+			rule := &nftablesRule{
+				protocol: pm.Protocol,
+				dport:    pm.HostPort,
+				dnatIP:   target.podIP,
+				dnatPort: pm.ContainerPort,
+				comment:  comment,
+			}
+			if target.hostIP != "" {
+				hostIP := net.ParseIP(target.hostIP)
+				if hostIP == nil {
+					err := fmt.Errorf("invalid host IP %s of pod %s", target.hostIP, podFullName)
+					// clean up opened host port if encounter any error
+					return utilerrors.NewAggregate([]error{err, closeHostports(hm.hostPortMap, hostportMappings, isIPv6, id)})
+				}
+				rule.daddr = hostIPNet(hostIP)
+			}
+			tx.addRule(hpChain, rule)
+
+			podIP := target.podIP.String()
+			if masqueradedIPs[podIP] {
+				continue
+			}
+			masqueradedIPs[podIP] = true
+
+			// SNAT hairpin traffic, see the iptables based manager for
+			// details. Unlike there, the original destination port is not
+			// matched, because the masquerading chain is only entered by
+			// connections which got DNATed anyway.
+			tx.addRule(masqChain, &nftablesRule{
+				saddr:      hostIPNet(target.podIP),
+				daddr:      hostIPNet(target.podIP),
+				protocol:   pm.Protocol,
+				dport:      pm.ContainerPort,
+				masquerade: true,
+				comment:    comment,
+			})
+		}
+	}
+
+	hm.addDispatchChains(tx, append(existingChains, hpChain, masqChain), "")
+
+	if err := hm.apply(tx); err != nil {
+		// clean up opened host port if encounter any error
+		return utilerrors.NewAggregate([]error{err, closeHostports(hm.hostPortMap, hostportMappings, isIPv6, id)})
+	}
+
+	if hm.legacy != nil {
+		// Migrate the pod by removing its iptables rules, if there are any.
+		if err := hm.removeLegacy(id, podPortMapping); err != nil {
+			logrus.Warnf("Unable to remove iptables hostport rules of pod %s: %v", podFullName, err)
+		}
+	}
+
+	// Remove conntrack entries just after adding the new nftables rules, see
+	// the iptables based manager for details.
+	logrus.Infof("Starting to delete udp conntrack entries: %v, isIPv6 - %v", conntrackPortsToRemove, isIPv6)
+	// https://www.iana.org/assignments/protocol-numbers/protocol-numbers.xhtml
+	const protocolUDPNumber = 17
+	for _, port := range conntrackPortsToRemove {
+		err = deleteConntrackEntriesForDstPort(uint16(port), protocolUDPNumber, getNetlinkFamily(isIPv6))
+		if err != nil {
+			logrus.Errorf("Failed to clear udp conntrack for port %d, error: %v", port, err)
+		}
+	}
+	return nil
+}
+
+func (hm *nftablesHostportManager) Remove(id string, podPortMapping *PodPortMapping) (err error) {
+	if podPortMapping == nil || podPortMapping.HostNetwork {
+		return nil
+	}
+
+	isIPv6 := hm.nftables.IsIPv6()
+	hostportMappings := gatherHostportMappings(podPortMapping, isIPv6)
+	if len(hostportMappings) == 0 {
+		return nil
+	}
+
+	// Ensure atomicity for port closing and nftables operations
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	errList := []error{}
+	if hm.legacy != nil {
+		if err := hm.removeLegacy(id, podPortMapping); err != nil {
+			errList = append(errList, err)
+		}
+	}
+
+	existingChains, err := hm.nftables.ListChains(nftablesTable)
+	if err != nil {
+		return utilerrors.NewAggregate(append(errList, err))
+	}
+
+	hpChain := getNFTablesPodChain(nftablesHostportChainPrefix, id)
+	masqChain := getNFTablesPodChain(nftablesMasqueradeChainPrefix, id)
+	chainsToRemove := []string{}
+	for _, chain := range existingChains {
+		if chain == hpChain || chain == masqChain {
+			chainsToRemove = append(chainsToRemove, chain)
+		}
+	}
+
+	// exit if there is nothing to remove
+	// don´t forget to clean up opened pod host ports
+	if len(chainsToRemove) > 0 {
+		tx := hm.newTransaction()
+		// The dispatch chains must not jump to the removed chains anymore
+		// before they can get deleted.
+		hm.addDispatchChains(tx, existingChains, id)
+		for _, chain := range chainsToRemove {
+			tx.flushChain(chain)
+			tx.deleteChain(chain)
+		}
+		if err := hm.apply(tx); err != nil {
+			return utilerrors.NewAggregate(append(errList, err))
+		}
+	}
+
+	// clean up opened pod host ports
+	if err := closeHostports(hm.hostPortMap, hostportMappings, isIPv6, id); err != nil {
+		errList = append(errList, err)
+	}
+	return utilerrors.NewAggregate(errList)
+}
+
+// removeLegacy removes the iptables rules of the pod and drops the legacy
+// manager if no rules are left. Must be called with the mutex held.
+func (hm *nftablesHostportManager) removeLegacy(id string, podPortMapping *PodPortMapping) error {
+	if err := hm.legacy.Remove(id, podPortMapping); err != nil {
+		return err
+	}
+	if !hm.legacy.pruneChains() {
+		logrus.Info("Migrated all iptables hostport rules to nftables")
+		hm.legacy = nil
+	}
+	return nil
+}
+
+// addBaseChains adds the commands to ensure the table including the base
+// chains, which are hooked into the netfilter NAT hooks.
+func (hm *nftablesHostportManager) addBaseChains(tx *nftablesTransaction) {
+	tx.addTable()
+	tx.addBaseChain("prerouting", -100)
+	tx.addBaseChain("output", -100)
+	tx.addBaseChain("postrouting", 100)
+	tx.addChain(nftablesHostportsChain)
+	tx.addChain(nftablesMasqueradeChain)
+
+	// Flushing the base chains before adding their rules keeps the commands
+	// idempotent.
+	for _, chain := range []string{"prerouting", "output"} {
+		tx.flushChain(chain)
+		tx.addRule(chain, &nftablesRule{
+			fibDaddrLocal: true,
+			jump:          nftablesHostportsChain,
+			comment:       "kube hostport portals",
+		})
+	}
+	tx.flushChain("postrouting")
+	tx.addRule("postrouting", &nftablesRule{
+		ctStatusDNAT: true,
+		jump:         nftablesMasqueradeChain,
+		comment:      "kube hostport masquerading",
+	})
+}
+
+// addLocalhostMasquerade adds the commands to SNAT the traffic from localhost
+// to the NAT interface.
+func (hm *nftablesHostportManager) addLocalhostMasquerade(tx *nftablesTransaction, natInterfaceName string) {
+	localhost := &net.IPNet{IP: net.IPv4(127, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)}
+	if hm.nftables.IsIPv6() {
+		localhost = hostIPNet(net.IPv6loopback)
+	}
+	chain := getNFTablesLocalhostChain(natInterfaceName)
+	tx.addChain(chain)
+	tx.flushChain(chain)
+	tx.addRule(chain, &nftablesRule{
+		oifname:    natInterfaceName,
+		saddr:      localhost,
+		masquerade: true,
+		comment:    "SNAT for localhost access to hostports",
+	})
+}
+
+// addDispatchChains adds the commands to rebuild the dispatch chains, so that
+// they jump to all hostport and masquerade chains. The chains of the pod with
+// the excludeID get skipped.
+func (hm *nftablesHostportManager) addDispatchChains(tx *nftablesTransaction, chains []string, excludeID string) {
+	excluded := map[string]bool{}
+	if excludeID != "" {
+		excluded[getNFTablesPodChain(nftablesHostportChainPrefix, excludeID)] = true
+		excluded[getNFTablesPodChain(nftablesMasqueradeChainPrefix, excludeID)] = true
+	}
+
+	hostportChains := map[string]bool{}
+	masqueradeChains := map[string]bool{}
+	for _, chain := range chains {
+		if excluded[chain] {
+			continue
+		}
+		if strings.HasPrefix(chain, nftablesHostportChainPrefix) {
+			hostportChains[chain] = true
+		} else if strings.HasPrefix(chain, nftablesMasqueradeChainPrefix) {
+			masqueradeChains[chain] = true
+		}
+	}
+
+	for _, dispatch := range []struct {
+		chain   string
+		targets map[string]bool
+	}{
+		{nftablesHostportsChain, hostportChains},
+		{nftablesMasqueradeChain, masqueradeChains},
+	} {
+		tx.flushChain(dispatch.chain)
+		for _, target := range sortedKeys(dispatch.targets) {
+			tx.addRule(dispatch.chain, &nftablesRule{jump: target})
+		}
+	}
+}
+
+// newTransaction returns a new transaction for the table of the manager.
+func (hm *nftablesHostportManager) newTransaction() *nftablesTransaction {
+	return newNFTablesTransaction(hm.nftables.Family(), nftablesTable)
+}
+
+// apply applies the nftables transaction
+func (hm *nftablesHostportManager) apply(tx *nftablesTransaction) error {
+	logrus.Debugf("Applying nftables rules: %s", tx)
+	if err := hm.nftables.Apply(tx); err != nil {
+		return fmt.Errorf("failed to apply nftables rules: %w", err)
+	}
+	return nil
+}
+
+// getNFTablesPodChain returns the nftables chain with the given prefix for the
+// pod, which is computed by hashing the id, like in getHostportChain.
+func getNFTablesPodChain(prefix, id string) string {
+	hash := sha256.Sum256([]byte(id))
+	encoded := base32.StdEncoding.EncodeToString(hash[:])
+	return prefix + strings.ToLower(encoded[:16])
+}
+
+// getNFTablesLocalhostChain returns the masquerade chain for the localhost
+// traffic to the NAT interface.
+func getNFTablesLocalhostChain(natInterfaceName string) string {
+	return nftablesMasqueradeChainPrefix + "localhost-" + natInterfaceName
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
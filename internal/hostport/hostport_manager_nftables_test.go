@@ -0,0 +1,325 @@
+package hostport
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+
+	utiliptables "github.com/cri-o/cri-o/internal/iptables"
+	"github.com/google/nftables/expr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+	v1 "k8s.io/api/core/v1"
+)
+
+func newFakeNFTablesHostportManager(nft *fakeNFTables, portOpener *fakeSocketManager) *nftablesHostportManager {
+	return &nftablesHostportManager{
+		hostPortMap: make(map[hostport]closeable),
+		nftables:    nft,
+		portOpener:  portOpener.openFakeSocket,
+	}
+}
+
+func TestNFTablesHostportManager(t *testing.T) {
+	nft := newFakeNFTables(nftablesFamilyIPv4)
+	portOpener := newFakeSocketManager()
+	manager := newFakeNFTablesHostportManager(nft, portOpener)
+
+	pod1 := &PodPortMapping{
+		Name:      "pod1",
+		Namespace: "ns1",
+		IP:        net.ParseIP("10.1.1.2"),
+		PortMappings: []*PortMapping{
+			{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP},
+			{HostPort: 8081, ContainerPort: 81, Protocol: v1.ProtocolUDP},
+			{HostPort: 8083, ContainerPort: 83, Protocol: v1.ProtocolSCTP},
+			{HostPort: 8084, ContainerPort: 84, Protocol: v1.ProtocolTCP, HostIP: "127.0.0.1"},
+			{HostPort: 8085, ContainerPort: 85, Protocol: v1.ProtocolTCP, HostIP: "2001:beef::1"},
+		},
+	}
+	pod2 := &PodPortMapping{
+		Name:      "pod2",
+		Namespace: "ns1",
+		IP:        net.ParseIP("10.1.1.3"),
+		PortMappings: []*PortMapping{
+			{HostPort: 8443, ContainerPort: 443, Protocol: v1.ProtocolTCP},
+		},
+	}
+	pod3 := &PodPortMapping{
+		Name:      "pod3",
+		Namespace: "ns1",
+		IP:        net.ParseIP("10.1.1.4"),
+		PortMappings: []*PortMapping{
+			{HostPort: 8443, ContainerPort: 443, Protocol: v1.ProtocolTCP},
+		},
+	}
+
+	// Add the hostports
+	require.NoError(t, manager.Add("id1", pod1, "cni0"))
+	require.NoError(t, manager.Add("id2", pod2, ""))
+	// the port is already taken by pod2
+	assert.Error(t, manager.Add("id3", pod3, ""))
+	// the IP family does not match
+	assert.Error(t, manager.Add("id4", &PodPortMapping{
+		Name:      "pod4",
+		Namespace: "ns1",
+		IP:        net.ParseIP("2001:beef::2"),
+		PortMappings: []*PortMapping{
+			{HostPort: 8090, ContainerPort: 90, Protocol: v1.ProtocolTCP},
+		},
+	}, ""))
+
+	// Check the opened ports
+	expectedPorts := map[hostport]bool{
+		{IPv4, "", 8080, "tcp"}:          true,
+		{IPv4, "", 8081, "udp"}:          true,
+		{IPv4, "127.0.0.1", 8084, "tcp"}: true,
+		{IPv4, "", 8443, "tcp"}:          true,
+	}
+	openedPorts := make(map[hostport]bool)
+	for hp, port := range portOpener.mem {
+		if !port.closed {
+			openedPorts[hp] = true
+		}
+	}
+	assert.Equal(t, expectedPorts, openedPorts)
+
+	// Check the rules
+	hpChain1 := getNFTablesPodChain(nftablesHostportChainPrefix, "id1")
+	masqChain1 := getNFTablesPodChain(nftablesMasqueradeChainPrefix, "id1")
+	hpChain2 := getNFTablesPodChain(nftablesHostportChainPrefix, "id2")
+	masqChain2 := getNFTablesPodChain(nftablesMasqueradeChainPrefix, "id2")
+	localhostChain := getNFTablesLocalhostChain("cni0")
+
+	rules, ok := nft.getRules("prerouting")
+	assert.True(t, ok)
+	assert.Equal(t, []string{`fib daddr type local jump hostports comment "kube hostport portals"`}, rules)
+	rules, ok = nft.getRules("postrouting")
+	assert.True(t, ok)
+	assert.Equal(t, []string{`ct status dnat jump masquerading comment "kube hostport masquerading"`}, rules)
+
+	rules, ok = nft.getRules(nftablesHostportsChain)
+	assert.True(t, ok)
+	assert.ElementsMatch(t, []string{"jump " + hpChain1, "jump " + hpChain2}, rules)
+	rules, ok = nft.getRules(nftablesMasqueradeChain)
+	assert.True(t, ok)
+	assert.ElementsMatch(t, []string{"jump " + masqChain1, "jump " + masqChain2, "jump " + localhostChain}, rules)
+
+	rules, ok = nft.getRules(hpChain1)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		`tcp dport 8080 dnat to 10.1.1.2:80 comment "pod1_ns1 hostport 8080"`,
+		`udp dport 8081 dnat to 10.1.1.2:81 comment "pod1_ns1 hostport 8081"`,
+		`sctp dport 8083 dnat to 10.1.1.2:83 comment "pod1_ns1 hostport 8083"`,
+		`ip daddr 127.0.0.1 tcp dport 8084 dnat to 10.1.1.2:84 comment "pod1_ns1 hostport 8084"`,
+	}, rules)
+	rules, ok = nft.getRules(masqChain2)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		`ip saddr 10.1.1.3 ip daddr 10.1.1.3 tcp dport 443 masquerade comment "pod2_ns1 hostport 8443"`,
+	}, rules)
+	rules, ok = nft.getRules(localhostChain)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		`oifname "cni0" ip saddr 127.0.0.0/8 masquerade comment "SNAT for localhost access to hostports"`,
+	}, rules)
+
+	// Adding the same pod again must not duplicate the rules
+	require.NoError(t, manager.Remove("id1", pod1))
+	require.NoError(t, manager.Add("id1", pod1, "cni0"))
+	rules, _ = nft.getRules(hpChain1)
+	assert.Len(t, rules, 4)
+	rules, _ = nft.getRules(nftablesHostportsChain)
+	assert.Len(t, rules, 2)
+
+	// Remove the hostports
+	require.NoError(t, manager.Remove("id1", &PodPortMapping{
+		Name:         pod1.Name,
+		Namespace:    pod1.Namespace,
+		PortMappings: pod1.PortMappings,
+	}))
+	_, ok = nft.getRules(hpChain1)
+	assert.False(t, ok)
+	_, ok = nft.getRules(masqChain1)
+	assert.False(t, ok)
+	rules, _ = nft.getRules(nftablesHostportsChain)
+	assert.Equal(t, []string{"jump " + hpChain2}, rules)
+
+	// Removing an unknown pod is a no-op
+	require.NoError(t, manager.Remove("id1", pod1))
+
+	require.NoError(t, manager.Remove("id2", pod2))
+	rules, _ = nft.getRules(nftablesHostportsChain)
+	assert.Empty(t, rules)
+	rules, _ = nft.getRules(nftablesMasqueradeChain)
+	assert.Equal(t, []string{"jump " + localhostChain}, rules)
+
+	// Check that all ports got closed
+	for hp, port := range portOpener.mem {
+		assert.True(t, port.closed, "port %s is still open", hp.String())
+	}
+}
+
+func TestNFTablesHostportManagerIPv6(t *testing.T) {
+	nft := newFakeNFTables(nftablesFamilyIPv6)
+	manager := newFakeNFTablesHostportManager(nft, newFakeSocketManager())
+
+	require.NoError(t, manager.Add("id", &PodPortMapping{
+		Name:      "pod",
+		Namespace: "ns",
+		IP:        net.ParseIP("2001:beef::2"),
+		PortMappings: []*PortMapping{
+			{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP, HostIP: "2001:beef::1"},
+			{HostPort: 8081, ContainerPort: 81, Protocol: v1.ProtocolTCP, HostIP: "127.0.0.1"},
+		},
+	}, "cni0"))
+
+	rules, ok := nft.getRules(getNFTablesPodChain(nftablesHostportChainPrefix, "id"))
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		`ip6 daddr 2001:beef::1 tcp dport 8080 dnat to [2001:beef::2]:80 comment "pod_ns hostport 8080"`,
+	}, rules)
+	rules, ok = nft.getRules(getNFTablesLocalhostChain("cni0"))
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		`oifname "cni0" ip6 saddr ::1 masquerade comment "SNAT for localhost access to hostports"`,
+	}, rules)
+	for _, tx := range nft.transactions {
+		for _, line := range strings.Split(strings.TrimSpace(tx.String()), "\n") {
+			assert.Equal(t, "ip6 crio-hostports", strings.Join(strings.Fields(line)[2:4], " "), line)
+		}
+	}
+}
+
+func TestNFTablesHostportManagerMigration(t *testing.T) {
+	pod1 := &PodPortMapping{
+		Name:      "pod1",
+		Namespace: "ns1",
+		IP:        net.ParseIP("10.1.1.2"),
+		PortMappings: []*PortMapping{
+			{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP},
+		},
+	}
+	pod2 := &PodPortMapping{
+		Name:      "pod2",
+		Namespace: "ns1",
+		IP:        net.ParseIP("10.1.1.3"),
+		PortMappings: []*PortMapping{
+			{HostPort: 8081, ContainerPort: 81, Protocol: v1.ProtocolUDP},
+		},
+	}
+
+	// Given
+	iptables := newFakeIPTables()
+	iptablesManager := &hostportManager{
+		hostPortMap: make(map[hostport]closeable),
+		iptables:    iptables,
+		portOpener:  newFakeSocketManager().openFakeSocket,
+	}
+	require.NoError(t, iptablesManager.Add("id1", pod1, "cni0"))
+	require.NoError(t, iptablesManager.Add("id2", pod2, "cni0"))
+
+	nft := newFakeNFTables(nftablesFamilyIPv4)
+	manager := newFakeNFTablesHostportManager(nft, newFakeSocketManager())
+	manager.legacy = newLegacyHostportManager(iptables)
+	require.NotNil(t, manager.legacy)
+
+	// When
+	require.NoError(t, manager.Add("id1", pod1, "cni0"))
+
+	// Then
+	_, ok := nft.getRules(getNFTablesPodChain(nftablesHostportChainPrefix, "id1"))
+	assert.True(t, ok)
+	assert.Equal(t, []string{string(getHostportChain(kubeHostportChainPrefix, "id2", pod2.PortMappings[0]))}, legacyPodChains(t, iptables))
+	assert.NotNil(t, manager.legacy)
+
+	// When
+	require.NoError(t, manager.Remove("id2", pod2))
+
+	// Then
+	assert.Empty(t, legacyPodChains(t, iptables))
+	assert.Nil(t, manager.legacy)
+	for _, chain := range []utiliptables.Chain{kubeHostportsChain, crioMasqueradeChain} {
+		_, _, err := iptables.getChain(utiliptables.TableNAT, chain)
+		assert.Error(t, err)
+	}
+	for _, chain := range []utiliptables.Chain{utiliptables.ChainPrerouting, utiliptables.ChainOutput, utiliptables.ChainPostrouting} {
+		_, c, err := iptables.getChain(utiliptables.TableNAT, chain)
+		require.NoError(t, err)
+		assert.Empty(t, c.rules)
+	}
+}
+
+func TestNFTablesHostportManagerCleanupWithoutPods(t *testing.T) {
+	// Given
+	iptables := newFakeIPTables()
+	require.NoError(t, ensureKubeHostportChains(iptables, "cni0"))
+
+	// When
+	legacy := newLegacyHostportManager(iptables)
+
+	// Then
+	assert.Nil(t, legacy)
+	_, _, err := iptables.getChain(utiliptables.TableNAT, kubeHostportsChain)
+	assert.Error(t, err)
+	_, _, err = iptables.getChain(utiliptables.TableNAT, crioMasqueradeChain)
+	assert.Error(t, err)
+}
+
+func TestNFTablesRuleExprs(t *testing.T) {
+	// Given
+	rule := &nftablesRule{
+		daddr:    hostIPNet(net.ParseIP("127.0.0.1")),
+		protocol: v1.ProtocolTCP,
+		dport:    8080,
+		dnatIP:   net.ParseIP("10.1.1.2"),
+		dnatPort: 80,
+		comment:  "pod1_ns1 hostport 8080",
+	}
+
+	// When
+	exprs, err := rule.exprs(false)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []expr.Any{
+		&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader, Offset: 16, Len: 4},
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{127, 0, 0, 1}},
+		&expr.Meta{Key: expr.MetaKeyL4PROTO, Register: 1},
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{6}},
+		&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseTransportHeader, Offset: 2, Len: 2},
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{0x1f, 0x90}},
+		&expr.Immediate{Register: 1, Data: []byte{10, 1, 1, 2}},
+		&expr.Immediate{Register: 2, Data: []byte{0, 80}},
+		&expr.NAT{Type: expr.NATTypeDestNAT, Family: unix.NFPROTO_IPV4, RegAddrMin: 1, RegProtoMin: 2},
+	}, exprs)
+}
+
+func TestNFTablesRuleExprsFamilyMismatch(t *testing.T) {
+	// Given
+	rule := &nftablesRule{
+		saddr:      hostIPNet(net.ParseIP("10.1.1.2")),
+		masquerade: true,
+	}
+
+	// When
+	_, err := rule.exprs(true)
+
+	// Then
+	assert.Error(t, err)
+}
+
+// legacyPodChains returns the iptables hostport chains of pods
+func legacyPodChains(t *testing.T, iptables *fakeIPTables) []string {
+	raw := bytes.NewBuffer(nil)
+	require.NoError(t, iptables.SaveInto(utiliptables.TableNAT, raw))
+	chains := []string{}
+	for chain := range getChainLines(utiliptables.TableNAT, raw.Bytes()) {
+		if strings.HasPrefix(string(chain), kubeHostportChainPrefix) {
+			chains = append(chains, string(chain))
+		}
+	}
+	return chains
+}
@@ -18,6 +18,7 @@ package hostport
 
 import (
 	"fmt"
+	"net"
 
 	utiliptables "github.com/cri-o/cri-o/internal/iptables"
 	. "github.com/onsi/ginkgo/v2"
@@ -94,4 +95,59 @@ var _ = t.Describe("HostPort", func() {
 		Expect(len(chain.rules)).To(BeEquivalentTo(1))
 		Expect(chain.rules).To(ContainElement(localhostMasqRule))
 	})
+
+	It("should map host ports to the pod IP of the HostIP network", func() {
+		// Given
+		_, primary, err := net.ParseCIDR("10.85.0.5/16")
+		Expect(err).To(BeNil())
+		primary.IP = net.ParseIP("10.85.0.5")
+		_, secondary, err := net.ParseCIDR("192.168.100.5/24")
+		Expect(err).To(BeNil())
+		secondary.IP = net.ParseIP("192.168.100.5")
+		mapping := &PodPortMapping{
+			IP:  primary.IP,
+			IPs: []net.IPNet{*primary, *secondary},
+		}
+		hostAddrs := []net.IP{net.ParseIP("10.85.0.1"), net.ParseIP("192.168.100.1"), net.ParseIP("172.16.0.1")}
+
+		// When
+		specific := getHostportTargets(mapping, &PortMapping{HostIP: "192.168.100.1"}, hostAddrs)
+		other := getHostportTargets(mapping, &PortMapping{HostIP: "172.16.0.1"}, hostAddrs)
+		wildcard := getHostportTargets(mapping, &PortMapping{HostIP: "0.0.0.0"}, hostAddrs)
+
+		// Then
+		Expect(specific).To(Equal([]hostportTarget{{hostIP: "192.168.100.1", podIP: secondary.IP}}))
+		Expect(other).To(Equal([]hostportTarget{{hostIP: "172.16.0.1", podIP: primary.IP}}))
+		Expect(wildcard).To(Equal([]hostportTarget{
+			{hostIP: "192.168.100.1", podIP: secondary.IP},
+			{podIP: primary.IP},
+		}))
+	})
+
+	It("should split the port mapping by IP family", func() {
+		// Given
+		mapping := &PodPortMapping{
+			Name: "pod",
+			IPs: []net.IPNet{
+				{IP: net.ParseIP("2001:beef::2"), Mask: net.CIDRMask(64, 128)},
+				{IP: net.ParseIP("10.85.0.5"), Mask: net.CIDRMask(16, 32)},
+				{IP: net.ParseIP("192.168.100.5"), Mask: net.CIDRMask(24, 32)},
+			},
+		}
+
+		// When
+		ipv4 := mapping.forFamily(false)
+		ipv6 := mapping.forFamily(true)
+		none := (&PodPortMapping{IPs: mapping.IPs[:1]}).forFamily(false)
+
+		// Then
+		Expect(ipv4).NotTo(BeNil())
+		Expect(ipv4.Name).To(Equal("pod"))
+		Expect(ipv4.IP.String()).To(Equal("10.85.0.5"))
+		Expect(ipv4.IPs).To(HaveLen(2))
+		Expect(ipv6).NotTo(BeNil())
+		Expect(ipv6.IP.String()).To(Equal("2001:beef::2"))
+		Expect(ipv6.IPs).To(HaveLen(1))
+		Expect(none).To(BeNil())
+	})
 })
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	utiliptables "github.com/cri-o/cri-o/internal/iptables"
+	"github.com/sirupsen/logrus"
 	utilexec "k8s.io/utils/exec"
 	utilnet "k8s.io/utils/net"
 )
@@ -14,45 +15,72 @@ type metaHostportManager struct {
 	ipv6HostportManager HostPortManager
 }
 
-// NewMetaHostportManager creates a new HostPortManager
+// NewMetaHostportManager creates a new HostPortManager. It programs the
+// hostport rules using nftables if available and falls back to iptables
+// otherwise.
 func NewMetaHostportManager() HostPortManager {
 	exec := utilexec.New()
-	// Create IPv4 handler
 	iptInterface := utiliptables.New(exec, utiliptables.ProtocolIPv4)
-	hostportManagerv4 := NewHostportManager(iptInterface)
-	// Create IPv6 handler
 	ip6tInterface := utiliptables.New(exec, utiliptables.ProtocolIPv6)
-	hostportManagerv6 := NewHostportManager(ip6tInterface)
 
-	h := &metaHostportManager{
-		ipv4HostportManager: hostportManagerv4,
-		ipv6HostportManager: hostportManagerv6,
+	nftInterface := newNFTables(nftablesFamilyIPv4)
+	nft6Interface := newNFTables(nftablesFamilyIPv6)
+	if err := nftInterface.Present(); err != nil {
+		logrus.Infof("Using iptables for hostport mapping, nftables is not available: %v", err)
+		return &metaHostportManager{
+			ipv4HostportManager: NewHostportManager(iptInterface),
+			ipv6HostportManager: NewHostportManager(ip6tInterface),
+		}
+	}
+
+	logrus.Info("Using nftables for hostport mapping")
+	return &metaHostportManager{
+		ipv4HostportManager: newNFTablesHostportManager(nftInterface, iptInterface),
+		ipv6HostportManager: newNFTablesHostportManager(nft6Interface, ip6tInterface),
 	}
-	return h
 }
 
 func (mh *metaHostportManager) Add(id string, podPortMapping *PodPortMapping, natInterfaceName string) error {
-	if utilnet.IsIPv6(podPortMapping.IP) {
-		return mh.ipv6HostportManager.Add(id, podPortMapping, natInterfaceName)
+	if len(podPortMapping.IPs) == 0 {
+		return mh.managerFor(utilnet.IsIPv6(podPortMapping.IP)).Add(id, podPortMapping, natInterfaceName)
 	}
 
-	return mh.ipv4HostportManager.Add(id, podPortMapping, natInterfaceName)
+	// Map the host ports to the pod IPs of every IP family
+	for _, isIPv6 := range []bool{false, true} {
+		mapping := podPortMapping.forFamily(isIPv6)
+		if mapping == nil {
+			continue
+		}
+		if err := mh.managerFor(isIPv6).Add(id, mapping, natInterfaceName); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (mh *metaHostportManager) Remove(id string, podPortMapping *PodPortMapping) error {
 	var errstrings []string
 	// Remove may not have the IP information, so we try to clean us much as possible
-	// and warn about the possible errors
-	err := mh.ipv4HostportManager.Remove(id, podPortMapping)
-	if err != nil {
-		errstrings = append(errstrings, err.Error())
-	}
-	err = mh.ipv6HostportManager.Remove(id, podPortMapping)
-	if err != nil {
-		errstrings = append(errstrings, err.Error())
+	// and warn about the possible errors. If the pod IPs are known, only the
+	// IP families of the pod are cleaned up.
+	for _, isIPv6 := range []bool{false, true} {
+		if podPortMapping != nil && len(podPortMapping.IPs) > 0 && podPortMapping.forFamily(isIPv6) == nil {
+			continue
+		}
+		if err := mh.managerFor(isIPv6).Remove(id, podPortMapping); err != nil {
+			errstrings = append(errstrings, err.Error())
+		}
 	}
 	if len(errstrings) > 0 {
 		return errors.New(strings.Join(errstrings, "\n"))
 	}
 	return nil
 }
+
+// managerFor returns the HostPortManager of the IP family
+func (mh *metaHostportManager) managerFor(isIPv6 bool) HostPortManager {
+	if isIPv6 {
+		return mh.ipv6HostportManager
+	}
+	return mh.ipv4HostportManager
+}
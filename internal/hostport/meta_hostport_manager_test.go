@@ -359,3 +359,92 @@ func TestMetaHostportManager(t *testing.T) {
 		assert.EqualValues(t, true, port.closed)
 	}
 }
+
+func TestMetaHostportManagerMultipleIPs(t *testing.T) {
+	listHostAddressesBefore := listHostAddresses
+	defer func() { listHostAddresses = listHostAddressesBefore }()
+	listHostAddresses = func(isIPv6 bool) ([]net.IP, error) {
+		if isIPv6 {
+			return []net.IP{net.ParseIP("2001:beef::1")}, nil
+		}
+		return []net.IP{net.ParseIP("10.85.0.1"), net.ParseIP("192.168.100.1")}, nil
+	}
+
+	nft := newFakeNFTables(nftablesFamilyIPv4)
+	portOpener := newFakeSocketManager()
+	nft6 := newFakeNFTables(nftablesFamilyIPv6)
+	port6Opener := newFakeSocketManager()
+	manager := metaHostportManager{
+		ipv4HostportManager: newFakeNFTablesHostportManager(nft, portOpener),
+		ipv6HostportManager: newFakeNFTablesHostportManager(nft6, port6Opener),
+	}
+
+	pod1 := &PodPortMapping{
+		Name:      "pod1",
+		Namespace: "ns1",
+		IPs: []net.IPNet{
+			{IP: net.ParseIP("10.85.0.5"), Mask: net.CIDRMask(16, 32)},
+			{IP: net.ParseIP("192.168.100.5"), Mask: net.CIDRMask(24, 32)},
+			{IP: net.ParseIP("2001:beef::5"), Mask: net.CIDRMask(64, 128)},
+		},
+		PortMappings: []*PortMapping{
+			{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP},
+			{HostPort: 8081, ContainerPort: 81, Protocol: v1.ProtocolTCP, HostIP: "192.168.100.1"},
+		},
+	}
+	pod2 := &PodPortMapping{
+		Name:      "pod2",
+		Namespace: "ns1",
+		IPs: []net.IPNet{
+			{IP: net.ParseIP("2001:beef::6"), Mask: net.CIDRMask(64, 128)},
+		},
+		PortMappings: []*PortMapping{
+			{HostPort: 9090, ContainerPort: 90, Protocol: v1.ProtocolTCP},
+		},
+	}
+
+	// Add the hostports for all pod IPs
+	assert.NoError(t, manager.Add("id1", pod1, ""))
+	assert.NoError(t, manager.Add("id2", pod2, ""))
+
+	rules, ok := nft.getRules(getNFTablesPodChain(nftablesHostportChainPrefix, "id1"))
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		`ip daddr 192.168.100.1 tcp dport 8080 dnat to 192.168.100.5:80 comment "pod1_ns1 hostport 8080"`,
+		`tcp dport 8080 dnat to 10.85.0.5:80 comment "pod1_ns1 hostport 8080"`,
+		`ip daddr 192.168.100.1 tcp dport 8081 dnat to 192.168.100.5:81 comment "pod1_ns1 hostport 8081"`,
+	}, rules)
+	rules, ok = nft.getRules(getNFTablesPodChain(nftablesMasqueradeChainPrefix, "id1"))
+	assert.True(t, ok)
+	assert.Len(t, rules, 3)
+	rules, ok = nft6.getRules(getNFTablesPodChain(nftablesHostportChainPrefix, "id1"))
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		`tcp dport 8080 dnat to [2001:beef::5]:80 comment "pod1_ns1 hostport 8080"`,
+	}, rules)
+
+	// Remove cleans up all pod IPs
+	assert.NoError(t, manager.Remove("id1", &PodPortMapping{
+		Name:         pod1.Name,
+		Namespace:    pod1.Namespace,
+		PortMappings: pod1.PortMappings,
+	}))
+	_, ok = nft.getRules(getNFTablesPodChain(nftablesHostportChainPrefix, "id1"))
+	assert.False(t, ok)
+	_, ok = nft6.getRules(getNFTablesPodChain(nftablesHostportChainPrefix, "id1"))
+	assert.False(t, ok)
+	for hp, port := range portOpener.mem {
+		assert.True(t, port.closed, "port %s is still open", hp.String())
+	}
+	assert.True(t, port6Opener.mem[hostport{IPv6, "", 8080, "tcp"}].closed)
+
+	// Remove does not close the host ports held for other pods
+	assert.NoError(t, manager.Remove("id1", &PodPortMapping{
+		Name:         pod2.Name,
+		Namespace:    pod2.Namespace,
+		PortMappings: pod2.PortMappings,
+	}))
+	assert.False(t, port6Opener.mem[hostport{IPv6, "", 9090, "tcp"}].closed)
+	_, ok = nft6.getRules(getNFTablesPodChain(nftablesHostportChainPrefix, "id2"))
+	assert.True(t, ok)
+}
@@ -0,0 +1,442 @@
+package hostport
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/binaryutil"
+	"github.com/google/nftables/expr"
+	"github.com/google/nftables/userdata"
+	"golang.org/x/sys/unix"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// nftablesFamilyIPv4 is the nftables address family of IPv4 tables
+	nftablesFamilyIPv4 = "ip"
+	// nftablesFamilyIPv6 is the nftables address family of IPv6 tables
+	nftablesFamilyIPv6 = "ip6"
+
+	// ipsDstNAT is the IPS_DST_NAT bit of the conntrack status
+	ipsDstNAT = 1 << 5
+)
+
+// nftablesInterface is an injectable interface for programming nftables
+// rules.
+type nftablesInterface interface {
+	// Family returns the nftables address family, which is either "ip" or
+	// "ip6".
+	Family() string
+	// IsIPv6 returns true if this is managing ip6 tables.
+	IsIPv6() bool
+	// Present returns an error if nftables can not be used.
+	Present() error
+	// Apply applies the transaction, which means that either all or none of
+	// its commands take effect.
+	Apply(tx *nftablesTransaction) error
+	// ListChains returns the names of all chains in the table of the address
+	// family. It returns an empty list if the table does not exist.
+	ListChains(table string) ([]string, error)
+}
+
+// nftablesTransaction is a list of commands for a single table, which get
+// applied atomically.
+type nftablesTransaction struct {
+	family   string
+	table    string
+	commands []*nftablesCommand
+}
+
+type nftablesVerb string
+
+const (
+	nftablesAdd    nftablesVerb = "add"
+	nftablesFlush  nftablesVerb = "flush"
+	nftablesDelete nftablesVerb = "delete"
+)
+
+// nftablesCommand is a command of a transaction. It affects the table if the
+// chain is empty, the chain if the rule is nil and adds the rule otherwise.
+type nftablesCommand struct {
+	verb  nftablesVerb
+	chain string
+	// hook and priority are only set when adding a base chain
+	hook     string
+	priority int32
+	rule     *nftablesRule
+}
+
+// nftablesRule is a rule of the subset of the nftables syntax used by the
+// hostport manager. A rule consists of its matches, followed by exactly one
+// statement.
+type nftablesRule struct {
+	// matches
+	fibDaddrLocal bool
+	ctStatusDNAT  bool
+	oifname       string
+	saddr         *net.IPNet
+	daddr         *net.IPNet
+	protocol      v1.Protocol
+	dport         int32
+
+	// statements
+	jump       string
+	dnatIP     net.IP
+	dnatPort   int32
+	masquerade bool
+
+	comment string
+}
+
+func newNFTablesTransaction(family, table string) *nftablesTransaction {
+	return &nftablesTransaction{family: family, table: table}
+}
+
+func (tx *nftablesTransaction) addTable() {
+	tx.commands = append(tx.commands, &nftablesCommand{verb: nftablesAdd})
+}
+
+func (tx *nftablesTransaction) addChain(chain string) {
+	tx.commands = append(tx.commands, &nftablesCommand{verb: nftablesAdd, chain: chain})
+}
+
+// addBaseChain adds a NAT chain, which is hooked into the netfilter hook of
+// the same name.
+func (tx *nftablesTransaction) addBaseChain(hook string, priority int32) {
+	tx.commands = append(tx.commands, &nftablesCommand{verb: nftablesAdd, chain: hook, hook: hook, priority: priority})
+}
+
+func (tx *nftablesTransaction) flushChain(chain string) {
+	tx.commands = append(tx.commands, &nftablesCommand{verb: nftablesFlush, chain: chain})
+}
+
+func (tx *nftablesTransaction) deleteChain(chain string) {
+	tx.commands = append(tx.commands, &nftablesCommand{verb: nftablesDelete, chain: chain})
+}
+
+func (tx *nftablesTransaction) addRule(chain string, rule *nftablesRule) {
+	tx.commands = append(tx.commands, &nftablesCommand{verb: nftablesAdd, chain: chain, rule: rule})
+}
+
+// String renders the transaction in the nft syntax.
+func (tx *nftablesTransaction) String() string {
+	script := &strings.Builder{}
+	for _, cmd := range tx.commands {
+		words := []string{string(cmd.verb)}
+		switch {
+		case cmd.chain == "":
+			words = append(words, "table", tx.family, tx.table)
+		case cmd.rule == nil:
+			words = append(words, "chain", tx.family, tx.table, cmd.chain)
+			if cmd.hook != "" {
+				words = append(words, fmt.Sprintf("{ type nat hook %s priority %d; policy accept; }", cmd.hook, cmd.priority))
+			}
+		default:
+			words = append(words, "rule", tx.family, tx.table, cmd.chain, cmd.rule.String())
+		}
+		script.WriteString(strings.Join(words, " ") + "\n")
+	}
+	return script.String()
+}
+
+// String renders the rule in the nft syntax.
+func (r *nftablesRule) String() string {
+	words := []string{}
+	if r.fibDaddrLocal {
+		words = append(words, "fib daddr type local")
+	}
+	if r.ctStatusDNAT {
+		words = append(words, "ct status dnat")
+	}
+	if r.oifname != "" {
+		words = append(words, fmt.Sprintf("oifname %q", r.oifname))
+	}
+	if r.saddr != nil {
+		words = append(words, ipKeyword(r.saddr.IP), "saddr", formatIPNet(r.saddr))
+	}
+	if r.daddr != nil {
+		words = append(words, ipKeyword(r.daddr.IP), "daddr", formatIPNet(r.daddr))
+	}
+	if r.protocol != "" {
+		words = append(words, strings.ToLower(string(r.protocol)), "dport", strconv.Itoa(int(r.dport)))
+	}
+	switch {
+	case r.jump != "":
+		words = append(words, "jump", r.jump)
+	case r.dnatIP != nil:
+		words = append(words, "dnat to", net.JoinHostPort(r.dnatIP.String(), strconv.Itoa(int(r.dnatPort))))
+	case r.masquerade:
+		words = append(words, "masquerade")
+	}
+	if r.comment != "" {
+		words = append(words, fmt.Sprintf("comment %q", r.comment))
+	}
+	return strings.Join(words, " ")
+}
+
+// ipKeyword returns the nftables payload keyword of the IP header.
+func ipKeyword(ip net.IP) string {
+	if ip.To4() == nil {
+		return "ip6"
+	}
+	return "ip"
+}
+
+// formatIPNet returns the address of single host networks and the CIDR
+// notation otherwise.
+func formatIPNet(ipNet *net.IPNet) string {
+	if ones, bits := ipNet.Mask.Size(); ones == bits {
+		return ipNet.IP.String()
+	}
+	return ipNet.String()
+}
+
+// hostIPNet returns the single host network of the IP.
+func hostIPNet(ip net.IP) *net.IPNet {
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
+type nftablesNetlink struct {
+	family string
+}
+
+// newNFTables returns a new nftables interface for the address family, which
+// programs the rules via netlink.
+func newNFTables(family string) nftablesInterface {
+	return &nftablesNetlink{family: family}
+}
+
+func (n *nftablesNetlink) Family() string {
+	return n.family
+}
+
+func (n *nftablesNetlink) IsIPv6() bool {
+	return n.family == nftablesFamilyIPv6
+}
+
+func (n *nftablesNetlink) tableFamily() nftables.TableFamily {
+	if n.IsIPv6() {
+		return nftables.TableFamilyIPv6
+	}
+	return nftables.TableFamilyIPv4
+}
+
+func (n *nftablesNetlink) Present() error {
+	conn, err := nftables.New()
+	if err != nil {
+		return fmt.Errorf("open nftables netlink connection: %w", err)
+	}
+	// Listing the tables fails if the kernel does not support nf_tables.
+	if _, err := conn.ListTablesOfFamily(n.tableFamily()); err != nil {
+		return fmt.Errorf("list nftables tables: %w", err)
+	}
+	return nil
+}
+
+func (n *nftablesNetlink) Apply(tx *nftablesTransaction) error {
+	conn, err := nftables.New()
+	if err != nil {
+		return fmt.Errorf("open nftables netlink connection: %w", err)
+	}
+	table := &nftables.Table{Name: tx.table, Family: n.tableFamily()}
+	for _, cmd := range tx.commands {
+		chain := &nftables.Chain{Name: cmd.chain, Table: table}
+		switch {
+		case cmd.chain == "":
+			conn.AddTable(table)
+		case cmd.rule != nil:
+			exprs, err := cmd.rule.exprs(n.IsIPv6())
+			if err != nil {
+				return fmt.Errorf("add rule %s: %w", cmd.rule, err)
+			}
+			rule := &nftables.Rule{Table: table, Chain: chain, Exprs: exprs}
+			if cmd.rule.comment != "" {
+				rule.UserData = userdata.AppendString(nil, userdata.TypeComment, cmd.rule.comment)
+			}
+			conn.AddRule(rule)
+		case cmd.verb == nftablesFlush:
+			conn.FlushChain(chain)
+		case cmd.verb == nftablesDelete:
+			conn.DelChain(chain)
+		default:
+			if cmd.hook != "" {
+				hook, err := nftablesHook(cmd.hook)
+				if err != nil {
+					return err
+				}
+				chain.Type = nftables.ChainTypeNAT
+				chain.Hooknum = hook
+				chain.Priority = nftables.ChainPriorityRef(nftables.ChainPriority(cmd.priority))
+				policy := nftables.ChainPolicyAccept
+				chain.Policy = &policy
+			}
+			conn.AddChain(chain)
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return fmt.Errorf("flush nftables batch: %w", err)
+	}
+	return nil
+}
+
+func (n *nftablesNetlink) ListChains(table string) ([]string, error) {
+	conn, err := nftables.New()
+	if err != nil {
+		return nil, fmt.Errorf("open nftables netlink connection: %w", err)
+	}
+	chains, err := conn.ListChainsOfTableFamily(n.tableFamily())
+	if err != nil {
+		return nil, fmt.Errorf("list nftables chains of family %s: %w", n.family, err)
+	}
+	names := []string{}
+	for _, chain := range chains {
+		if chain.Table.Name == table {
+			names = append(names, chain.Name)
+		}
+	}
+	return names, nil
+}
+
+// nftablesHook returns the netfilter hook of the NAT base chain.
+func nftablesHook(hook string) (*nftables.ChainHook, error) {
+	switch hook {
+	case "prerouting":
+		return nftables.ChainHookPrerouting, nil
+	case "output":
+		return nftables.ChainHookOutput, nil
+	case "postrouting":
+		return nftables.ChainHookPostrouting, nil
+	}
+	return nil, fmt.Errorf("unsupported nftables hook %s", hook)
+}
+
+// exprs translates the rule into the expressions the kernel evaluates, the
+// same way as the nft binary does.
+func (r *nftablesRule) exprs(isIPv6 bool) ([]expr.Any, error) {
+	exprs := []expr.Any{}
+	if r.fibDaddrLocal {
+		exprs = append(exprs,
+			&expr.Fib{Register: 1, FlagDADDR: true, ResultADDRTYPE: true},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: binaryutil.NativeEndian.PutUint32(unix.RTN_LOCAL)},
+		)
+	}
+	if r.ctStatusDNAT {
+		exprs = append(exprs,
+			&expr.Ct{Register: 1, Key: expr.CtKeySTATUS},
+			&expr.Bitwise{
+				SourceRegister: 1,
+				DestRegister:   1,
+				Len:            4,
+				Mask:           binaryutil.NativeEndian.PutUint32(ipsDstNAT),
+				Xor:            binaryutil.NativeEndian.PutUint32(0),
+			},
+			&expr.Cmp{Op: expr.CmpOpNeq, Register: 1, Data: binaryutil.NativeEndian.PutUint32(0)},
+		)
+	}
+	if r.oifname != "" {
+		if len(r.oifname) >= unix.IFNAMSIZ {
+			return nil, fmt.Errorf("interface name %s is too long", r.oifname)
+		}
+		name := make([]byte, unix.IFNAMSIZ)
+		copy(name, r.oifname)
+		exprs = append(exprs,
+			&expr.Meta{Key: expr.MetaKeyOIFNAME, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: name},
+		)
+	}
+	for _, addr := range []struct {
+		ipNet                  *net.IPNet
+		ipv4Offset, ipv6Offset uint32
+	}{
+		{r.saddr, 12, 8},
+		{r.daddr, 16, 24},
+	} {
+		if addr.ipNet == nil {
+			continue
+		}
+		addrExprs, err := ipNetExprs(addr.ipNet, isIPv6, addr.ipv4Offset, addr.ipv6Offset)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, addrExprs...)
+	}
+	if r.protocol != "" {
+		proto, err := protocolNumber(r.protocol)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs,
+			&expr.Meta{Key: expr.MetaKeyL4PROTO, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{proto}},
+			&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseTransportHeader, Offset: 2, Len: 2},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: binaryutil.BigEndian.PutUint16(uint16(r.dport))},
+		)
+	}
+
+	switch {
+	case r.jump != "":
+		exprs = append(exprs, &expr.Verdict{Kind: expr.VerdictJump, Chain: r.jump})
+	case r.dnatIP != nil:
+		ip, family := r.dnatIP.To4(), uint32(unix.NFPROTO_IPV4)
+		if isIPv6 {
+			ip, family = r.dnatIP.To16(), unix.NFPROTO_IPV6
+		}
+		if ip == nil || (ip.To4() != nil) == isIPv6 {
+			return nil, fmt.Errorf("DNAT address %s does not match the address family", r.dnatIP)
+		}
+		exprs = append(exprs,
+			&expr.Immediate{Register: 1, Data: ip},
+			&expr.Immediate{Register: 2, Data: binaryutil.BigEndian.PutUint16(uint16(r.dnatPort))},
+			&expr.NAT{Type: expr.NATTypeDestNAT, Family: family, RegAddrMin: 1, RegProtoMin: 2},
+		)
+	case r.masquerade:
+		exprs = append(exprs, &expr.Masq{})
+	default:
+		return nil, fmt.Errorf("rule %s has no statement", r)
+	}
+	return exprs, nil
+}
+
+// ipNetExprs returns the expressions matching the address at the offset of
+// the IP header against the network.
+func ipNetExprs(ipNet *net.IPNet, isIPv6 bool, ipv4Offset, ipv6Offset uint32) ([]expr.Any, error) {
+	ip, mask, offset := ipNet.IP.To4(), ipNet.Mask, ipv4Offset
+	if isIPv6 {
+		ip, offset = ipNet.IP.To16(), ipv6Offset
+	}
+	if ip == nil || (ip.To4() != nil) == isIPv6 || len(mask) != len(ip) {
+		return nil, fmt.Errorf("address %s does not match the address family", ipNet)
+	}
+	exprs := []expr.Any{
+		&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader, Offset: offset, Len: uint32(len(ip))},
+	}
+	if ones, bits := mask.Size(); ones != bits {
+		exprs = append(exprs, &expr.Bitwise{
+			SourceRegister: 1,
+			DestRegister:   1,
+			Len:            uint32(len(ip)),
+			Mask:           mask,
+			Xor:            make([]byte, len(ip)),
+		})
+	}
+	return append(exprs, &expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: ip.Mask(mask)}), nil
+}
+
+// protocolNumber returns the IANA number of the protocol.
+func protocolNumber(protocol v1.Protocol) (byte, error) {
+	switch protocol {
+	case v1.ProtocolTCP:
+		return unix.IPPROTO_TCP, nil
+	case v1.ProtocolUDP:
+		return unix.IPPROTO_UDP, nil
+	case v1.ProtocolSCTP:
+		return unix.IPPROTO_SCTP, nil
+	}
+	return 0, fmt.Errorf("unsupported protocol %s", protocol)
+}
@@ -0,0 +1,194 @@
+package imagevolume
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/containers/storage/pkg/archive"
+	"github.com/containers/storage/pkg/directory"
+	"github.com/containers/storage/pkg/idtools"
+	"github.com/containers/storage/pkg/stringid"
+	"github.com/containers/storage/pkg/system"
+	securejoin "github.com/cyphar/filepath-securejoin"
+)
+
+// volumesDirName is the name of the directory inside of the container
+// directory, which contains the image volumes of the container.
+const volumesDirName = "volumes"
+
+// Volume is an image volume of a container. It is a directory managed by
+// CRI-O, which gets bind mounted to a volume path of the container image.
+type Volume struct {
+	// Destination is the path of the volume inside of the container.
+	Destination string `json:"destination"`
+
+	// Source is the path of the volume directory on the host.
+	Source string `json:"source"`
+
+	// UID and GID are the owner of the volume directory.
+	UID int `json:"uid"`
+	GID int `json:"gid"`
+}
+
+// Manager creates the image volumes of containers and keeps track of them
+// until they get removed together with the container.
+type Manager struct {
+	containers map[string]*containerVolumes
+	mutex      sync.Mutex
+}
+
+// containerVolumes are the tracked image volumes of a single container.
+type containerVolumes struct {
+	// dir is the directory which contains all image volumes of the container.
+	dir     string
+	volumes []*Volume
+}
+
+// New creates a new image volume Manager.
+func New() *Manager {
+	return &Manager{
+		containers: make(map[string]*containerVolumes),
+	}
+}
+
+// Dir returns the directory containing the image volumes of a container,
+// which is placed inside of the container directory ctrDir.
+func Dir(ctrDir string) string {
+	return filepath.Join(ctrDir, volumesDirName)
+}
+
+// Create creates a new image volume for the destination of the container with
+// the ID ctrID and tracks it. The volume directory is placed inside of the
+// container directory ctrDir.
+// If the destination exists in the container rootfs, then its content is
+// copied into the volume, which also takes over its owner and permissions.
+// Otherwise the volume is empty and owned by the provided owner.
+func (m *Manager) Create(ctrID, ctrDir, rootfs, destination string, owner idtools.IDPair) (vol *Volume, retErr error) {
+	imagePath, err := securejoin.SecureJoin(rootfs, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	source := filepath.Join(Dir(ctrDir), stringid.GenerateNonCryptoID())
+	if err := os.MkdirAll(Dir(ctrDir), 0o755); err != nil {
+		return nil, fmt.Errorf("create image volumes directory: %w", err)
+	}
+	defer func() {
+		if retErr != nil {
+			if err := os.RemoveAll(source); err != nil {
+				retErr = fmt.Errorf("%w: remove image volume %s: %v", retErr, source, err)
+			}
+		}
+	}()
+
+	info, err := os.Stat(imagePath)
+	switch {
+	case os.IsNotExist(err):
+		if err := idtools.MkdirAllAndChownNew(source, 0o755, owner); err != nil {
+			return nil, fmt.Errorf("create image volume %s: %w", destination, err)
+		}
+
+	case err != nil:
+		return nil, fmt.Errorf("stat image volume path %s: %w", destination, err)
+
+	case !info.IsDir():
+		return nil, fmt.Errorf("image volume path %s is not a directory", destination)
+
+	default:
+		// Copy up the content of the image, like done for volumes by Docker.
+		if err := archive.NewDefaultArchiver().CopyWithTar(imagePath, source); err != nil {
+			return nil, fmt.Errorf("copy up image content of volume %s: %w", destination, err)
+		}
+		st, err := system.Stat(imagePath)
+		if err != nil {
+			return nil, fmt.Errorf("stat image volume path %s: %w", destination, err)
+		}
+		owner = idtools.IDPair{UID: int(st.UID()), GID: int(st.GID())}
+		if err := os.Chown(source, owner.UID, owner.GID); err != nil {
+			return nil, fmt.Errorf("chown image volume %s: %w", destination, err)
+		}
+		if err := os.Chmod(source, info.Mode()); err != nil {
+			return nil, fmt.Errorf("chmod image volume %s: %w", destination, err)
+		}
+	}
+
+	vol = &Volume{
+		Destination: destination,
+		Source:      source,
+		UID:         owner.UID,
+		GID:         owner.GID,
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	ctr, ok := m.containers[ctrID]
+	if !ok {
+		ctr = &containerVolumes{dir: Dir(ctrDir)}
+		m.containers[ctrID] = ctr
+	}
+	ctr.volumes = append(ctr.volumes, vol)
+
+	return vol, nil
+}
+
+// Restore tracks the already existing image volumes of the container with the
+// ID ctrID, for example after a restart of the server.
+func (m *Manager) Restore(ctrID, ctrDir string, volumes []*Volume) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.containers[ctrID] = &containerVolumes{
+		dir:     Dir(ctrDir),
+		volumes: volumes,
+	}
+}
+
+// Volumes returns the tracked image volumes of the container with the ID
+// ctrID.
+func (m *Manager) Volumes(ctrID string) []*Volume {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	ctr, ok := m.containers[ctrID]
+	if !ok {
+		return nil
+	}
+	volumes := make([]*Volume, len(ctr.volumes))
+	copy(volumes, ctr.volumes)
+	return volumes
+}
+
+// Usage returns the disk usage of all image volumes of the container with the
+// ID ctrID. Containers without any image volume have no usage.
+func (m *Manager) Usage(ctrID string) (*directory.DiskUsage, error) {
+	m.mutex.Lock()
+	ctr, ok := m.containers[ctrID]
+	m.mutex.Unlock()
+	if !ok || len(ctr.volumes) == 0 {
+		return &directory.DiskUsage{}, nil
+	}
+
+	usage, err := directory.Usage(ctr.dir)
+	if err != nil {
+		return nil, fmt.Errorf("get image volumes usage of container %s: %w", ctrID, err)
+	}
+	// Do not account the volumes directory itself.
+	usage.InodeCount--
+	return usage, nil
+}
+
+// Remove removes all image volumes of the container with the ID ctrID and
+// stops tracking them. Removing a container without image volumes is a no-op.
+func (m *Manager) Remove(ctrID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	ctr, ok := m.containers[ctrID]
+	if !ok {
+		return nil
+	}
+	if err := os.RemoveAll(ctr.dir); err != nil {
+		return fmt.Errorf("remove image volumes of container %s: %w", ctrID, err)
+	}
+	delete(m.containers, ctrID)
+	return nil
+}
@@ -0,0 +1,141 @@
+package imagevolume_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/containers/storage/pkg/idtools"
+	"github.com/cri-o/cri-o/internal/imagevolume"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const ctrID = "id"
+
+// The actual test suite
+var _ = t.Describe("ImageVolume", func() {
+	var (
+		sut    *imagevolume.Manager
+		ctrDir string
+		rootfs string
+		owner  = idtools.IDPair{UID: 1000, GID: 1000}
+	)
+
+	BeforeEach(func() {
+		sut = imagevolume.New()
+		ctrDir = t.MustTempDir("ctr")
+		rootfs = t.MustTempDir("rootfs")
+	})
+
+	It("should create an empty volume if the path does not exist in the image", func() {
+		// Given
+		// When
+		vol, err := sut.Create(ctrID, ctrDir, rootfs, "/data", owner)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(vol.Destination).To(Equal("/data"))
+		Expect(filepath.Dir(vol.Source)).To(Equal(imagevolume.Dir(ctrDir)))
+		Expect(vol.UID).To(Equal(owner.UID))
+		Expect(vol.GID).To(Equal(owner.GID))
+		entries, err := os.ReadDir(vol.Source)
+		Expect(err).To(BeNil())
+		Expect(entries).To(BeEmpty())
+		Expect(sut.Volumes(ctrID)).To(ConsistOf(vol))
+	})
+
+	It("should copy up the image content into the volume", func() {
+		// Given
+		imageDir := filepath.Join(rootfs, "data")
+		Expect(os.MkdirAll(filepath.Join(imageDir, "sub"), 0o750)).To(Succeed())
+		Expect(os.Chmod(imageDir, 0o750)).To(Succeed())
+		Expect(os.Chown(imageDir, 10, 20)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(imageDir, "sub", "file"), []byte("content"), 0o644)).To(Succeed())
+
+		// When
+		vol, err := sut.Create(ctrID, ctrDir, rootfs, "/data", owner)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(vol.UID).To(Equal(10))
+		Expect(vol.GID).To(Equal(20))
+		info, err := os.Stat(vol.Source)
+		Expect(err).To(BeNil())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o750)))
+		content, err := os.ReadFile(filepath.Join(vol.Source, "sub", "file"))
+		Expect(err).To(BeNil())
+		Expect(string(content)).To(Equal("content"))
+	})
+
+	It("should fail if the path in the image is not a directory", func() {
+		// Given
+		Expect(os.WriteFile(filepath.Join(rootfs, "data"), []byte{}, 0o644)).To(Succeed())
+
+		// When
+		vol, err := sut.Create(ctrID, ctrDir, rootfs, "/data", owner)
+
+		// Then
+		Expect(err).NotTo(BeNil())
+		Expect(vol).To(BeNil())
+		Expect(sut.Volumes(ctrID)).To(BeEmpty())
+		entries, err := os.ReadDir(imagevolume.Dir(ctrDir))
+		Expect(err).To(BeNil())
+		Expect(entries).To(BeEmpty())
+	})
+
+	It("should report the usage of the volumes", func() {
+		// Given
+		vol, err := sut.Create(ctrID, ctrDir, rootfs, "/data", owner)
+		Expect(err).To(BeNil())
+		Expect(os.WriteFile(filepath.Join(vol.Source, "file"), []byte("content"), 0o644)).To(Succeed())
+
+		// When
+		usage, err := sut.Usage(ctrID)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(usage.Size).To(BeEquivalentTo(len("content")))
+		Expect(usage.InodeCount).To(BeEquivalentTo(2))
+	})
+
+	It("should report no usage for containers without volumes", func() {
+		// Given
+		// When
+		usage, err := sut.Usage(ctrID)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(usage.Size).To(BeZero())
+		Expect(usage.InodeCount).To(BeZero())
+	})
+
+	It("should remove the volumes", func() {
+		// Given
+		vol, err := sut.Create(ctrID, ctrDir, rootfs, "/data", owner)
+		Expect(err).To(BeNil())
+
+		// When
+		err = sut.Remove(ctrID)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(vol.Source).NotTo(BeADirectory())
+		Expect(imagevolume.Dir(ctrDir)).NotTo(BeADirectory())
+		Expect(sut.Volumes(ctrID)).To(BeEmpty())
+	})
+
+	It("should remove restored volumes", func() {
+		// Given
+		vol, err := sut.Create(ctrID, ctrDir, rootfs, "/data", owner)
+		Expect(err).To(BeNil())
+		restored := imagevolume.New()
+
+		// When
+		restored.Restore(ctrID, ctrDir, []*imagevolume.Volume{vol})
+
+		// Then
+		Expect(restored.Volumes(ctrID)).To(ConsistOf(vol))
+		Expect(restored.Remove(ctrID)).To(Succeed())
+		Expect(vol.Source).NotTo(BeADirectory())
+	})
+})
@@ -55,39 +55,40 @@ func (c *ContainerServer) ContainerCheckpoint(
 	if err = c.runtime.PauseContainer(ctx, ctr); err != nil {
 		return "", fmt.Errorf("failed to pause container %q before checkpointing: %w", ctr.ID(), err)
 	}
-	defer func() {
-		if err := c.runtime.UpdateContainerStatus(ctx, ctr); err != nil {
-			log.Errorf(ctx, "Failed to update container status: %q: %v", ctr.ID(), err)
-		}
-		if ctr.State().Status == oci.ContainerStatePaused {
-			err := c.runtime.UnpauseContainer(ctx, ctr)
-			if err != nil {
-				log.Errorf(ctx, "Failed to unpause container: %q: %v", ctr.ID(), err)
-			}
-		}
-		// container state needs to be written _after_ unpausing
-		if err = c.ContainerStateToDisk(ctx, ctr); err != nil {
-			log.Warnf(ctx, "Unable to write containers %s state to disk: %v", ctr.ID(), err)
-		}
-	}()
+	defer c.resumeCheckpointedContainer(ctx, ctr)
+
+	if err := c.checkpointPausedContainer(ctx, ctr, specgen.Config, opts); err != nil {
+		return "", err
+	}
 
+	return ctr.ID(), nil
+}
+
+// checkpointPausedContainer checkpoints the already paused container and
+// optionally exports the checkpoint to opts.TargetFile.
+func (c *ContainerServer) checkpointPausedContainer(
+	ctx context.Context,
+	ctr *oci.Container,
+	specgen *rspec.Spec,
+	opts *libpod.ContainerCheckpointOptions,
+) error {
 	if opts.TargetFile != "" {
 		if err := c.prepareCheckpointExport(ctr); err != nil {
-			return "", fmt.Errorf("failed to write config dumps for container %s: %w", ctr.ID(), err)
+			return fmt.Errorf("failed to write config dumps for container %s: %w", ctr.ID(), err)
 		}
 	}
 
-	if err := c.runtime.CheckpointContainer(ctx, ctr, specgen.Config, opts.KeepRunning); err != nil {
-		return "", fmt.Errorf("failed to checkpoint container %s: %w", ctr.ID(), err)
+	if err := c.runtime.CheckpointContainer(ctx, ctr, specgen, opts.KeepRunning); err != nil {
+		return fmt.Errorf("failed to checkpoint container %s: %w", ctr.ID(), err)
 	}
 	if opts.TargetFile != "" {
-		if err := c.exportCheckpoint(ctx, ctr, specgen.Config, opts.TargetFile); err != nil {
-			return "", fmt.Errorf("failed to write file system changes of container %s: %w", ctr.ID(), err)
+		if err := c.exportCheckpoint(ctx, ctr, specgen, opts.TargetFile); err != nil {
+			return fmt.Errorf("failed to write file system changes of container %s: %w", ctr.ID(), err)
 		}
 	}
 	if !opts.KeepRunning {
 		if err := c.storageRuntimeServer.StopContainer(ctx, ctr.ID()); err != nil {
-			return "", fmt.Errorf("failed to unmount container %s: %w", ctr.ID(), err)
+			return fmt.Errorf("failed to unmount container %s: %w", ctr.ID(), err)
 		}
 	}
 
@@ -106,7 +107,24 @@ func (c *ContainerServer) ContainerCheckpoint(
 		}
 	}
 
-	return ctr.ID(), nil
+	return nil
+}
+
+// resumeCheckpointedContainer unpauses the container after checkpointing if
+// it is still paused and writes its new state to disk.
+func (c *ContainerServer) resumeCheckpointedContainer(ctx context.Context, ctr *oci.Container) {
+	if err := c.runtime.UpdateContainerStatus(ctx, ctr); err != nil {
+		log.Errorf(ctx, "Failed to update container status: %q: %v", ctr.ID(), err)
+	}
+	if ctr.State().Status == oci.ContainerStatePaused {
+		if err := c.runtime.UnpauseContainer(ctx, ctr); err != nil {
+			log.Errorf(ctx, "Failed to unpause container: %q: %v", ctr.ID(), err)
+		}
+	}
+	// container state needs to be written _after_ unpausing
+	if err := c.ContainerStateToDisk(ctx, ctr); err != nil {
+		log.Warnf(ctx, "Unable to write containers %s state to disk: %v", ctr.ID(), err)
+	}
 }
 
 // Copied from libpod/diff.go
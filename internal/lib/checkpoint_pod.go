@@ -0,0 +1,259 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	metadata "github.com/checkpoint-restore/checkpointctl/lib"
+	"github.com/containers/podman/v4/libpod"
+	"github.com/containers/storage/pkg/archive"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/generate"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// PodCheckpointManifestVersion is the version of the pod checkpoint manifest
+// written by PodCheckpoint.
+const PodCheckpointManifestVersion = 1
+
+// PodCheckpointManifest is stored as metadata.PodDumpFile in a pod checkpoint
+// archive and describes the checkpointed sandbox and all of its containers.
+type PodCheckpointManifest struct {
+	// Version is the version of the manifest format.
+	Version int `json:"version"`
+	// ID is the ID of the checkpointed sandbox.
+	ID string `json:"id"`
+	// RuntimeHandler is the runtime handler the sandbox has been created with.
+	RuntimeHandler string `json:"runtimeHandler,omitempty"`
+	// Config is the configuration used to recreate the sandbox on restore.
+	Config *types.PodSandboxConfig `json:"config"`
+	// CheckpointedAt is the time the checkpoint has been created.
+	CheckpointedAt time.Time `json:"checkpointedAt"`
+	// Containers are the checkpointed containers in the order they have
+	// been created.
+	Containers []PodCheckpointContainer `json:"containers"`
+}
+
+// PodCheckpointContainer describes a single container checkpoint inside of a
+// pod checkpoint archive.
+type PodCheckpointContainer struct {
+	// ID is the ID of the checkpointed container.
+	ID string `json:"id"`
+	// Name is the Kubernetes name of the checkpointed container.
+	Name string `json:"name"`
+	// Archive is the path of the container checkpoint archive relative to
+	// the root of the pod checkpoint archive.
+	Archive string `json:"archive"`
+}
+
+// PodCheckpointOptions are the options for checkpointing a pod sandbox.
+type PodCheckpointOptions struct {
+	// TargetFile is the path of the resulting pod checkpoint archive.
+	TargetFile string
+	// KeepRunning specifies whether the containers should continue to run
+	// after they have been checkpointed.
+	KeepRunning bool
+}
+
+// PodCheckpoint checkpoints all running containers of a pod sandbox into a
+// single archive. All containers are paused before the first one gets
+// checkpointed, which results in a consistent snapshot of the whole pod.
+func (c *ContainerServer) PodCheckpoint(ctx context.Context, sbID string, opts *PodCheckpointOptions) (*PodCheckpointManifest, error) {
+	ctx, span := log.StartSpan(ctx)
+	defer span.End()
+
+	if opts.TargetFile == "" {
+		return nil, errors.New("target file for pod checkpoint must not be empty")
+	}
+
+	sb, err := c.LookupSandbox(sbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sandbox %s: %w", sbID, err)
+	}
+
+	ctrs := sb.Containers().List()
+	sort.Slice(ctrs, func(i, j int) bool {
+		return ctrs[i].CreatedAt().Before(ctrs[j].CreatedAt())
+	})
+
+	specs := make(map[string]*rspec.Spec, len(ctrs))
+	toCheckpoint := make([]*oci.Container, 0, len(ctrs))
+	for _, ctr := range ctrs {
+		if ctr.State().Status != oci.ContainerStateRunning {
+			log.Infof(ctx, "Skipping container %s of pod %s in checkpoint as it is not running", ctr.ID(), sb.ID())
+			continue
+		}
+		specgen, err := generate.NewFromFile(filepath.Join(ctr.BundlePath(), "config.json"))
+		if err != nil {
+			return nil, fmt.Errorf("not able to read config for container %q: %w", ctr.ID(), err)
+		}
+		specs[ctr.ID()] = specgen.Config
+		toCheckpoint = append(toCheckpoint, ctr)
+	}
+	if len(toCheckpoint) == 0 {
+		return nil, fmt.Errorf("pod %s has no running containers to checkpoint", sb.ID())
+	}
+
+	// Freeze every container of the pod before the first one gets
+	// checkpointed. Checkpointing the containers one after another while the
+	// others keep running would result in an inconsistent pod snapshot.
+	paused := make([]*oci.Container, 0, len(toCheckpoint))
+	defer func() {
+		for _, ctr := range paused {
+			c.resumeCheckpointedContainer(ctx, ctr)
+		}
+	}()
+	for _, ctr := range toCheckpoint {
+		if err := c.runtime.PauseContainer(ctx, ctr); err != nil {
+			return nil, fmt.Errorf("failed to pause container %q before checkpointing: %w", ctr.ID(), err)
+		}
+		paused = append(paused, ctr)
+	}
+
+	workDir, err := os.MkdirTemp("", "pod-checkpoint-")
+	if err != nil {
+		return nil, fmt.Errorf("create pod checkpoint directory: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(workDir); err != nil {
+			log.Warnf(ctx, "Unable to remove pod checkpoint directory %s: %v", workDir, err)
+		}
+	}()
+
+	manifest := &PodCheckpointManifest{
+		Version:        PodCheckpointManifestVersion,
+		ID:             sb.ID(),
+		RuntimeHandler: sb.RuntimeHandler(),
+		Config:         PodSandboxConfigFromSandbox(sb),
+		CheckpointedAt: time.Now(),
+		Containers:     make([]PodCheckpointContainer, 0, len(toCheckpoint)),
+	}
+
+	for _, ctr := range toCheckpoint {
+		archiveName := ctr.ID() + ".tar"
+		log.Debugf(ctx, "Checkpointing container %s of pod %s", ctr.ID(), sb.ID())
+		if err := c.checkpointPausedContainer(ctx, ctr, specs[ctr.ID()], &libpod.ContainerCheckpointOptions{
+			TargetFile:  filepath.Join(workDir, archiveName),
+			KeepRunning: opts.KeepRunning,
+		}); err != nil {
+			return nil, err
+		}
+
+		name := ctr.Name()
+		if md := ctr.Metadata(); md != nil {
+			name = md.Name
+		}
+		manifest.Containers = append(manifest.Containers, PodCheckpointContainer{
+			ID:      ctr.ID(),
+			Name:    name,
+			Archive: archiveName,
+		})
+	}
+
+	if _, err := metadata.WriteJSONFile(manifest, workDir, metadata.PodDumpFile); err != nil {
+		return nil, fmt.Errorf("write pod checkpoint manifest: %w", err)
+	}
+
+	if err := writePodCheckpointArchive(workDir, opts.TargetFile); err != nil {
+		return nil, fmt.Errorf("write pod checkpoint archive %s: %w", opts.TargetFile, err)
+	}
+
+	return manifest, nil
+}
+
+// writePodCheckpointArchive writes the content of dir as tar archive to target.
+func writePodCheckpointArchive(dir, target string) error {
+	input, err := archive.TarWithOptions(dir, &archive.TarOptions{
+		Compression:      archive.Uncompressed,
+		IncludeSourceDir: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	// The resulting tar archive should not be readable by everyone as it
+	// contains every memory page of the checkpointed processes.
+	outFile, err := os.OpenFile(target, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+
+	_, err = io.Copy(outFile, input)
+	return err
+}
+
+// ReadPodCheckpointManifest unpacks the pod checkpoint archive input into dir
+// and returns the contained manifest.
+func ReadPodCheckpointManifest(input, dir string) (*PodCheckpointManifest, error) {
+	archiveFile, err := os.Open(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pod checkpoint archive %s for import: %w", input, err)
+	}
+	defer archiveFile.Close()
+
+	if err := archive.Untar(archiveFile, dir, nil); err != nil {
+		return nil, fmt.Errorf("unpacking of pod checkpoint archive %s failed: %w", input, err)
+	}
+
+	manifest := new(PodCheckpointManifest)
+	if _, err := metadata.ReadJSONFile(manifest, dir, metadata.PodDumpFile); err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", metadata.PodDumpFile, err)
+	}
+	if manifest.Version != PodCheckpointManifestVersion {
+		return nil, fmt.Errorf("unsupported pod checkpoint manifest version %d", manifest.Version)
+	}
+	if manifest.Config == nil || manifest.Config.Metadata == nil {
+		return nil, errors.New("pod checkpoint manifest does not contain a sandbox config")
+	}
+	for _, ctr := range manifest.Containers {
+		if filepath.IsAbs(ctr.Archive) || ctr.Archive != filepath.Base(ctr.Archive) {
+			return nil, fmt.Errorf("invalid checkpoint archive path %q for container %s", ctr.Archive, ctr.ID)
+		}
+	}
+
+	return manifest, nil
+}
+
+// PodSandboxConfigFromSandbox reconstructs the CRI configuration of a sandbox,
+// which can be used to recreate it.
+func PodSandboxConfigFromSandbox(sb *sandbox.Sandbox) *types.PodSandboxConfig {
+	portMappings := make([]*types.PortMapping, 0, len(sb.PortMappings()))
+	for _, pm := range sb.PortMappings() {
+		portMappings = append(portMappings, &types.PortMapping{
+			Protocol:      types.Protocol(types.Protocol_value[string(pm.Protocol)]),
+			ContainerPort: pm.ContainerPort,
+			HostPort:      pm.HostPort,
+			HostIp:        pm.HostIP,
+		})
+	}
+
+	return &types.PodSandboxConfig{
+		Metadata:     sb.Metadata(),
+		Hostname:     sb.Hostname(),
+		LogDirectory: sb.LogDir(),
+		DnsConfig:    sb.DNSConfig(),
+		PortMappings: portMappings,
+		Labels:       sb.Labels(),
+		Annotations:  sb.Annotations(),
+		Linux: &types.LinuxPodSandboxConfig{
+			CgroupParent: sb.CgroupParent(),
+			SecurityContext: &types.LinuxSandboxSecurityContext{
+				NamespaceOptions: sb.NamespaceOptions(),
+				Privileged:       sb.Privileged(),
+			},
+			Overhead:  sb.PodLinuxOverhead(),
+			Resources: sb.PodLinuxResources(),
+		},
+	}
+}
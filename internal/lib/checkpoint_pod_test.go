@@ -0,0 +1,248 @@
+package lib_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	metadata "github.com/checkpoint-restore/checkpointctl/lib"
+	"github.com/containers/podman/v4/pkg/criu"
+	cstorage "github.com/containers/storage"
+	"github.com/containers/storage/pkg/archive"
+	"github.com/cri-o/cri-o/internal/hostport"
+	"github.com/cri-o/cri-o/internal/lib"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// The actual test suite
+var _ = t.Describe("PodCheckpoint", func() {
+	// Prepare the sut
+	BeforeEach(func() {
+		beforeEach()
+		createDummyConfig()
+		mockRuncInLibConfig()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll("dump.log")
+		os.RemoveAll("pod.tar")
+	})
+
+	t.Describe("PodCheckpoint", func() {
+		It("should fail without target file", func() {
+			// Given
+			addContainerAndSandbox()
+
+			// When
+			res, err := sut.PodCheckpoint(context.Background(), sandboxID, &lib.PodCheckpointOptions{})
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(res).To(BeNil())
+		})
+
+		It("should fail with invalid sandbox", func() {
+			// Given
+			// When
+			res, err := sut.PodCheckpoint(context.Background(), sandboxID,
+				&lib.PodCheckpointOptions{TargetFile: "pod.tar"})
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(res).To(BeNil())
+		})
+
+		It("should fail without running containers", func() {
+			// Given
+			addContainerAndSandbox()
+
+			// When
+			res, err := sut.PodCheckpoint(context.Background(), sandboxID,
+				&lib.PodCheckpointOptions{TargetFile: "pod.tar"})
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(res).To(BeNil())
+			Expect(err.Error()).To(Equal(`pod sandboxID has no running containers to checkpoint`))
+		})
+
+		It("should fail because runtime failure (/bin/false)", func() {
+			// Given
+			mockRuncToFalseInLibConfig()
+			addContainerAndSandbox()
+			myContainer.SetState(&oci.ContainerState{
+				State: specs.State{Status: oci.ContainerStateRunning},
+			})
+
+			// When
+			res, err := sut.PodCheckpoint(context.Background(), sandboxID,
+				&lib.PodCheckpointOptions{TargetFile: "pod.tar"})
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(res).To(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`failed to pause container "containerID" before checkpointing`))
+		})
+
+		It("should succeed", func() {
+			// Given
+			if err := criu.CheckForCriu(criu.PodCriuVersion); err != nil {
+				Skip("Check CRIU: " + err.Error())
+			}
+			addContainerAndSandbox()
+			myContainer.SetState(&oci.ContainerState{
+				State: specs.State{Status: oci.ContainerStateRunning},
+			})
+			myContainer.SetSpec(&specs.Spec{Version: "1.0.0"})
+
+			gomock.InOrder(
+				storeMock.EXPECT().Container(gomock.Any()).Return(&cstorage.Container{}, nil),
+				storeMock.EXPECT().Changes(gomock.Any(), gomock.Any()).Return([]archive.Change{}, nil),
+				storeMock.EXPECT().Mount(gomock.Any(), gomock.Any()).Return("/tmp/", nil),
+			)
+
+			// When
+			res, err := sut.PodCheckpoint(context.Background(), sandboxID,
+				&lib.PodCheckpointOptions{TargetFile: "pod.tar", KeepRunning: true})
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(res).NotTo(BeNil())
+			Expect(res.ID).To(Equal(sandboxID))
+			Expect(res.Containers).To(HaveLen(1))
+			Expect(res.Containers[0].ID).To(Equal(containerID))
+			_, err = os.Stat("pod.tar")
+			Expect(err).To(BeNil())
+		})
+	})
+})
+
+var _ = t.Describe("ReadPodCheckpointManifest", func() {
+	var archiveDir, outputDir string
+
+	BeforeEach(func() {
+		archiveDir = t.MustTempDir("pod-checkpoint")
+		outputDir = t.MustTempDir("pod-restore")
+	})
+
+	writeArchive := func(manifest *lib.PodCheckpointManifest) string {
+		_, err := metadata.WriteJSONFile(manifest, archiveDir, metadata.PodDumpFile)
+		Expect(err).To(BeNil())
+		input, err := archive.Tar(archiveDir, archive.Uncompressed)
+		Expect(err).To(BeNil())
+		defer input.Close()
+		target := filepath.Join(t.MustTempDir("pod-archive"), "pod.tar")
+		outFile, err := os.Create(target)
+		Expect(err).To(BeNil())
+		defer outFile.Close()
+		_, err = io.Copy(outFile, input)
+		Expect(err).To(BeNil())
+		return target
+	}
+
+	It("should succeed", func() {
+		// Given
+		input := writeArchive(&lib.PodCheckpointManifest{
+			Version: lib.PodCheckpointManifestVersion,
+			ID:      sandboxID,
+			Config: &types.PodSandboxConfig{
+				Metadata: &types.PodSandboxMetadata{Name: "pod"},
+			},
+			Containers: []lib.PodCheckpointContainer{
+				{ID: containerID, Name: "ctr", Archive: containerID + ".tar"},
+			},
+		})
+
+		// When
+		manifest, err := lib.ReadPodCheckpointManifest(input, outputDir)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(manifest.ID).To(Equal(sandboxID))
+		Expect(manifest.Config.Metadata.Name).To(Equal("pod"))
+		Expect(manifest.Containers).To(HaveLen(1))
+	})
+
+	It("should fail with unsupported version", func() {
+		// Given
+		input := writeArchive(&lib.PodCheckpointManifest{
+			Version: lib.PodCheckpointManifestVersion + 1,
+			Config: &types.PodSandboxConfig{
+				Metadata: &types.PodSandboxMetadata{},
+			},
+		})
+
+		// When
+		manifest, err := lib.ReadPodCheckpointManifest(input, outputDir)
+
+		// Then
+		Expect(err).NotTo(BeNil())
+		Expect(manifest).To(BeNil())
+	})
+
+	It("should fail with invalid container archive path", func() {
+		// Given
+		input := writeArchive(&lib.PodCheckpointManifest{
+			Version: lib.PodCheckpointManifestVersion,
+			Config: &types.PodSandboxConfig{
+				Metadata: &types.PodSandboxMetadata{},
+			},
+			Containers: []lib.PodCheckpointContainer{
+				{ID: containerID, Archive: "../" + containerID + ".tar"},
+			},
+		})
+
+		// When
+		manifest, err := lib.ReadPodCheckpointManifest(input, outputDir)
+
+		// Then
+		Expect(err).NotTo(BeNil())
+		Expect(manifest).To(BeNil())
+	})
+
+	It("should fail with not existing archive", func() {
+		// Given
+		// When
+		manifest, err := lib.ReadPodCheckpointManifest("/not/existing", outputDir)
+
+		// Then
+		Expect(err).NotTo(BeNil())
+		Expect(manifest).To(BeNil())
+	})
+})
+
+var _ = t.Describe("PodSandboxConfigFromSandbox", func() {
+	It("should succeed", func() {
+		// Given
+		sb, err := sandbox.New(sandboxID, "default", "name", "kubeName", "/log/dir",
+			map[string]string{"label": "value"}, map[string]string{"annotation": "value"}, "", "",
+			&types.PodSandboxMetadata{Name: "pod", Namespace: "default"}, "", "/cgroup/parent", false, "", "", "hostname",
+			[]*hostport.PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: "UDP", HostIP: "127.0.0.1"}},
+			false, time.Now(), "", nil, nil)
+		Expect(err).To(BeNil())
+		sb.SetNamespaceOptions(&types.NamespaceOption{Pid: types.NamespaceMode_POD})
+
+		// When
+		sbConfig := lib.PodSandboxConfigFromSandbox(sb)
+
+		// Then
+		Expect(sbConfig.Metadata.Name).To(Equal("pod"))
+		Expect(sbConfig.Hostname).To(Equal("hostname"))
+		Expect(sbConfig.LogDirectory).To(Equal("/log/dir"))
+		Expect(sbConfig.Labels).To(HaveKeyWithValue("label", "value"))
+		Expect(sbConfig.Annotations).To(HaveKeyWithValue("annotation", "value"))
+		Expect(sbConfig.Linux.CgroupParent).To(Equal("/cgroup/parent"))
+		Expect(sbConfig.Linux.SecurityContext.NamespaceOptions.Pid).To(Equal(types.NamespaceMode_POD))
+		Expect(sbConfig.PortMappings).To(HaveLen(1))
+		Expect(sbConfig.PortMappings[0].Protocol).To(Equal(types.Protocol_UDP))
+		Expect(sbConfig.PortMappings[0].HostIp).To(Equal("127.0.0.1"))
+	})
+})
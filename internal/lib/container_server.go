@@ -14,7 +14,9 @@ import (
 	cstorage "github.com/containers/storage"
 	"github.com/containers/storage/pkg/ioutils"
 	"github.com/containers/storage/pkg/truncindex"
+	"github.com/cri-o/cri-o/internal/bandwidth"
 	"github.com/cri-o/cri-o/internal/hostport"
+	"github.com/cri-o/cri-o/internal/imagevolume"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	statsserver "github.com/cri-o/cri-o/internal/lib/stats"
 	"github.com/cri-o/cri-o/internal/log"
@@ -46,6 +48,7 @@ type ContainerServer struct {
 	podNameIndex         *registrar.Registrar
 	podIDIndex           *truncindex.TruncIndex
 	Hooks                *hooks.Manager
+	imageVolumes         *imagevolume.Manager
 	*statsserver.StatsServer
 
 	stateLock sync.Locker
@@ -83,6 +86,11 @@ func (c *ContainerServer) Config() *libconfig.Config {
 	return c.config
 }
 
+// ImageVolumes returns the image volume manager for the ContainerServer
+func (c *ContainerServer) ImageVolumes() *imagevolume.Manager {
+	return c.imageVolumes
+}
+
 // StorageRuntimeServer gets the runtime server for the ContainerServer
 func (c *ContainerServer) StorageRuntimeServer() storage.RuntimeServer {
 	return c.storageRuntimeServer
@@ -150,6 +158,7 @@ func New(ctx context.Context, configIface libconfig.Iface) (*ContainerServer, er
 		podNameIndex:         registrar.NewRegistrar(),
 		podIDIndex:           truncindex.NewTruncIndex([]string{}),
 		Hooks:                newHooks,
+		imageVolumes:         imagevolume.New(),
 		stateLock:            &sync.Mutex{},
 		state: &containerServerState{
 			containers:      oci.NewMemoryStore(),
@@ -243,6 +252,14 @@ func (c *ContainerServer) LoadSandbox(ctx context.Context, id string) (sb *sandb
 	sb.SetSeccompProfilePath(spp)
 	sb.SetNamespaceOptions(&nsOpts)
 
+	if v := m.Annotations[crioann.BandwidthLimitsAnnotation]; v != "" {
+		bandwidthLimits := &bandwidth.Limits{}
+		if err := json.Unmarshal([]byte(v), bandwidthLimits); err != nil {
+			return nil, fmt.Errorf("error unmarshalling %s annotation: %w", crioann.BandwidthLimitsAnnotation, err)
+		}
+		sb.SetBandwidthLimits(bandwidthLimits)
+	}
+
 	defer func() {
 		if retErr != nil {
 			if err := sb.RemoveManagedNamespaces(); err != nil {
@@ -497,6 +514,14 @@ func (c *ContainerServer) LoadContainer(ctx context.Context, id string) (retErr
 
 	ctr.SetRuntimePathForPlatform(platformRuntimePath)
 
+	if v := m.Annotations[crioann.ImageVolumesAnnotation]; v != "" {
+		imageVolumes := []*imagevolume.Volume{}
+		if err := json.Unmarshal([]byte(v), &imageVolumes); err != nil {
+			return fmt.Errorf("failed to unmarshal image volumes: %w", err)
+		}
+		c.imageVolumes.Restore(id, containerDir, imageVolumes)
+	}
+
 	c.AddContainer(ctx, ctr)
 
 	return c.ctrIDIndex.Add(id)
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cri-o/cri-o/internal/bandwidth"
 	"github.com/cri-o/cri-o/internal/config/nsmgr"
 	"github.com/cri-o/cri-o/internal/hostport"
 	"github.com/cri-o/cri-o/internal/log"
@@ -66,6 +67,7 @@ type Sandbox struct {
 	containerEnvPath   string
 	podLinuxOverhead   *types.LinuxContainerResources
 	podLinuxResources  *types.LinuxContainerResources
+	bandwidthLimits    *bandwidth.Limits
 }
 
 // DefaultShmSize is the default shm size
@@ -141,6 +143,18 @@ func (s *Sandbox) AddIPs(ips []string) {
 	s.ips = ips
 }
 
+// SetBandwidthLimits sets the bandwidth limits applied by CRI-O on the pod
+// network interface, or nil if the CNI network applies them.
+func (s *Sandbox) SetBandwidthLimits(limits *bandwidth.Limits) {
+	s.bandwidthLimits = limits
+}
+
+// BandwidthLimits returns the bandwidth limits applied by CRI-O on the pod
+// network interface.
+func (s *Sandbox) BandwidthLimits() *bandwidth.Limits {
+	return s.bandwidthLimits
+}
+
 // SetNamespaceOptions sets whether the pod is running using host network
 func (s *Sandbox) SetNamespaceOptions(nsOpts *types.NamespaceOption) {
 	s.nsOpts = nsOpts
@@ -0,0 +1,320 @@
+package statsserver
+
+import (
+	"math"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/oci"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// Names of the metrics exposed through the ListPodSandboxMetrics RPC.
+// They match the names cAdvisor uses, so that dashboards and alerts written
+// against cAdvisor keep working when the kubelet consumes the CRI stats.
+const (
+	metricCPUUsageSecondsTotal       = "container_cpu_usage_seconds_total"
+	metricMemoryUsageBytes           = "container_memory_usage_bytes"
+	metricMemoryWorkingSetBytes      = "container_memory_working_set_bytes"
+	metricMemoryRSS                  = "container_memory_rss"
+	metricMemoryFailuresTotal        = "container_memory_failures_total"
+	metricNetworkReceiveBytesTotal   = "container_network_receive_bytes_total"
+	metricNetworkReceiveErrorsTotal  = "container_network_receive_errors_total"
+	metricNetworkTransmitBytesTotal  = "container_network_transmit_bytes_total"
+	metricNetworkTransmitErrorsTotal = "container_network_transmit_errors_total"
+	metricProcesses                  = "container_processes"
+	metricFsUsageBytes               = "container_fs_usage_bytes"
+	metricFsInodesUsed               = "container_fs_inodes_used"
+)
+
+const (
+	labelKeyInterface   = "interface"
+	labelKeyDevice      = "device"
+	labelKeyFailureType = "failure_type"
+	labelKeyScope       = "scope"
+
+	memoryFailureTypePgFault    = "pgfault"
+	memoryFailureTypePgMajFault = "pgmajfault"
+	memoryFailureScopeContainer = "container"
+
+	kubernetesContainerNameLabel = "io.kubernetes.container.name"
+)
+
+// baseLabelKeys are the label keys every metric is reported with.
+// They are kept sorted, as required by the CRI.
+var baseLabelKeys = []string{"container", "id", "image", "name", "namespace", "pod"}
+
+// withBaseLabelKeys returns the base label keys followed by the provided ones.
+func withBaseLabelKeys(keys ...string) []string {
+	return append(append(make([]string, 0, len(baseLabelKeys)+len(keys)), baseLabelKeys...), keys...)
+}
+
+// metricDescriptors contains the descriptors of all metrics CRI-O is able to
+// report for pods and containers.
+var metricDescriptors = []*types.MetricDescriptor{
+	{
+		Name:      metricCPUUsageSecondsTotal,
+		Help:      "Cumulative cpu time consumed in seconds.",
+		LabelKeys: withBaseLabelKeys(),
+	},
+	{
+		Name:      metricMemoryUsageBytes,
+		Help:      "Current memory usage in bytes, including all memory regardless of when it was accessed.",
+		LabelKeys: withBaseLabelKeys(),
+	},
+	{
+		Name:      metricMemoryWorkingSetBytes,
+		Help:      "Current working set in bytes.",
+		LabelKeys: withBaseLabelKeys(),
+	},
+	{
+		Name:      metricMemoryRSS,
+		Help:      "Size of RSS in bytes.",
+		LabelKeys: withBaseLabelKeys(),
+	},
+	{
+		Name:      metricMemoryFailuresTotal,
+		Help:      "Cumulative count of memory allocation failures.",
+		LabelKeys: withBaseLabelKeys(labelKeyFailureType, labelKeyScope),
+	},
+	{
+		Name:      metricNetworkReceiveBytesTotal,
+		Help:      "Cumulative count of bytes received.",
+		LabelKeys: withBaseLabelKeys(labelKeyInterface),
+	},
+	{
+		Name:      metricNetworkReceiveErrorsTotal,
+		Help:      "Cumulative count of errors encountered while receiving.",
+		LabelKeys: withBaseLabelKeys(labelKeyInterface),
+	},
+	{
+		Name:      metricNetworkTransmitBytesTotal,
+		Help:      "Cumulative count of bytes transmitted.",
+		LabelKeys: withBaseLabelKeys(labelKeyInterface),
+	},
+	{
+		Name:      metricNetworkTransmitErrorsTotal,
+		Help:      "Cumulative count of errors encountered while transmitting.",
+		LabelKeys: withBaseLabelKeys(labelKeyInterface),
+	},
+	{
+		Name:      metricProcesses,
+		Help:      "Number of processes running inside the container.",
+		LabelKeys: withBaseLabelKeys(),
+	},
+	{
+		Name:      metricFsUsageBytes,
+		Help:      "Number of bytes that are consumed by the container on this filesystem.",
+		LabelKeys: withBaseLabelKeys(labelKeyDevice),
+	},
+	{
+		Name:      metricFsInodesUsed,
+		Help:      "Number of inodes used by the container on this filesystem.",
+		LabelKeys: withBaseLabelKeys(labelKeyDevice),
+	},
+}
+
+// MetricDescriptors returns the descriptors of all metrics which can be
+// returned by MetricsForSandboxes.
+func MetricDescriptors() []*types.MetricDescriptor {
+	return metricDescriptors
+}
+
+// MetricsForSandboxes returns the metrics for the given list of sandboxes.
+func (ss *StatsServer) MetricsForSandboxes(sboxes []*sandbox.Sandbox) []*types.PodSandboxMetrics {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+	metrics := make([]*types.PodSandboxMetrics, 0, len(sboxes))
+	for _, sb := range sboxes {
+		stats := ss.statsForSandbox(sb)
+		if stats == nil {
+			continue
+		}
+		metrics = append(metrics, ss.metricsForSandbox(sb, stats))
+	}
+	return metrics
+}
+
+// metricsForSandbox converts the provided sandbox stats into CRI metrics.
+func (ss *StatsServer) metricsForSandbox(sb *sandbox.Sandbox, stats *types.PodSandboxStats) *types.PodSandboxMetrics {
+	podMetrics := &types.PodSandboxMetrics{
+		PodSandboxId:     sb.ID(),
+		ContainerMetrics: []*types.ContainerMetrics{},
+	}
+	if stats.Linux == nil {
+		return podMetrics
+	}
+
+	podLabels := &metricLabels{
+		id:        sb.ID(),
+		namespace: sb.Namespace(),
+	}
+	if md := sb.Metadata(); md != nil {
+		podLabels.pod = md.Name
+	}
+
+	b := &metricsBuilder{cached: ss.collectionPeriod != 0, labels: podLabels}
+	b.addCPUMetrics(stats.Linux.Cpu)
+	b.addMemoryMetrics(stats.Linux.Memory)
+	b.addNetworkMetrics(stats.Linux.Network)
+	b.addProcessMetrics(stats.Linux.Process)
+	podMetrics.Metrics = b.metrics
+
+	// The sandbox stores its containers by name, while the stats only carry the ID.
+	ctrs := make(map[string]*oci.Container, len(stats.Linux.Containers))
+	for _, c := range sb.Containers().List() {
+		ctrs[c.ID()] = c
+	}
+
+	for _, cStats := range stats.Linux.Containers {
+		if cStats == nil || cStats.Attributes == nil {
+			continue
+		}
+		ctrLabels := &metricLabels{
+			id:        cStats.Attributes.Id,
+			namespace: podLabels.namespace,
+			pod:       podLabels.pod,
+		}
+		if md := cStats.Attributes.Metadata; md != nil {
+			ctrLabels.container = md.Name
+		}
+		if name, ok := cStats.Attributes.Labels[kubernetesContainerNameLabel]; ok {
+			ctrLabels.container = name
+		}
+		if c, ok := ctrs[cStats.Attributes.Id]; ok {
+			ctrLabels.name = c.Name()
+			ctrLabels.image = c.ImageName()
+		}
+
+		b := &metricsBuilder{cached: ss.collectionPeriod != 0, labels: ctrLabels}
+		b.addCPUMetrics(cStats.Cpu)
+		b.addMemoryMetrics(cStats.Memory)
+		b.addFilesystemMetrics(cStats.WritableLayer)
+		podMetrics.ContainerMetrics = append(podMetrics.ContainerMetrics, &types.ContainerMetrics{
+			ContainerId: cStats.Attributes.Id,
+			Metrics:     b.metrics,
+		})
+	}
+
+	return podMetrics
+}
+
+// metricLabels are the values of the base label keys for a pod or container.
+type metricLabels struct {
+	container, id, image, name, namespace, pod string
+}
+
+// values returns the label values in the order of baseLabelKeys, followed by
+// the provided extra values.
+func (l *metricLabels) values(extra ...string) []string {
+	return append([]string{l.container, l.id, l.image, l.name, l.namespace, l.pod}, extra...)
+}
+
+// metricsBuilder accumulates CRI metrics for a single pod or container.
+type metricsBuilder struct {
+	// cached specifies if the stats have been gathered by the update loop
+	// rather than live, in which case the timestamp of the stats is used.
+	cached  bool
+	labels  *metricLabels
+	metrics []*types.Metric
+}
+
+// add appends a new metric to the builder.
+func (b *metricsBuilder) add(name string, metricType types.MetricType, timestamp int64, value uint64, extraLabels ...string) {
+	if !b.cached {
+		timestamp = 0
+	}
+	b.metrics = append(b.metrics, &types.Metric{
+		Name:        name,
+		Timestamp:   timestamp,
+		MetricType:  metricType,
+		LabelValues: b.labels.values(extraLabels...),
+		Value:       &types.UInt64Value{Value: value},
+	})
+}
+
+func (b *metricsBuilder) addCPUMetrics(cpu *types.CpuUsage) {
+	if cpu == nil || cpu.UsageCoreNanoSeconds == nil {
+		return
+	}
+	// The CRI metric value is an integer, so round instead of truncating the
+	// consumed CPU time to full seconds.
+	seconds := float64(cpu.UsageCoreNanoSeconds.Value) / float64(time.Second)
+	b.add(metricCPUUsageSecondsTotal, types.MetricType_COUNTER, cpu.Timestamp,
+		uint64(math.Round(seconds)))
+}
+
+func (b *metricsBuilder) addMemoryMetrics(memory *types.MemoryUsage) {
+	if memory == nil {
+		return
+	}
+	if memory.UsageBytes != nil {
+		b.add(metricMemoryUsageBytes, types.MetricType_GAUGE, memory.Timestamp, memory.UsageBytes.Value)
+	}
+	if memory.WorkingSetBytes != nil {
+		b.add(metricMemoryWorkingSetBytes, types.MetricType_GAUGE, memory.Timestamp, memory.WorkingSetBytes.Value)
+	}
+	if memory.RssBytes != nil {
+		b.add(metricMemoryRSS, types.MetricType_GAUGE, memory.Timestamp, memory.RssBytes.Value)
+	}
+	if memory.PageFaults != nil {
+		b.add(metricMemoryFailuresTotal, types.MetricType_COUNTER, memory.Timestamp, memory.PageFaults.Value,
+			memoryFailureTypePgFault, memoryFailureScopeContainer)
+	}
+	if memory.MajorPageFaults != nil {
+		b.add(metricMemoryFailuresTotal, types.MetricType_COUNTER, memory.Timestamp, memory.MajorPageFaults.Value,
+			memoryFailureTypePgMajFault, memoryFailureScopeContainer)
+	}
+}
+
+func (b *metricsBuilder) addNetworkMetrics(network *types.NetworkUsage) {
+	if network == nil {
+		return
+	}
+	ifaces := network.Interfaces
+	if network.DefaultInterface != nil {
+		ifaces = append([]*types.NetworkInterfaceUsage{network.DefaultInterface}, ifaces...)
+	}
+	for _, iface := range ifaces {
+		if iface == nil {
+			continue
+		}
+		for _, m := range []struct {
+			name  string
+			value *types.UInt64Value
+		}{
+			{metricNetworkReceiveBytesTotal, iface.RxBytes},
+			{metricNetworkReceiveErrorsTotal, iface.RxErrors},
+			{metricNetworkTransmitBytesTotal, iface.TxBytes},
+			{metricNetworkTransmitErrorsTotal, iface.TxErrors},
+		} {
+			if m.value == nil {
+				continue
+			}
+			b.add(m.name, types.MetricType_COUNTER, network.Timestamp, m.value.Value, iface.Name)
+		}
+	}
+}
+
+func (b *metricsBuilder) addProcessMetrics(process *types.ProcessUsage) {
+	if process == nil || process.ProcessCount == nil {
+		return
+	}
+	b.add(metricProcesses, types.MetricType_GAUGE, process.Timestamp, process.ProcessCount.Value)
+}
+
+func (b *metricsBuilder) addFilesystemMetrics(fs *types.FilesystemUsage) {
+	if fs == nil {
+		return
+	}
+	device := ""
+	if fs.FsId != nil {
+		device = fs.FsId.Mountpoint
+	}
+	if fs.UsedBytes != nil {
+		b.add(metricFsUsageBytes, types.MetricType_GAUGE, fs.Timestamp, fs.UsedBytes.Value, device)
+	}
+	if fs.InodesUsed != nil {
+		b.add(metricFsInodesUsed, types.MetricType_GAUGE, fs.Timestamp, fs.InodesUsed.Value, device)
+	}
+}
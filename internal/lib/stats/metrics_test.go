@@ -0,0 +1,89 @@
+package statsserver
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// The actual test suite
+var _ = t.Describe("Metrics", func() {
+	t.Describe("addCPUMetrics", func() {
+		var b *metricsBuilder
+
+		BeforeEach(func() {
+			b = &metricsBuilder{labels: &metricLabels{id: "id"}}
+		})
+
+		It("should report the CPU usage in seconds", func() {
+			// Given
+			cpu := &types.CpuUsage{
+				Timestamp:            1,
+				UsageCoreNanoSeconds: &types.UInt64Value{Value: 2_600_000_000},
+			}
+
+			// When
+			b.addCPUMetrics(cpu)
+
+			// Then
+			Expect(b.metrics).To(HaveLen(1))
+			Expect(b.metrics[0].Name).To(Equal(metricCPUUsageSecondsTotal))
+			Expect(b.metrics[0].MetricType).To(Equal(types.MetricType_COUNTER))
+			Expect(b.metrics[0].Value.Value).To(BeEquivalentTo(3))
+		})
+
+		It("should not truncate less than a second of CPU usage", func() {
+			// Given
+			cpu := &types.CpuUsage{
+				UsageCoreNanoSeconds: &types.UInt64Value{Value: 900_000_000},
+			}
+
+			// When
+			b.addCPUMetrics(cpu)
+
+			// Then
+			Expect(b.metrics).To(HaveLen(1))
+			Expect(b.metrics[0].Value.Value).To(BeEquivalentTo(1))
+		})
+
+		It("should not report without CPU usage", func() {
+			// Given
+			cpu := &types.CpuUsage{}
+
+			// When
+			b.addCPUMetrics(cpu)
+
+			// Then
+			Expect(b.metrics).To(BeEmpty())
+		})
+	})
+
+	t.Describe("addFilesystemMetrics", func() {
+		var b *metricsBuilder
+
+		BeforeEach(func() {
+			b = &metricsBuilder{labels: &metricLabels{id: "id"}}
+		})
+
+		It("should report the used bytes and inodes", func() {
+			// Given
+			fs := &types.FilesystemUsage{
+				Timestamp:  1,
+				FsId:       &types.FilesystemIdentifier{Mountpoint: "/var/lib/containers"},
+				UsedBytes:  &types.UInt64Value{Value: 4096},
+				InodesUsed: &types.UInt64Value{Value: 12},
+			}
+
+			// When
+			b.addFilesystemMetrics(fs)
+
+			// Then
+			Expect(b.metrics).To(HaveLen(2))
+			Expect(b.metrics[0].Name).To(Equal(metricFsUsageBytes))
+			Expect(b.metrics[0].Value.Value).To(BeEquivalentTo(4096))
+			Expect(b.metrics[1].Name).To(Equal(metricFsInodesUsed))
+			Expect(b.metrics[1].MetricType).To(Equal(types.MetricType_GAUGE))
+			Expect(b.metrics[1].Value.Value).To(BeEquivalentTo(12))
+		})
+	})
+})
@@ -9,6 +9,7 @@ import (
 
 	"github.com/containernetworking/plugins/pkg/ns"
 	cstorage "github.com/containers/storage"
+	"github.com/cri-o/cri-o/internal/imagevolume"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/oci"
 	"github.com/cri-o/cri-o/pkg/config"
@@ -39,6 +40,7 @@ type parentServerIface interface {
 	ListSandboxes() []*sandbox.Sandbox
 	GetSandbox(string) *sandbox.Sandbox
 	Config() *config.Config
+	ImageVolumes() *imagevolume.Manager
 }
 
 // New returns a new StatsServer, deriving the needed information from the provided parentServerIface.
@@ -103,6 +105,9 @@ func (ss *StatsServer) updateSandbox(sb *sandbox.Sandbox) *types.PodSandboxStats
 	if err := ss.Config().CgroupManager().PopulateSandboxCgroupStats(sb.CgroupParent(), sandboxStats); err != nil {
 		logrus.Errorf("Error getting sandbox stats %s: %v", sb.ID(), err)
 	}
+	if err := ss.populateMonitorUsage(sandboxStats, sb); err != nil {
+		logrus.Errorf("Error adding monitor stats for sandbox %s: %v", sb.ID(), err)
+	}
 	if err := ss.populateNetworkUsage(sandboxStats, sb); err != nil {
 		logrus.Errorf("Error adding network stats for sandbox %s: %v", sb.ID(), err)
 	}
@@ -181,6 +186,7 @@ func (ss *StatsServer) populateWritableLayer(stats *types.ContainerStats, contai
 
 // writableLayerForContainer gathers information about the container's writable layer.
 // It does so by calling into the GraphDriver's endpoint to get the UsedBytes and InodesUsed.
+// The usage of the image volumes of the container is accounted to the writable layer as well.
 func (ss *StatsServer) writableLayerForContainer(container *oci.Container) (*types.FilesystemUsage, error) {
 	writableLayer := &types.FilesystemUsage{
 		Timestamp: time.Now().UnixNano(),
@@ -201,9 +207,32 @@ func (ss *StatsServer) writableLayerForContainer(container *oci.Container) (*typ
 	writableLayer.UsedBytes = &types.UInt64Value{Value: uint64(usage.Size)}
 	writableLayer.InodesUsed = &types.UInt64Value{Value: uint64(usage.InodeCount)}
 
+	volumesUsage, err := ss.ImageVolumes().Usage(container.ID())
+	if err != nil {
+		return writableLayer, fmt.Errorf("unable to get image volumes disk usage for container %s: %w", container.ID(), err)
+	}
+	writableLayer.UsedBytes.Value += uint64(volumesUsage.Size)
+	writableLayer.InodesUsed.Value += uint64(volumesUsage.InodeCount)
+
 	return writableLayer, nil
 }
 
+// populateMonitorUsage adds the usage of the conmon-rs server of a sandbox using
+// the pod runtime, in case it is not accounted for by the sandbox cgroup.
+func (ss *StatsServer) populateMonitorUsage(stats *types.PodSandboxStats, sb *sandbox.Sandbox) error {
+	runtimeHandler := sb.RuntimeHandler()
+	if runtimeHandler == "" {
+		runtimeHandler = ss.Config().DefaultRuntime
+	}
+	handler, ok := ss.Config().Runtimes[runtimeHandler]
+	if !ok || handler.RuntimeType != config.RuntimeTypePod || sb.InfraContainer() == nil {
+		return nil
+	}
+	return ss.Config().CgroupManager().PopulateConmonCgroupStats(
+		sb.InfraContainer().ID(), sb.CgroupParent(), handler.MonitorCgroup, stats,
+	)
+}
+
 // populateNetworkUsage gathers information about the network from within the sandbox's network namespace.
 func (ss *StatsServer) populateNetworkUsage(stats *types.PodSandboxStats, sb *sandbox.Sandbox) error {
 	return ns.WithNetNSPath(sb.NetNsPath(), func(_ ns.NetNS) error {
@@ -213,6 +242,7 @@ func (ss *StatsServer) populateNetworkUsage(stats *types.PodSandboxStats, sb *sa
 			return err
 		}
 		stats.Linux.Network = &types.NetworkUsage{
+			Timestamp:  time.Now().UnixNano(),
 			Interfaces: make([]*types.NetworkInterfaceUsage, 0, len(links)-1),
 		}
 		for i := range links {
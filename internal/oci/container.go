@@ -71,6 +71,7 @@ type Container struct {
 	created            bool
 	spoofed            bool
 	stopping           bool
+	exitHandled        bool
 	stopLock           sync.Mutex
 	stopTimeoutChan    chan int64
 	stopWatchers       []chan struct{}
@@ -319,13 +320,12 @@ func (c *Container) ID() string {
 	return c.criContainer.Id
 }
 
-// CleanupConmonCgroup cleans up conmon's group when using cgroupfs.
+// CleanupConmonCgroup cleans up conmon's group when using cgroupfs. This
+// includes spoofed infra containers of the pod runtime, whose conmon-rs got
+// moved into a dedicated cgroup.
 func (c *Container) CleanupConmonCgroup(ctx context.Context) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
-	if c.spoofed {
-		return
-	}
 	path := c.ConmonCgroupfsPath()
 	if path == "" {
 		return
@@ -631,6 +631,19 @@ func (c *Container) SetAsStopping() (setToStopping bool) {
 	return false
 }
 
+// SetAsExitHandled marks the exit of the container as handled, because it
+// can be reported by both the exit notifier and the exit file.
+// Returns true if the exit was not handled before, and false otherwise.
+func (c *Container) SetAsExitHandled() (setToHandled bool) {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+	if !c.exitHandled {
+		c.exitHandled = true
+		return true
+	}
+	return false
+}
+
 func (c *Container) WaitOnStopTimeout(ctx context.Context, timeout int64) {
 	c.stopLock.Lock()
 	if !c.stopping {
@@ -171,6 +171,17 @@ var _ = t.Describe("Container", func() {
 		Expect(sut.RestoreArchive()).To(Equal(restoreArchive))
 	})
 
+	It("should succeed to set the exit as handled only once", func() {
+		// Given
+		// When
+		first := sut.SetAsExitHandled()
+		second := sut.SetAsExitHandled()
+
+		// Then
+		Expect(first).To(BeTrue())
+		Expect(second).To(BeFalse())
+	})
+
 	It("should succeed to set start failed with nil error", func() {
 		// Given
 		// When
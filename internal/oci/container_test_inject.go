@@ -7,6 +7,9 @@
 package oci
 
 import (
+	"io"
+	"net"
+
 	"github.com/cri-o/cri-o/pkg/config"
 )
 
@@ -42,3 +45,14 @@ func NewRuntimeOCI(r *Runtime, handler *config.RuntimeHandler) RuntimeOCI {
 		},
 	}
 }
+
+// NewRuntimeVM creates a new runtimeVM, which starts the shim binary at the
+// provided path.
+func NewRuntimeVM(path, root, exitsPath string) RuntimeImpl {
+	return newRuntimeVM(path, root, "", exitsPath)
+}
+
+// NewDatagramStream creates a new stream framing the datagrams of conn
+func NewDatagramStream(conn net.Conn) io.ReadWriteCloser {
+	return newDatagramStream(conn)
+}
@@ -0,0 +1,31 @@
+package oci
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrExitNotifierUnsupported is returned if the exit notifier is not supported
+// by the platform or kernel.
+var ErrExitNotifierUnsupported = errors.New("exit notifier is not supported")
+
+var (
+	exitNotifierOnce sync.Once
+	exitNotifier     *ExitNotifier
+)
+
+// defaultExitNotifier returns the exit notifier shared by all runtimes, or nil
+// if it is not supported. Callers have to fall back to polling in that case.
+func defaultExitNotifier() *ExitNotifier {
+	exitNotifierOnce.Do(func() {
+		notifier, err := NewExitNotifier()
+		if err != nil {
+			logrus.Infof("Falling back to polling for container exits: %v", err)
+			return
+		}
+		exitNotifier = notifier
+	})
+	return exitNotifier
+}
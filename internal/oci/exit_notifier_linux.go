@@ -0,0 +1,190 @@
+package oci
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// ExitNotifier notifies about process exits by using pidfds, which become
+// readable as soon as the process has exited. All pidfds are waited for by a
+// single epoll instance, which avoids polling the processes.
+type ExitNotifier struct {
+	epfd int
+	// wakefd is an eventfd used to interrupt the epoll loop on Close.
+	wakefd int
+
+	mutex   sync.Mutex
+	watches map[int32]chan struct{}
+	closed  bool
+}
+
+// NewExitNotifier creates a new exit notifier. It returns
+// ErrExitNotifierUnsupported if the kernel does not support pidfds.
+func NewExitNotifier() (*ExitNotifier, error) {
+	if err := probePidfd(); err != nil {
+		return nil, err
+	}
+
+	epfd, err := unix.EpollCreate1(unix.EPOLL_CLOEXEC)
+	if err != nil {
+		return nil, fmt.Errorf("create epoll instance: %w", err)
+	}
+	wakefd, err := unix.Eventfd(0, unix.EFD_CLOEXEC|unix.EFD_NONBLOCK)
+	if err != nil {
+		unix.Close(epfd)
+		return nil, fmt.Errorf("create eventfd: %w", err)
+	}
+	if err := unix.EpollCtl(epfd, unix.EPOLL_CTL_ADD, wakefd, &unix.EpollEvent{
+		Events: unix.EPOLLIN,
+		Fd:     int32(wakefd),
+	}); err != nil {
+		unix.Close(wakefd)
+		unix.Close(epfd)
+		return nil, fmt.Errorf("add eventfd to epoll instance: %w", err)
+	}
+
+	n := &ExitNotifier{
+		epfd:    epfd,
+		wakefd:  wakefd,
+		watches: make(map[int32]chan struct{}),
+	}
+	go n.loop()
+	return n, nil
+}
+
+// probePidfd verifies that the kernel supports pidfds by opening one for the
+// current process.
+func probePidfd() error {
+	fd, err := unix.PidfdOpen(unix.Getpid(), 0)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrExitNotifierUnsupported, err)
+	}
+	return unix.Close(fd)
+}
+
+// Watch starts watching the process with the provided PID. The returned
+// channel gets closed as soon as the process has exited, which is right away
+// if the process does not exist. It may also get closed if the notifier fails,
+// which means that callers should verify that the process is gone. The
+// returned function stops watching the process without closing the channel.
+//
+// The PID may have been reused before the watch got established, which means
+// that callers have to verify the identity of the process after calling Watch.
+func (n *ExitNotifier) Watch(pid int) (exited <-chan struct{}, cancel func(), err error) {
+	done := make(chan struct{})
+
+	pidfd, err := unix.PidfdOpen(pid, 0)
+	if errors.Is(err, unix.ESRCH) {
+		close(done)
+		return done, func() {}, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("open pidfd for PID %d: %w", pid, err)
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if n.closed {
+		unix.Close(pidfd)
+		return nil, nil, errors.New("exit notifier is closed")
+	}
+	n.watches[int32(pidfd)] = done
+	if err := unix.EpollCtl(n.epfd, unix.EPOLL_CTL_ADD, pidfd, &unix.EpollEvent{
+		Events: unix.EPOLLIN,
+		Fd:     int32(pidfd),
+	}); err != nil {
+		delete(n.watches, int32(pidfd))
+		unix.Close(pidfd)
+		return nil, nil, fmt.Errorf("add pidfd for PID %d to epoll instance: %w", pid, err)
+	}
+
+	return done, func() {
+		n.mutex.Lock()
+		defer n.mutex.Unlock()
+		if n.watches[int32(pidfd)] == done {
+			n.remove(pidfd)
+		}
+	}, nil
+}
+
+// Close stops the exit notifier. Channels of processes which are still being
+// watched do not get closed.
+func (n *ExitNotifier) Close() error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if n.closed {
+		return nil
+	}
+	n.closed = true
+	for pidfd := range n.watches {
+		n.remove(int(pidfd))
+	}
+	// Wake up the loop, which closes the file descriptors afterwards.
+	_, err := unix.Write(n.wakefd, []byte{1, 0, 0, 0, 0, 0, 0, 0})
+	return err
+}
+
+// remove stops watching the pidfd. Must be called with the mutex held.
+func (n *ExitNotifier) remove(pidfd int) {
+	delete(n.watches, int32(pidfd))
+	if err := unix.EpollCtl(n.epfd, unix.EPOLL_CTL_DEL, pidfd, nil); err != nil {
+		logrus.Debugf("Unable to remove pidfd %d from epoll instance: %v", pidfd, err)
+	}
+	unix.Close(pidfd)
+}
+
+func (n *ExitNotifier) loop() {
+	defer func() {
+		unix.Close(n.wakefd)
+		unix.Close(n.epfd)
+	}()
+
+	events := make([]unix.EpollEvent, 128)
+	for {
+		count, err := unix.EpollWait(n.epfd, events, -1)
+		if err != nil {
+			if errors.Is(err, unix.EINTR) {
+				continue
+			}
+			logrus.Errorf("Waiting for process exits failed: %v", err)
+			n.fail()
+			return
+		}
+
+		n.mutex.Lock()
+		if n.closed {
+			n.mutex.Unlock()
+			return
+		}
+		for i := 0; i < count; i++ {
+			pidfd := events[i].Fd
+			done, ok := n.watches[pidfd]
+			if !ok {
+				continue
+			}
+			n.remove(int(pidfd))
+			close(done)
+		}
+		n.mutex.Unlock()
+	}
+}
+
+// fail closes the notifier after an unrecoverable error. The channels of all
+// watched processes get closed, so that callers stop waiting and verify the
+// process state on their own.
+func (n *ExitNotifier) fail() {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.closed = true
+	for pidfd, done := range n.watches {
+		n.remove(int(pidfd))
+		close(done)
+	}
+}
@@ -0,0 +1,89 @@
+package oci_test
+
+import (
+	"os/exec"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/oci"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("ExitNotifier", func() {
+	// The system under test
+	var sut *oci.ExitNotifier
+
+	BeforeEach(func() {
+		var err error
+		sut, err = oci.NewExitNotifier()
+		if err != nil {
+			Skip("exit notifier not supported: " + err.Error())
+		}
+	})
+
+	AfterEach(func() {
+		Expect(sut.Close()).To(BeNil())
+	})
+
+	startProcess := func() *exec.Cmd {
+		cmd := exec.Command("sleep", "10")
+		Expect(cmd.Start()).To(BeNil())
+		DeferCleanup(func() {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+		})
+		return cmd
+	}
+
+	It("should notify about process exits", func() {
+		// Given
+		cmd := startProcess()
+		exited, cancel, err := sut.Watch(cmd.Process.Pid)
+		Expect(err).To(BeNil())
+		defer cancel()
+		Consistently(exited, 50*time.Millisecond).ShouldNot(BeClosed())
+
+		// When
+		Expect(cmd.Process.Kill()).To(BeNil())
+
+		// Then
+		Eventually(exited).Should(BeClosed())
+	})
+
+	It("should notify right away if the process does not exist", func() {
+		// Given
+		// When
+		exited, cancel, err := sut.Watch(neverRunningPid)
+
+		// Then
+		Expect(err).To(BeNil())
+		defer cancel()
+		Expect(exited).To(BeClosed())
+	})
+
+	It("should not notify after the watch got canceled", func() {
+		// Given
+		cmd := startProcess()
+		exited, cancel, err := sut.Watch(cmd.Process.Pid)
+		Expect(err).To(BeNil())
+
+		// When
+		cancel()
+		Expect(cmd.Process.Kill()).To(BeNil())
+
+		// Then
+		Consistently(exited, 100*time.Millisecond).ShouldNot(BeClosed())
+	})
+
+	It("should fail to watch after close", func() {
+		// Given
+		Expect(sut.Close()).To(BeNil())
+
+		// When
+		_, _, err := sut.Watch(alwaysRunningPid)
+
+		// Then
+		Expect(err).NotTo(BeNil())
+	})
+})
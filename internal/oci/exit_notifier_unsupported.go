@@ -0,0 +1,23 @@
+//go:build !linux
+// +build !linux
+
+package oci
+
+// ExitNotifier notifies about process exits, which is not supported on this
+// platform.
+type ExitNotifier struct{}
+
+// NewExitNotifier returns ErrExitNotifierUnsupported on this platform.
+func NewExitNotifier() (*ExitNotifier, error) {
+	return nil, ErrExitNotifierUnsupported
+}
+
+// Watch returns ErrExitNotifierUnsupported on this platform.
+func (n *ExitNotifier) Watch(pid int) (exited <-chan struct{}, cancel func(), err error) {
+	return nil, nil, ErrExitNotifierUnsupported
+}
+
+// Close does nothing on this platform.
+func (n *ExitNotifier) Close() error {
+	return nil
+}
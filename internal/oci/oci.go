@@ -72,7 +72,7 @@ type RuntimeImpl interface {
 	AttachContainer(context.Context, *Container, io.Reader, io.WriteCloser, io.WriteCloser,
 		bool, <-chan remotecommand.TerminalSize) error
 	PortForwardContainer(context.Context, *Container, string,
-		int32, io.ReadWriteCloser) error
+		int32, types.Protocol, io.ReadWriteCloser) error
 	ReopenContainerLog(context.Context, *Container) error
 	CheckpointContainer(context.Context, *Container, *rspec.Spec, bool) error
 	RestoreContainer(context.Context, *Container, string, string) error
@@ -260,6 +260,35 @@ func (r *Runtime) StartContainer(ctx context.Context, c *Container) error {
 	return impl.StartContainer(ctx, c)
 }
 
+// WatchContainerExit returns a channel which gets closed as soon as the
+// process of the running container has exited. It returns nil if the exit
+// cannot be watched, for example for VM based runtimes or if the kernel does
+// not support pidfds. Callers have to rely on the exit files in that case.
+func (r *Runtime) WatchContainerExit(ctx context.Context, c *Container) <-chan struct{} {
+	ctx, span := log.StartSpan(ctx)
+	defer span.End()
+	impl, err := r.RuntimeImpl(c)
+	if err != nil {
+		return nil
+	}
+
+	var ociImpl *runtimeOCI
+	switch impl := impl.(type) {
+	case *runtimeOCI:
+		ociImpl = impl
+	case *runtimePod:
+		ociImpl = impl.oci
+	default:
+		return nil
+	}
+
+	c.opLock.Lock()
+	defer c.opLock.Unlock()
+	// The watch gets removed by the notifier as soon as the process exits.
+	exited, _ := ociImpl.watchExit(ctx, c)
+	return exited
+}
+
 // ExecContainer prepares a streaming endpoint to execute a command in the container.
 func (r *Runtime) ExecContainer(ctx context.Context, c *Container, cmd []string, stdin io.Reader, stdout, stderr io.WriteCloser, tty bool, resizeChan <-chan remotecommand.TerminalSize) error {
 	ctx, span := log.StartSpan(ctx)
@@ -404,8 +433,9 @@ func (r *Runtime) AttachContainer(ctx context.Context, c *Container, inputStream
 	return impl.AttachContainer(ctx, c, inputStream, outputStream, errorStream, tty, resizeChan)
 }
 
-// PortForwardContainer forwards the specified port provides statistics of a container.
-func (r *Runtime) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, stream io.ReadWriteCloser) error {
+// PortForwardContainer forwards the specified port and protocol into the
+// network namespace of a container.
+func (r *Runtime) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, protocol types.Protocol, stream io.ReadWriteCloser) error {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
 	impl, err := r.RuntimeImpl(c)
@@ -413,7 +443,7 @@ func (r *Runtime) PortForwardContainer(ctx context.Context, c *Container, netNsP
 		return err
 	}
 
-	return impl.PortForwardContainer(ctx, c, netNsPath, port, stream)
+	return impl.PortForwardContainer(ctx, c, netNsPath, port, protocol, stream)
 }
 
 // ReopenContainerLog reopens the log file of a container.
@@ -0,0 +1,190 @@
+package oci
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/cri-o/cri-o/internal/log"
+	"golang.org/x/net/context"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// maxDatagramSize is the maximum size of a forwarded UDP datagram, which is
+// limited by the 16 bit length prefix of the stream framing.
+const maxDatagramSize = 1<<16 - 1
+
+// portForward forwards the specified port and protocol inside the network
+// namespace to the provided stream.
+//
+// TCP and SCTP connections are forwarded as byte streams. UDP datagrams are
+// framed on the stream by prefixing every datagram with its length as 16 bit
+// big endian integer, in both directions.
+func portForward(ctx context.Context, c *Container, netNsPath string, port int32, protocol types.Protocol, stream io.ReadWriteCloser) error {
+	log.Infof(ctx,
+		"Starting %s port forward for %s in network namespace %s", protocol, c.ID(), netNsPath,
+	)
+
+	// Adapted reference implementation:
+	// https://github.com/containerd/cri/blob/8c366d/pkg/server/sandbox_portforward_unix.go#L65-L120
+	if err := ns.WithNetNSPath(netNsPath, func(_ ns.NetNS) error {
+		defer stream.Close()
+
+		conn, err := dialPortForward(protocol, port)
+		if err != nil {
+			return fmt.Errorf("failed to connect to localhost:%d (%s) inside namespace %s: %w", port, protocol, c.ID(), err)
+		}
+		defer conn.Close()
+
+		errCh := make(chan error, 2)
+
+		debug := func(format string, args ...interface{}) {
+			log.Debugf(ctx, fmt.Sprintf(
+				"PortForward (id: %s, port: %d/%s): %s", c.ID(), port, protocol, format,
+			), args...)
+		}
+
+		// Copy from the namespace port connection to the client stream
+		go func() {
+			debug("copy data from container to client")
+			_, err := io.Copy(stream, conn)
+			errCh <- err
+		}()
+
+		// Copy from the client stream to the namespace port connection
+		go func() {
+			debug("copy data from client to container")
+			_, err := io.Copy(conn, stream)
+			errCh <- err
+		}()
+
+		// Wait until the first error is returned by one of the connections we
+		// use errFwd to store the result of the port forwarding operation if
+		// the context is cancelled close everything and return
+		var errFwd error
+		select {
+		case errFwd = <-errCh:
+			debug("stop forwarding in direction: %v", errFwd)
+		case <-ctx.Done():
+			debug("cancelled: %v", ctx.Err())
+			return ctx.Err()
+		}
+
+		// give a chance to terminate gracefully or timeout
+		const timeout = time.Second
+		select {
+		case e := <-errCh:
+			if errFwd == nil {
+				errFwd = e
+			}
+			debug("stopped forwarding in both directions")
+
+		case <-time.After(timeout):
+			debug("timed out waiting to close the connection")
+
+		case <-ctx.Done():
+			debug("cancelled: %v", ctx.Err())
+			errFwd = ctx.Err()
+		}
+
+		return errFwd
+	}); err != nil {
+		return fmt.Errorf(
+			"port forward into network namespace %q: %w", netNsPath, err,
+		)
+	}
+
+	log.Infof(ctx, "Finished port forwarding for %q on port %d/%s", c.ID(), port, protocol)
+	return nil
+}
+
+// dialPortForward connects to the port on localhost inside the current network
+// namespace.
+func dialPortForward(protocol types.Protocol, port int32) (io.ReadWriteCloser, error) {
+	// localhost can resolve to both IPv4 and IPv6 addresses in dual-stack systems
+	// but the application can be listening in one of the IP families only.
+	// golang has enabled RFC 6555 Fast Fallback (aka HappyEyeballs) by default in 1.12
+	// It means that if a host resolves to both IPv6 and IPv4, it will try to connect to any
+	// of those addresses and use the working connection.
+	// xref https://github.com/golang/go/commit/efc185029bf770894defe63cec2c72a4c84b2ee9
+	// However, the implementation uses go routines to start both connections in parallel,
+	// and this has limitations when running inside a namespace, so we try to the connections
+	// serially disabling the Fast Fallback support.
+	// xref https://github.com/golang/go/issues/44922
+	var d net.Dialer
+	d.FallbackDelay = -1
+
+	switch protocol {
+	case types.Protocol_TCP:
+		return d.Dial("tcp", fmt.Sprintf("localhost:%d", port))
+
+	case types.Protocol_UDP:
+		conn, err := d.Dial("udp", fmt.Sprintf("localhost:%d", port))
+		if err != nil {
+			return nil, err
+		}
+		return newDatagramStream(conn), nil
+
+	case types.Protocol_SCTP:
+		return dialSCTP(port)
+	}
+
+	return nil, fmt.Errorf("unsupported protocol %s", protocol)
+}
+
+// datagramStream adapts a datagram connection to a byte stream, where every
+// datagram is prefixed by its length as 16 bit big endian integer.
+type datagramStream struct {
+	conn net.Conn
+
+	// readBuf contains the framed datagram not yet read from the stream.
+	readBuf []byte
+	// writeBuf contains the incomplete frame written to the stream.
+	writeBuf []byte
+}
+
+func newDatagramStream(conn net.Conn) *datagramStream {
+	return &datagramStream{conn: conn}
+}
+
+// Read reads the next framed datagram from the connection.
+func (d *datagramStream) Read(p []byte) (int, error) {
+	if len(d.readBuf) == 0 {
+		buf := make([]byte, 2+maxDatagramSize)
+		n, err := d.conn.Read(buf[2:])
+		if err != nil {
+			return 0, err
+		}
+		binary.BigEndian.PutUint16(buf, uint16(n))
+		d.readBuf = buf[:2+n]
+	}
+
+	n := copy(p, d.readBuf)
+	d.readBuf = d.readBuf[n:]
+	return n, nil
+}
+
+// Write sends every complete frame as datagram to the connection and keeps
+// the remaining bytes for the next write.
+func (d *datagramStream) Write(p []byte) (int, error) {
+	d.writeBuf = append(d.writeBuf, p...)
+	for len(d.writeBuf) >= 2 {
+		size := int(binary.BigEndian.Uint16(d.writeBuf))
+		if len(d.writeBuf) < 2+size {
+			break
+		}
+		if _, err := d.conn.Write(d.writeBuf[2 : 2+size]); err != nil {
+			return 0, err
+		}
+		d.writeBuf = append(d.writeBuf[:0], d.writeBuf[2+size:]...)
+	}
+	return len(p), nil
+}
+
+// Close closes the underlying connection.
+func (d *datagramStream) Close() error {
+	return d.conn.Close()
+}
@@ -0,0 +1,50 @@
+package oci
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// dialSCTP connects to the SCTP port on localhost inside the current network
+// namespace, trying IPv4 first and IPv6 afterwards.
+func dialSCTP(port int32) (io.ReadWriteCloser, error) {
+	ipv6 := &unix.SockaddrInet6{Port: int(port)}
+	copy(ipv6.Addr[:], net.IPv6loopback)
+	addresses := []struct {
+		family int
+		addr   unix.Sockaddr
+	}{
+		{unix.AF_INET, &unix.SockaddrInet4{Port: int(port), Addr: [4]byte{127, 0, 0, 1}}},
+		{unix.AF_INET6, ipv6},
+	}
+
+	var errs []error
+	for _, address := range addresses {
+		fd, err := unix.Socket(address.family, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, unix.IPPROTO_SCTP)
+		if errors.Is(err, unix.EPROTONOSUPPORT) || errors.Is(err, unix.ESOCKTNOSUPPORT) {
+			return nil, fmt.Errorf("SCTP is not supported by the kernel: %w", err)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("create socket: %w", err))
+			continue
+		}
+		if err := unix.Connect(fd, address.addr); err != nil {
+			unix.Close(fd)
+			errs = append(errs, fmt.Errorf("connect: %w", err))
+			continue
+		}
+		// Use the runtime poller, which allows closing the file while
+		// reading from it.
+		if err := unix.SetNonblock(fd, true); err != nil {
+			unix.Close(fd)
+			return nil, fmt.Errorf("set socket non-blocking: %w", err)
+		}
+		return os.NewFile(uintptr(fd), fmt.Sprintf("sctp:localhost:%d", port)), nil
+	}
+	return nil, errors.Join(errs...)
+}
@@ -0,0 +1,77 @@
+package oci_test
+
+import (
+	"net"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/oci"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("DatagramStream", func() {
+	var (
+		server *net.UDPConn
+		client net.Conn
+	)
+
+	BeforeEach(func() {
+		var err error
+		server, err = net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		Expect(err).To(BeNil())
+		client, err = net.Dial("udp", server.LocalAddr().String())
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		Expect(server.Close()).To(BeNil())
+	})
+
+	It("should send complete frames as datagrams", func() {
+		// Given
+		sut := oci.NewDatagramStream(client)
+		defer sut.Close()
+
+		// When
+		_, err := sut.Write([]byte{0, 5, 'h', 'e'})
+		Expect(err).To(BeNil())
+		_, err = sut.Write([]byte{'l', 'l', 'o', 0, 2, 'h', 'i'})
+		Expect(err).To(BeNil())
+
+		// Then
+		buf := make([]byte, 16)
+		Expect(server.SetReadDeadline(time.Now().Add(time.Second))).To(Succeed())
+		n, _, err := server.ReadFromUDP(buf)
+		Expect(err).To(BeNil())
+		Expect(string(buf[:n])).To(Equal("hello"))
+		n, _, err = server.ReadFromUDP(buf)
+		Expect(err).To(BeNil())
+		Expect(string(buf[:n])).To(Equal("hi"))
+	})
+
+	It("should frame received datagrams", func() {
+		// Given
+		sut := oci.NewDatagramStream(client)
+		defer sut.Close()
+		_, err := sut.Write([]byte{0, 4, 'p', 'i', 'n', 'g'})
+		Expect(err).To(BeNil())
+		buf := make([]byte, 16)
+		Expect(server.SetReadDeadline(time.Now().Add(time.Second))).To(Succeed())
+		_, addr, err := server.ReadFromUDP(buf)
+		Expect(err).To(BeNil())
+
+		// When
+		_, err = server.WriteToUDP([]byte("pong"), addr)
+		Expect(err).To(BeNil())
+
+		// Then
+		res := make([]byte, 3)
+		n, err := sut.Read(res)
+		Expect(err).To(BeNil())
+		Expect(res[:n]).To(Equal([]byte{0, 4, 'p'}))
+		n, err = sut.Read(res)
+		Expect(err).To(BeNil())
+		Expect(string(res[:n])).To(Equal("ong"))
+	})
+})
@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package oci
+
+import (
+	"errors"
+	"io"
+)
+
+func dialSCTP(port int32) (io.ReadWriteCloser, error) {
+	return nil, errors.New("SCTP is not supported on this platform")
+}
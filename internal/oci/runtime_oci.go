@@ -15,7 +15,6 @@ import (
 	"time"
 
 	metadata "github.com/checkpoint-restore/checkpointctl/lib"
-	"github.com/containernetworking/plugins/pkg/ns"
 	conmonconfig "github.com/containers/conmon/runner/config"
 	"github.com/containers/podman/v4/pkg/checkpoint/crutils"
 	"github.com/containers/podman/v4/pkg/criu"
@@ -835,8 +834,22 @@ func (r *runtimeOCI) StopLoopForContainer(c *Container) {
 		}
 	}
 
+	// Wait for the exit notification if supported, which avoids polling the
+	// process. Polling is still used afterwards to verify that the process is
+	// gone, and serves as fallback otherwise.
+	exited, cancelExitWatch := r.watchExit(ctx, c)
 	done := make(chan struct{})
+	// stopped gets closed once the stop loop has finished, because a
+	// cancelled exit watch does not close the exited channel.
+	stopped := make(chan struct{})
 	go func() {
+		if exited != nil {
+			select {
+			case <-exited:
+			case <-stopped:
+				return
+			}
+		}
 		for {
 			if err := c.Living(); err != nil {
 				// The initial container process either doesn't exist, or isn't ours.
@@ -847,7 +860,11 @@ func (r *runtimeOCI) StopLoopForContainer(c *Container) {
 				return
 			}
 			// the PID is still active and belongs to the container, continue to wait
-			time.Sleep(100 * time.Millisecond)
+			select {
+			case <-time.After(100 * time.Millisecond):
+			case <-stopped:
+				return
+			}
 		}
 	}()
 
@@ -883,6 +900,8 @@ func (r *runtimeOCI) StopLoopForContainer(c *Container) {
 		}
 	}
 
+	close(stopped)
+	cancelExitWatch()
 	c.state.Finished = time.Now()
 	c.opLock.Unlock()
 
@@ -895,6 +914,29 @@ func (r *runtimeOCI) StopLoopForContainer(c *Container) {
 	c.stopLock.Unlock()
 }
 
+// watchExit watches the exit of the container process by using the exit
+// notifier. It returns a nil channel if the notifier is not supported or the
+// process cannot be watched. The returned function has to be called to stop
+// watching. Must be called with the container opLock held.
+func (r *runtimeOCI) watchExit(ctx context.Context, c *Container) (exited <-chan struct{}, cancel func()) {
+	notifier := defaultExitNotifier()
+	if notifier == nil || c.state == nil || c.state.InitPid <= 0 {
+		return nil, func() {}
+	}
+	exited, cancel, err := notifier.Watch(c.state.InitPid)
+	if err != nil {
+		log.Debugf(ctx, "Unable to watch exit of container %s, falling back to polling: %v", c.ID(), err)
+		return nil, func() {}
+	}
+	// The pidfd pins the process, so verify that the PID has not been reused
+	// before the watch got established.
+	if err := c.Living(); err != nil {
+		cancel()
+		return nil, func() {}
+	}
+	return exited, cancel
+}
+
 // DeleteContainer deletes a container.
 func (r *runtimeOCI) DeleteContainer(ctx context.Context, c *Container) error {
 	_, span := log.StartSpan(ctx)
@@ -1188,96 +1230,10 @@ func (r *runtimeOCI) AttachContainer(ctx context.Context, c *Container, inputStr
 }
 
 // PortForwardContainer forwards the specified port into the provided container.
-func (r *runtimeOCI) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, stream io.ReadWriteCloser) error {
+func (r *runtimeOCI) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, protocol types.Protocol, stream io.ReadWriteCloser) error {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
-	log.Infof(ctx,
-		"Starting port forward for %s in network namespace %s", c.ID(), netNsPath,
-	)
-
-	// Adapted reference implementation:
-	// https://github.com/containerd/cri/blob/8c366d/pkg/server/sandbox_portforward_unix.go#L65-L120
-	if err := ns.WithNetNSPath(netNsPath, func(_ ns.NetNS) error {
-		defer stream.Close()
-
-		// localhost can resolve to both IPv4 and IPv6 addresses in dual-stack systems
-		// but the application can be listening in one of the IP families only.
-		// golang has enabled RFC 6555 Fast Fallback (aka HappyEyeballs) by default in 1.12
-		// It means that if a host resolves to both IPv6 and IPv4, it will try to connect to any
-		// of those addresses and use the working connection.
-		// xref https://github.com/golang/go/commit/efc185029bf770894defe63cec2c72a4c84b2ee9
-		// However, the implementation uses go routines to start both connections in parallel,
-		// and this has limitations when running inside a namespace, so we try to the connections
-		// serially disabling the Fast Fallback support.
-		// xref https://github.com/golang/go/issues/44922
-		var d net.Dialer
-		d.FallbackDelay = -1
-		conn, err := d.Dial("tcp", fmt.Sprintf("localhost:%d", port))
-		if err != nil {
-			return fmt.Errorf("failed to connect to localhost:%d inside namespace %s: %w", port, c.ID(), err)
-		}
-		defer conn.Close()
-
-		errCh := make(chan error, 2)
-
-		debug := func(format string, args ...interface{}) {
-			log.Debugf(ctx, fmt.Sprintf(
-				"PortForward (id: %s, port: %d): %s", c.ID(), port, format,
-			), args...)
-		}
-
-		// Copy from the namespace port connection to the client stream
-		go func() {
-			debug("copy data from container to client")
-			_, err := io.Copy(stream, conn)
-			errCh <- err
-		}()
-
-		// Copy from the client stream to the namespace port connection
-		go func() {
-			debug("copy data from client to container")
-			_, err := io.Copy(conn, stream)
-			errCh <- err
-		}()
-
-		// Wait until the first error is returned by one of the connections we
-		// use errFwd to store the result of the port forwarding operation if
-		// the context is cancelled close everything and return
-		var errFwd error
-		select {
-		case errFwd = <-errCh:
-			debug("stop forwarding in direction: %v", errFwd)
-		case <-ctx.Done():
-			debug("cancelled: %v", ctx.Err())
-			return ctx.Err()
-		}
-
-		// give a chance to terminate gracefully or timeout
-		const timeout = time.Second
-		select {
-		case e := <-errCh:
-			if errFwd == nil {
-				errFwd = e
-			}
-			debug("stopped forwarding in both directions")
-
-		case <-time.After(timeout):
-			debug("timed out waiting to close the connection")
-
-		case <-ctx.Done():
-			debug("cancelled: %v", ctx.Err())
-			errFwd = ctx.Err()
-		}
-
-		return errFwd
-	}); err != nil {
-		return fmt.Errorf(
-			"port forward into network namespace %q: %w", netNsPath, err,
-		)
-	}
-
-	log.Infof(ctx, "Finished port forwarding for %q on port %d", c.ID(), port)
-	return nil
+	return portForward(ctx, c, netNsPath, port, protocol, stream)
 }
 
 // ReopenContainerLog reopens the log file of a container.
@@ -13,6 +13,7 @@ import (
 	"github.com/containers/common/pkg/resize"
 	conmonClient "github.com/containers/conmon-rs/pkg/client"
 	conmonconfig "github.com/containers/conmon/runner/config"
+	"github.com/containers/podman/v4/pkg/annotations"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/internal/opentelemetry"
 	"github.com/cri-o/cri-o/pkg/config"
@@ -77,7 +78,23 @@ func newRuntimePod(r *Runtime, handler *config.RuntimeHandler, c *Container) (Ru
 	}
 	logrus.Debugf("Running conmonrs with PID: %d", client.PID())
 
-	// TODO FIXME we need to move conmon-rs to the new cgroup
+	// conmon-rs gets moved into the monitor cgroup on creation of the pod
+	// container, because the cgroup parent is not known before. A server
+	// started before a restart of CRI-O already lives in its cgroup, which
+	// is only remembered by the container for cleaning it up.
+	// The pod overhead is not applied to that cgroup: the kubelet already
+	// adds it to the limits of the pod cgroup, which contains conmon-rs if
+	// the monitor cgroup is "pod", and limiting conmon-rs itself to the
+	// overhead could get it killed instead of the containers it monitors.
+	if c.conmonCgroupfsPath == "" && !r.config.CgroupManager().IsSystemd() {
+		if cgroupParent, ok := c.Spec().Annotations[annotations.CgroupParent]; ok {
+			c.conmonCgroupfsPath, err = r.config.CgroupManager().ConmonCgroupAbsolutePath(c.ID(), cgroupParent, handler.MonitorCgroup)
+			if err != nil {
+				return nil, fmt.Errorf("get conmon-rs cgroup: %w", err)
+			}
+		}
+	}
+
 	return &runtimePod{
 		oci: &runtimeOCI{
 			Runtime: r,
@@ -170,6 +187,8 @@ func (r *runtimePod) RestoreContainer(
 	return r.oci.RestoreContainer(ctx, c, cgroupParent, mountLabel)
 }
 
+// ExecContainer runs the exec through the OCI runtime, because conmon-rs has no
+// RPC for streaming an exec session yet.
 func (r *runtimePod) ExecContainer(ctx context.Context, c *Container, cmd []string, stdin io.Reader, stdout, stderr io.WriteCloser, tty bool, resizeChan <-chan remotecommand.TerminalSize) error {
 	return r.oci.ExecContainer(ctx, c, cmd, stdin, stdout, stderr, tty, resizeChan)
 }
@@ -286,8 +305,10 @@ func (r *runtimePod) AttachContainer(ctx context.Context, c *Container, inputStr
 	})
 }
 
-func (r *runtimePod) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, stream io.ReadWriteCloser) error {
-	return r.oci.PortForwardContainer(ctx, c, netNsPath, port, stream)
+// PortForwardContainer forwards the port within CRI-O like the OCI runtime,
+// because conmon-rs has no RPC for port forwarding yet.
+func (r *runtimePod) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, protocol types.Protocol, stream io.ReadWriteCloser) error {
+	return r.oci.PortForwardContainer(ctx, c, netNsPath, port, protocol, stream)
 }
 
 func (r *runtimePod) ReopenContainerLog(ctx context.Context, c *Container) error {
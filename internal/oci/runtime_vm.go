@@ -152,6 +152,9 @@ func (r *runtimeVM) CreateContainer(ctx context.Context, c *Container, cgroupPar
 		Terminal: containerIO.Config().Terminal,
 		Options:  opts,
 	}
+	if restore {
+		request.Checkpoint = c.CheckpointPath()
+	}
 
 	createdCh := make(chan error)
 	go func() {
@@ -235,6 +238,12 @@ func (r *runtimeVM) startRuntimeDaemon(ctx context.Context, c *Container) error
 	address := strings.TrimSpace(string(out))
 
 	// Now the RPC server is running, let's connect to it
+	return r.connect(address)
+}
+
+// connect connects to the shim listening on the provided address and updates
+// the runtime structure to use it.
+func (r *runtimeVM) connect(address string) error {
 	conn, err := client.Connect(address, client.AnonDialer)
 	if err != nil {
 		return err
@@ -243,7 +252,6 @@ func (r *runtimeVM) startRuntimeDaemon(ctx context.Context, c *Container) error
 	options := ttrpc.WithOnClose(func() { conn.Close() })
 	cl := ttrpc.NewClient(conn, options)
 
-	// Update the runtime structure
 	r.client = cl
 	r.task = task.NewTaskClient(cl)
 
@@ -259,6 +267,12 @@ func (r *runtimeVM) StartContainer(ctx context.Context, c *Container) error {
 	c.opLock.Lock()
 	defer c.opLock.Unlock()
 
+	return r.startContainer(ctx, c)
+}
+
+// startContainer starts the container and watches for its termination.
+// It does **not** Lock the container, thus it's the caller responsibility to do so, when needed.
+func (r *runtimeVM) startContainer(ctx context.Context, c *Container) error {
 	if err := r.start(c.ID(), ""); err != nil {
 		return err
 	}
@@ -702,14 +716,9 @@ func (r *runtimeVM) updateContainerStatus(ctx context.Context, c *Container) err
 			return errors.New("runtime not correctly setup")
 		}
 		address := strings.TrimSpace(string(data))
-		conn, err := client.Connect(address, client.AnonDialer)
-		if err != nil {
+		if err := r.connect(address); err != nil {
 			return err
 		}
-		options := ttrpc.WithOnClose(func() { conn.Close() })
-		cl := ttrpc.NewClient(conn, options)
-		r.client = cl
-		r.task = task.NewTaskClient(cl)
 	}
 
 	response, err := r.task.State(r.ctx, &task.StateRequest{
@@ -816,15 +825,37 @@ func (r *runtimeVM) createContainerIO(ctx context.Context, c *Container, cioOpts
 		}
 	}()
 
-	f, err := os.OpenFile(c.LogPath(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	stdout, stderr, err := newContainerLogger(ctx, c)
 	if err != nil {
 		return nil, err
 	}
 
+	containerIO.AddOutput(c.LogPath(), stdout, stderr)
+	containerIO.Pipe()
+
+	r.Lock()
+	r.ctrs[c.ID()] = containerInfo{
+		cio: containerIO,
+	}
+	r.Unlock()
+
+	return containerIO, nil
+}
+
+// newContainerLogger opens the log file of the container and returns the
+// writers for the stdout and stderr streams of the container, which write into
+// the log file using the CRI log format. The log file is closed once both
+// writers are closed.
+func newContainerLogger(ctx context.Context, c *Container) (stdout, stderr io.WriteCloser, _ error) {
+	f, err := os.OpenFile(c.LogPath(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var stdoutCh, stderrCh <-chan struct{}
 	wc := cioutil.NewSerialWriteCloser(f)
-	stdout, stdoutCh := cio.NewCRILogger(c.LogPath(), wc, cio.Stdout, -1)
-	stderr, stderrCh := cio.NewCRILogger(c.LogPath(), wc, cio.Stderr, -1)
+	stdout, stdoutCh = cio.NewCRILogger(c.LogPath(), wc, cio.Stdout, -1)
+	stderr, stderrCh = cio.NewCRILogger(c.LogPath(), wc, cio.Stderr, -1)
 
 	go func() {
 		if stdoutCh != nil {
@@ -837,16 +868,7 @@ func (r *runtimeVM) createContainerIO(ctx context.Context, c *Container, cioOpts
 		f.Close()
 	}()
 
-	containerIO.AddOutput(c.LogPath(), stdout, stderr)
-	containerIO.Pipe()
-
-	r.Lock()
-	r.ctrs[c.ID()] = containerInfo{
-		cio: containerIO,
-	}
-	r.Unlock()
-
-	return containerIO, nil
+	return stdout, stderr, nil
 }
 
 // PauseContainer pauses a container.
@@ -1012,12 +1034,13 @@ func (r *runtimeVM) AttachContainer(ctx context.Context, c *Container, inputStre
 	return nil
 }
 
-// PortForwardContainer forwards the specified port provides statistics of a container.
-func (r *runtimeVM) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, stream io.ReadWriteCloser) error {
+// PortForwardContainer forwards the specified port and protocol into the
+// network namespace of a container.
+func (r *runtimeVM) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, protocol types.Protocol, stream io.ReadWriteCloser) error {
 	log.Debugf(ctx, "RuntimeVM.PortForwardContainer() start")
 	defer log.Debugf(ctx, "RuntimeVM.PortForwardContainer() end")
 
-	return nil
+	return portForward(ctx, c, netNsPath, port, protocol, stream)
 }
 
 // ReopenContainerLog reopens the log file of a container.
@@ -1025,6 +1048,30 @@ func (r *runtimeVM) ReopenContainerLog(ctx context.Context, c *Container) error
 	log.Debugf(ctx, "RuntimeVM.ReopenContainerLog() start")
 	defer log.Debugf(ctx, "RuntimeVM.ReopenContainerLog() end")
 
+	r.Lock()
+	cInfo, ok := r.ctrs[c.ID()]
+	r.Unlock()
+	if !ok {
+		return errors.New("could not retrieve container information")
+	}
+
+	stdout, stderr, err := newContainerLogger(ctx, c)
+	if err != nil {
+		return fmt.Errorf("reopen log file of container %s: %w", c.ID(), err)
+	}
+
+	// Replace the outputs writing into the previous log file, which gets
+	// closed together with them.
+	oldStdout, oldStderr := cInfo.cio.AddOutput(c.LogPath(), stdout, stderr)
+	for _, old := range []io.WriteCloser{oldStdout, oldStderr} {
+		if old == nil {
+			continue
+		}
+		if err := old.Close(); err != nil {
+			log.Warnf(ctx, "Failed to close previous log output of container %s: %v", c.ID(), err)
+		}
+	}
+
 	return nil
 }
 
@@ -1105,18 +1152,86 @@ func (r *runtimeVM) closeIO(ctrID, execID string) error {
 	return nil
 }
 
-// CheckpointContainer not implemented for runtimeVM
+// CheckpointContainer checkpoints a container through the shim. It fails if
+// the shim does not implement checkpointing.
 func (r *runtimeVM) CheckpointContainer(ctx context.Context, c *Container, specgen *rspec.Spec, leaveRunning bool) error {
 	log.Debugf(ctx, "RuntimeVM.CheckpointContainer() start")
 	defer log.Debugf(ctx, "RuntimeVM.CheckpointContainer() end")
 
-	return errors.New("checkpointing not implemented for runtimeVM")
+	// Lock the container
+	c.opLock.Lock()
+	defer c.opLock.Unlock()
+
+	log.Debugf(ctx, "Writing checkpoint to %s", c.CheckpointPath())
+	if _, err := r.task.Checkpoint(r.ctx, &task.CheckpointTaskRequest{
+		ID:   c.ID(),
+		Path: c.CheckpointPath(),
+	}); err != nil {
+		return shimUnsupportedError("checkpointing", errdefs.FromGRPC(err))
+	}
+	c.SetCheckpointedAt(time.Now())
+
+	if !leaveRunning {
+		// The task API always leaves the container running, which is why it
+		// has to be stopped after the checkpoint got written.
+		if err := r.kill(c.ID(), "", syscall.SIGKILL, true); err != nil {
+			return fmt.Errorf("stop checkpointed container: %w", err)
+		}
+		if _, err := r.wait(c.ID(), ""); err != nil && !errors.Is(err, errdefs.ErrNotFound) {
+			return fmt.Errorf("wait for checkpointed container: %w", err)
+		}
+		c.state.Status = ContainerStateStopped
+		c.state.ExitCode = utils.Int32Ptr(0)
+		c.state.Finished = c.CheckpointedAt()
+	}
+
+	return nil
 }
 
-// RestoreContainer not implemented for runtimeVM
+// RestoreContainer restores a container from its checkpoint through the
+// shim. It fails if the shim does not implement restoring.
 func (r *runtimeVM) RestoreContainer(ctx context.Context, c *Container, cgroupParent, mountLabel string) error {
 	log.Debugf(ctx, "RuntimeVM.RestoreContainer() start")
 	defer log.Debugf(ctx, "RuntimeVM.RestoreContainer() end")
 
-	return errors.New("restoring not implemented for runtimeVM")
+	// The checkpoint format depends on the shim, so only verify that there
+	// is a checkpoint at all.
+	if _, err := os.Stat(c.CheckpointPath()); err != nil {
+		return fmt.Errorf("a complete checkpoint for this container cannot be found, cannot restore: %w", err)
+	}
+
+	c.state.InitPid = 0
+	c.state.InitStartTime = ""
+
+	if err := r.CreateContainer(ctx, c, cgroupParent, true); err != nil {
+		return shimUnsupportedError("restoring", err)
+	}
+
+	// Lock the container
+	c.opLock.Lock()
+	defer c.opLock.Unlock()
+
+	// Starting the task created from the checkpoint restores its processes
+	if err := r.startContainer(ctx, c); err != nil {
+		if cleanupErr := r.deleteContainer(c, true); cleanupErr != nil {
+			log.Infof(ctx, "DeleteContainer failed for container %s: %v", c.ID(), cleanupErr)
+		}
+		return shimUnsupportedError("restoring", err)
+	}
+
+	// Once the container is restored, update the metadata
+	c.state.Status = ContainerStateRunning
+	c.state.Pid = c.state.InitPid
+	c.state.ExitCode = nil
+
+	return nil
+}
+
+// shimUnsupportedError annotates the error of the operation if it has been
+// caused by a shim not implementing the operation.
+func shimUnsupportedError(operation string, err error) error {
+	if errors.Is(err, errdefs.ErrNotImplemented) {
+		return fmt.Errorf("%s is not supported by the runtime shim: %w", operation, err)
+	}
+	return err
 }
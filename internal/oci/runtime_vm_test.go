@@ -0,0 +1,308 @@
+package oci_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/containerd/containerd/api/runtime/task/v2"
+	tasktypes "github.com/containerd/containerd/api/types/task"
+	"github.com/containerd/ttrpc"
+	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/utils/errdefs"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/types/known/emptypb"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// fakeShim is a task service recording the requests of the runtime.
+type fakeShim struct {
+	mutex         sync.Mutex
+	create        *task.CreateTaskRequest
+	checkpoint    *task.CheckpointTaskRequest
+	checkpointErr error
+	signals       []uint32
+	exited        chan struct{}
+	exitOnce      sync.Once
+}
+
+func newFakeShim() *fakeShim {
+	return &fakeShim{exited: make(chan struct{})}
+}
+
+func (f *fakeShim) exit() {
+	f.exitOnce.Do(func() { close(f.exited) })
+}
+
+func (f *fakeShim) createRequest() *task.CreateTaskRequest {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.create
+}
+
+func (f *fakeShim) State(context.Context, *task.StateRequest) (*task.StateResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	res := &task.StateResponse{Status: tasktypes.Status_RUNNING, Pid: uint32(os.Getpid())}
+	if f.create != nil {
+		res.Stdout, res.Stderr = f.create.Stdout, f.create.Stderr
+	}
+	select {
+	case <-f.exited:
+		res.Status = tasktypes.Status_STOPPED
+	default:
+	}
+	return res, nil
+}
+
+func (f *fakeShim) Create(_ context.Context, req *task.CreateTaskRequest) (*task.CreateTaskResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.create = req
+	return &task.CreateTaskResponse{Pid: uint32(os.Getpid())}, nil
+}
+
+func (f *fakeShim) Start(context.Context, *task.StartRequest) (*task.StartResponse, error) {
+	return &task.StartResponse{Pid: uint32(os.Getpid())}, nil
+}
+
+func (f *fakeShim) Delete(context.Context, *task.DeleteRequest) (*task.DeleteResponse, error) {
+	return &task.DeleteResponse{}, nil
+}
+
+func (f *fakeShim) Pids(context.Context, *task.PidsRequest) (*task.PidsResponse, error) {
+	return nil, errdefs.ToGRPC(errdefs.ErrNotImplemented)
+}
+
+func (f *fakeShim) Pause(context.Context, *task.PauseRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func (f *fakeShim) Resume(context.Context, *task.ResumeRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func (f *fakeShim) Checkpoint(_ context.Context, req *task.CheckpointTaskRequest) (*emptypb.Empty, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.checkpointErr != nil {
+		return nil, errdefs.ToGRPC(f.checkpointErr)
+	}
+	f.checkpoint = req
+	return &emptypb.Empty{}, nil
+}
+
+func (f *fakeShim) Kill(_ context.Context, req *task.KillRequest) (*emptypb.Empty, error) {
+	f.mutex.Lock()
+	f.signals = append(f.signals, req.Signal)
+	f.mutex.Unlock()
+	if syscall.Signal(req.Signal) == syscall.SIGKILL {
+		f.exit()
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (f *fakeShim) Exec(context.Context, *task.ExecProcessRequest) (*emptypb.Empty, error) {
+	return nil, errdefs.ToGRPC(errdefs.ErrNotImplemented)
+}
+
+func (f *fakeShim) ResizePty(context.Context, *task.ResizePtyRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func (f *fakeShim) CloseIO(context.Context, *task.CloseIORequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func (f *fakeShim) Update(context.Context, *task.UpdateTaskRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func (f *fakeShim) Wait(ctx context.Context, _ *task.WaitRequest) (*task.WaitResponse, error) {
+	select {
+	case <-f.exited:
+		return &task.WaitResponse{ExitStatus: 137}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (f *fakeShim) Stats(context.Context, *task.StatsRequest) (*task.StatsResponse, error) {
+	return nil, errdefs.ToGRPC(errdefs.ErrNotImplemented)
+}
+
+func (f *fakeShim) Connect(context.Context, *task.ConnectRequest) (*task.ConnectResponse, error) {
+	return &task.ConnectResponse{ShimPid: uint32(os.Getpid())}, nil
+}
+
+func (f *fakeShim) Shutdown(context.Context, *task.ShutdownRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+// The actual test suite
+var _ = t.Describe("RuntimeVM", func() {
+	var (
+		sut       oci.RuntimeImpl
+		shim      *fakeShim
+		server    *ttrpc.Server
+		container *oci.Container
+		logPath   string
+	)
+
+	BeforeEach(func() {
+		dir := t.MustTempDir("runtime-vm")
+
+		// Serve the fake shim and provide a binary printing its address,
+		// like done by shims when getting started.
+		shim = newFakeShim()
+		var err error
+		server, err = ttrpc.NewServer()
+		Expect(err).To(BeNil())
+		task.RegisterTaskService(server, shim)
+		socket := filepath.Join(dir, "shim.sock")
+		listener, err := net.Listen("unix", socket)
+		Expect(err).To(BeNil())
+		go server.Serve(context.Background(), listener) // nolint:errcheck
+		shimPath := filepath.Join(dir, "containerd-shim-fake-v2")
+		Expect(os.WriteFile(shimPath, []byte(fmt.Sprintf("#!/bin/sh\necho unix://%s\n", socket)), 0o755)).To(Succeed())
+
+		bundlePath := filepath.Join(dir, "bundle")
+		ctrDir := filepath.Join(dir, "ctr")
+		exitsPath := filepath.Join(dir, "exits")
+		for _, path := range []string{bundlePath, ctrDir, exitsPath} {
+			Expect(os.MkdirAll(path, 0o755)).To(Succeed())
+		}
+		logPath = filepath.Join(dir, "ctr.log")
+		container, err = oci.NewContainer(containerID, "name", bundlePath, logPath,
+			map[string]string{}, map[string]string{}, map[string]string{},
+			"image", "imageName", "imageRef", &types.ContainerMetadata{}, sandboxID,
+			false, false, false, "", ctrDir, time.Now(), "")
+		Expect(err).To(BeNil())
+
+		sut = oci.NewRuntimeVM(shimPath, dir, exitsPath)
+	})
+
+	AfterEach(func() {
+		shim.exit()
+		Expect(server.Close()).To(Succeed())
+	})
+
+	createContainer := func() {
+		Expect(sut.CreateContainer(context.Background(), container, "", false)).To(Succeed())
+		Expect(sut.StartContainer(context.Background(), container)).To(Succeed())
+	}
+
+	readFile := func(path string) func() string {
+		return func() string {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return ""
+			}
+			return string(content)
+		}
+	}
+
+	It("should reopen the container log", func() {
+		// Given
+		createContainer()
+		// The container output ends once the fifo gets closed by the shim
+		stdout, err := os.OpenFile(shim.createRequest().Stdout, os.O_WRONLY, 0)
+		Expect(err).To(BeNil())
+		defer stdout.Close()
+		_, err = stdout.WriteString("hello\n")
+		Expect(err).To(BeNil())
+		Eventually(readFile(logPath)).Should(ContainSubstring("stdout F hello"))
+		rotatedPath := logPath + ".1"
+		Expect(os.Rename(logPath, rotatedPath)).To(Succeed())
+
+		// When
+		err = sut.ReopenContainerLog(context.Background(), container)
+
+		// Then
+		Expect(err).To(BeNil())
+		_, err = stdout.WriteString("world\n")
+		Expect(err).To(BeNil())
+		Eventually(readFile(logPath)).Should(ContainSubstring("stdout F world"))
+		Expect(readFile(rotatedPath)()).NotTo(ContainSubstring("world"))
+	})
+
+	It("should fail to reopen the log of an unknown container", func() {
+		// Given
+		// When
+		err := sut.ReopenContainerLog(context.Background(), container)
+
+		// Then
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should checkpoint a container and leave it running", func() {
+		// Given
+		createContainer()
+
+		// When
+		err := sut.CheckpointContainer(context.Background(), container, nil, true)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(shim.checkpoint.ID).To(Equal(containerID))
+		Expect(shim.checkpoint.Path).To(Equal(container.CheckpointPath()))
+		Expect(shim.signals).To(BeEmpty())
+		Expect(container.CheckpointedAt()).NotTo(BeZero())
+	})
+
+	It("should checkpoint a container and stop it", func() {
+		// Given
+		createContainer()
+
+		// When
+		err := sut.CheckpointContainer(context.Background(), container, nil, false)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(shim.signals).To(ConsistOf(uint32(syscall.SIGKILL)))
+		Expect(container.State().Status).To(BeEquivalentTo(oci.ContainerStateStopped))
+	})
+
+	It("should fail to checkpoint if the shim does not support it", func() {
+		// Given
+		createContainer()
+		shim.checkpointErr = errdefs.ErrNotImplemented
+
+		// When
+		err := sut.CheckpointContainer(context.Background(), container, nil, true)
+
+		// Then
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("checkpointing is not supported by the runtime shim"))
+	})
+
+	It("should restore a container from its checkpoint", func() {
+		// Given
+		Expect(os.MkdirAll(container.CheckpointPath(), 0o755)).To(Succeed())
+
+		// When
+		err := sut.RestoreContainer(context.Background(), container, "", "")
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(shim.createRequest().Checkpoint).To(Equal(container.CheckpointPath()))
+		Expect(container.State().Status).To(BeEquivalentTo(oci.ContainerStateRunning))
+		Expect(container.State().Pid).To(Equal(os.Getpid()))
+	})
+
+	It("should fail to restore a container without checkpoint", func() {
+		// Given
+		// When
+		err := sut.RestoreContainer(context.Background(), container, "", "")
+
+		// Then
+		Expect(err).NotTo(BeNil())
+		Expect(shim.createRequest()).To(BeNil())
+	})
+})
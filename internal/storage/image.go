@@ -126,8 +126,9 @@ type ImageServer interface {
 	// PrepareImage returns an Image where the config digest can be grabbed
 	// for further analysis. Call Close() on the resulting image.
 	PrepareImage(systemContext *types.SystemContext, imageName RegistryImageReference) (types.ImageCloser, error)
-	// PullImage imports an image from the specified location.
-	PullImage(systemContext *types.SystemContext, imageName RegistryImageReference, options *ImageCopyOptions) (types.ImageReference, error)
+	// PullImage imports an image from the specified location. Canceling the
+	// context aborts the pull.
+	PullImage(ctx context.Context, systemContext *types.SystemContext, imageName RegistryImageReference, options *ImageCopyOptions) (types.ImageReference, error)
 
 	// DeleteImage deletes a storage image (impacting all its tags)
 	DeleteImage(systemContext *types.SystemContext, id StorageImageID) error
@@ -533,11 +534,11 @@ func toCopyOptions(options *ImageCopyOptions, progress chan types.ProgressProper
 	}
 }
 
-func (svc *imageService) copyImage(systemContext *types.SystemContext, imageName RegistryImageReference, parentCgroup string, options *ImageCopyOptions) error {
+func (svc *imageService) copyImage(ctx context.Context, systemContext *types.SystemContext, imageName RegistryImageReference, parentCgroup string, options *ImageCopyOptions) error {
 	progress := options.Progress
 	// the first argument imageName is not used by the re-execed command but it is useful for debugging as it
 	// shows in the ps output.
-	cmd := reexec.CommandContext(svc.ctx, "crio-copy-image", imageName.StringForOutOfProcessConsumptionOnly())
+	cmd := reexec.CommandContext(ctx, "crio-copy-image", imageName.StringForOutOfProcessConsumptionOnly())
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return fmt.Errorf("error getting stdout pipe for image copy process: %w", err)
@@ -610,7 +611,7 @@ func (svc *imageService) copyImage(systemContext *types.SystemContext, imageName
 	return nil
 }
 
-func (svc *imageService) PullImage(systemContext *types.SystemContext, imageName RegistryImageReference, inputOptions *ImageCopyOptions) (types.ImageReference, error) {
+func (svc *imageService) PullImage(ctx context.Context, systemContext *types.SystemContext, imageName RegistryImageReference, inputOptions *ImageCopyOptions) (types.ImageReference, error) {
 	options := *inputOptions // A shallow copy
 
 	srcSystemContext, srcRef, destRef, err := svc.lookup.getReferences(options.SourceCtx, svc.store, imageName)
@@ -620,7 +621,7 @@ func (svc *imageService) PullImage(systemContext *types.SystemContext, imageName
 	options.SourceCtx = srcSystemContext
 
 	if inputOptions.CgroupPull.UseNewCgroup {
-		if err := svc.copyImage(systemContext, imageName, inputOptions.CgroupPull.ParentCgroup, &options); err != nil {
+		if err := svc.copyImage(ctx, systemContext, imageName, inputOptions.CgroupPull.ParentCgroup, &options); err != nil {
 			return nil, err
 		}
 	} else {
@@ -635,7 +636,7 @@ func (svc *imageService) PullImage(systemContext *types.SystemContext, imageName
 
 		copyOptions := toCopyOptions(&options, inputOptions.Progress)
 
-		if _, err = copy.Image(svc.ctx, policyContext, destRef, srcRef, copyOptions); err != nil {
+		if _, err = copy.Image(ctx, policyContext, destRef, srcRef, copyOptions); err != nil {
 			return nil, err
 		}
 	}
@@ -0,0 +1,314 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/containers/storage"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/pkg/config"
+	"github.com/cri-o/cri-o/pkg/types"
+	"github.com/cri-o/cri-o/server/metrics"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// ImageGCReasonAge is the eviction reason for images which have been
+	// unused for longer than the configured maximum age.
+	ImageGCReasonAge = "age"
+
+	// ImageGCReasonDiskPressure is the eviction reason for images which have
+	// been removed because the disk usage exceeded the high threshold.
+	ImageGCReasonDiskPressure = "disk_pressure"
+
+	// maxImageGCEvictions is the amount of evictions kept for inspection.
+	maxImageGCEvictions = 100
+)
+
+// ImageGC removes unused images from the storage, either if they have not been
+// used for a configurable amount of time or if the disk usage of the storage
+// root exceeds a configurable threshold. The least recently used images get
+// removed first, while images used by containers or pinned images are never
+// removed.
+type ImageGC struct {
+	imageServer ImageServer
+	config      *config.ImageConfig
+
+	// runMutex serializes the garbage collection runs.
+	runMutex sync.Mutex
+
+	// mutex protects the fields below.
+	mutex            sync.Mutex
+	lastUsed         map[string]time.Time
+	lastRun          time.Time
+	diskUsagePercent int
+	evictions        []types.ImageGCEviction
+}
+
+// NewImageGC creates a new image garbage collection for the provided image
+// server. The policy is read from the image configuration on every run, which
+// means that configuration changes apply to the next run.
+func NewImageGC(imageServer ImageServer, imageConfig *config.ImageConfig) *ImageGC {
+	return &ImageGC{
+		imageServer: imageServer,
+		config:      imageConfig,
+		lastUsed:    make(map[string]time.Time),
+	}
+}
+
+// MarkUsed records that the image with the provided ID has been used right
+// now, for example by pulling it or by creating a container from it.
+func (gc *ImageGC) MarkUsed(id string) {
+	if id == "" {
+		return
+	}
+	gc.mutex.Lock()
+	defer gc.mutex.Unlock()
+	gc.lastUsed[id] = time.Now()
+}
+
+// Start runs the image garbage collection periodically in the background
+// until the stop channel gets closed.
+func (gc *ImageGC) Start(ctx context.Context, stop <-chan struct{}) {
+	interval := gc.config.ImageGCInterval
+	log.Infof(ctx, "Starting image garbage collection with interval %s", interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				log.Debugf(ctx, "Stopping image garbage collection")
+				return
+			case <-ticker.C:
+				if err := gc.Run(ctx); err != nil {
+					log.Warnf(ctx, "Image garbage collection failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// imageGCCandidate is an image which can be removed by the garbage collection.
+type imageGCCandidate struct {
+	image    *storage.Image
+	lastUsed time.Time
+}
+
+// Run performs a single image garbage collection.
+func (gc *ImageGC) Run(ctx context.Context) error {
+	ctx, span := log.StartSpan(ctx)
+	defer span.End()
+
+	gc.runMutex.Lock()
+	defer gc.runMutex.Unlock()
+
+	store := gc.imageServer.GetStore()
+	images, err := store.Images()
+	if err != nil {
+		return fmt.Errorf("list images: %w", err)
+	}
+	containers, err := store.Containers()
+	if err != nil {
+		return fmt.Errorf("list containers: %w", err)
+	}
+
+	now := time.Now()
+	inUse := make(map[string]bool, len(containers))
+	for i := range containers {
+		inUse[containers[i].ImageID] = true
+	}
+
+	candidates := gc.candidates(images, inUse, now)
+
+	var evictErr error
+	if gc.config.ImageGCMaxAge > 0 {
+		remaining := candidates[:0]
+		for _, candidate := range candidates {
+			if now.Sub(candidate.lastUsed) < gc.config.ImageGCMaxAge {
+				remaining = append(remaining, candidate)
+				continue
+			}
+			if _, err := gc.evict(ctx, candidate, ImageGCReasonAge); err != nil {
+				evictErr = err
+			}
+		}
+		candidates = remaining
+	}
+
+	usedPercent := -1
+	if gc.config.ImageGCHighThresholdPercent > 0 {
+		used, total, err := diskUsage(store.GraphRoot())
+		if err != nil {
+			return fmt.Errorf("get disk usage of %s: %w", store.GraphRoot(), err)
+		}
+		usedPercent = percent(used, total)
+
+		if usedPercent >= gc.config.ImageGCHighThresholdPercent {
+			target := total * uint64(gc.config.ImageGCLowThresholdPercent) / 100
+			toFree := int64(used - target)
+			log.Infof(ctx,
+				"Disk usage %d%% of %s exceeds image garbage collection threshold %d%%, trying to free %d bytes",
+				usedPercent, store.GraphRoot(), gc.config.ImageGCHighThresholdPercent, toFree,
+			)
+			for _, candidate := range candidates {
+				if toFree <= 0 {
+					break
+				}
+				size, err := gc.evict(ctx, candidate, ImageGCReasonDiskPressure)
+				if err != nil {
+					evictErr = err
+					continue
+				}
+				toFree -= size
+			}
+			if toFree > 0 {
+				log.Warnf(ctx, "Image garbage collection was not able to free another %d bytes", toFree)
+			}
+		}
+	}
+
+	gc.mutex.Lock()
+	gc.lastRun = now
+	gc.diskUsagePercent = usedPercent
+	gc.mutex.Unlock()
+
+	return evictErr
+}
+
+// candidates updates the tracked image usage and returns all images which can
+// be removed, sorted by their last usage.
+func (gc *ImageGC) candidates(images []storage.Image, inUse map[string]bool, now time.Time) []imageGCCandidate {
+	pinnedImages := CompileRegexpsForPinnedImages(gc.config.PinnedImages)
+
+	gc.mutex.Lock()
+	defer gc.mutex.Unlock()
+
+	existing := make(map[string]bool, len(images))
+	candidates := []imageGCCandidate{}
+	for i := range images {
+		image := &images[i]
+		existing[image.ID] = true
+
+		lastUsed, ok := gc.lastUsed[image.ID]
+		if !ok || inUse[image.ID] {
+			// Images are considered as used when they get seen for the
+			// first time, to not remove them right after a restart.
+			lastUsed = now
+			gc.lastUsed[image.ID] = now
+		}
+
+		if inUse[image.ID] || gc.isPinned(image, pinnedImages) || imageIsBeingPulled(image) {
+			continue
+		}
+		if now.Sub(lastUsed) < gc.config.ImageGCMinAge {
+			continue
+		}
+		candidates = append(candidates, imageGCCandidate{image: image, lastUsed: lastUsed})
+	}
+
+	for id := range gc.lastUsed {
+		if !existing[id] {
+			delete(gc.lastUsed, id)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].lastUsed.Before(candidates[j].lastUsed)
+	})
+	return candidates
+}
+
+func (gc *ImageGC) isPinned(image *storage.Image, pinnedImages []*regexp.Regexp) bool {
+	for _, name := range image.Names {
+		if name == gc.config.PauseImage || FilterPinnedImage(name, pinnedImages) {
+			return true
+		}
+	}
+	return false
+}
+
+// evict removes the provided candidate and returns the freed bytes.
+func (gc *ImageGC) evict(ctx context.Context, candidate imageGCCandidate, reason string) (int64, error) {
+	id := candidate.image.ID
+	size, err := gc.imageServer.GetStore().ImageSize(id)
+	if err != nil {
+		log.Warnf(ctx, "Unable to get size of image %s: %v", id, err)
+		size = 0
+	}
+
+	log.Infof(ctx, "Removing image %s %v by garbage collection (reason: %s, last used: %s)",
+		id, candidate.image.Names, reason, candidate.lastUsed.Format(time.RFC3339))
+	if err := gc.imageServer.DeleteImage(nil, newExactStorageImageID(id)); err != nil {
+		return 0, fmt.Errorf("remove image %s: %w", id, err)
+	}
+	metrics.Instance().MetricImageGCEvictionsInc(reason, size)
+
+	gc.mutex.Lock()
+	defer gc.mutex.Unlock()
+	delete(gc.lastUsed, id)
+	gc.evictions = append(gc.evictions, types.ImageGCEviction{
+		ID:        id,
+		Names:     candidate.image.Names,
+		Size:      size,
+		LastUsed:  candidate.lastUsed.UnixNano(),
+		EvictedAt: time.Now().UnixNano(),
+		Reason:    reason,
+	})
+	if len(gc.evictions) > maxImageGCEvictions {
+		gc.evictions = gc.evictions[len(gc.evictions)-maxImageGCEvictions:]
+	}
+	return size, nil
+}
+
+// Info returns the current state of the image garbage collection.
+func (gc *ImageGC) Info() types.ImageGCInfo {
+	gc.mutex.Lock()
+	defer gc.mutex.Unlock()
+
+	info := types.ImageGCInfo{
+		Enabled:              gc.config.ImageGCEnabled(),
+		HighThresholdPercent: gc.config.ImageGCHighThresholdPercent,
+		LowThresholdPercent:  gc.config.ImageGCLowThresholdPercent,
+		MinAge:               gc.config.ImageGCMinAge.String(),
+		MaxAge:               gc.config.ImageGCMaxAge.String(),
+		DiskUsagePercent:     gc.diskUsagePercent,
+		Images:               make([]types.ImageGCImage, 0, len(gc.lastUsed)),
+		Evictions:            make([]types.ImageGCEviction, len(gc.evictions)),
+	}
+	if !gc.lastRun.IsZero() {
+		info.LastRun = gc.lastRun.UnixNano()
+	}
+	for id, lastUsed := range gc.lastUsed {
+		info.Images = append(info.Images, types.ImageGCImage{ID: id, LastUsed: lastUsed.UnixNano()})
+	}
+	sort.Slice(info.Images, func(i, j int) bool {
+		return info.Images[i].ID < info.Images[j].ID
+	})
+	copy(info.Evictions, gc.evictions)
+	return info
+}
+
+// diskUsage returns the used and total bytes of the file system containing
+// path.
+func diskUsage(path string) (used, total uint64, err error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	total = stat.Blocks * uint64(stat.Bsize)
+	used = total - stat.Bfree*uint64(stat.Bsize)
+	return used, total, nil
+}
+
+func percent(used, total uint64) int {
+	if total == 0 {
+		return 0
+	}
+	return int(used * 100 / total)
+}
@@ -0,0 +1,176 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	cs "github.com/containers/storage"
+	"github.com/cri-o/cri-o/internal/storage"
+	"github.com/cri-o/cri-o/pkg/config"
+	containerstoragemock "github.com/cri-o/cri-o/test/mocks/containerstorage"
+	criostoragemock "github.com/cri-o/cri-o/test/mocks/criostorage"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("ImageGC", func() {
+	const (
+		usedID   = "1111111111111111111111111111111111111111111111111111111111111111"
+		unusedID = "2222222222222222222222222222222222222222222222222222222222222222"
+		pinnedID = "3333333333333333333333333333333333333333333333333333333333333333"
+	)
+
+	var (
+		mockCtrl        *gomock.Controller
+		storeMock       *containerstoragemock.MockStore
+		imageServerMock *criostoragemock.MockImageServer
+		imageConfig     *config.ImageConfig
+
+		// The system under test
+		sut *storage.ImageGC
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		storeMock = containerstoragemock.NewMockStore(mockCtrl)
+		imageServerMock = criostoragemock.NewMockImageServer(mockCtrl)
+		imageServerMock.EXPECT().GetStore().Return(storeMock).AnyTimes()
+
+		imageConfig = &config.ImageConfig{
+			PauseImage:   "registry.k8s.io/pause:3.9",
+			PinnedImages: []string{"quay.io/pinned*"},
+		}
+		sut = storage.NewImageGC(imageServerMock, imageConfig)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	mockImages := func() {
+		storeMock.EXPECT().Images().Return([]cs.Image{
+			{ID: usedID, Names: []string{"quay.io/used:latest"}},
+			{ID: unusedID, Names: []string{"quay.io/unused:latest"}},
+			{ID: pinnedID, Names: []string{"quay.io/pinned:latest"}},
+		}, nil)
+		storeMock.EXPECT().Containers().Return([]cs.Container{
+			{ID: "container", ImageID: usedID},
+		}, nil)
+	}
+
+	t.Describe("Run", func() {
+		It("should track all images on the first run", func() {
+			// Given
+			imageConfig.ImageGCMaxAge = time.Hour
+			mockImages()
+
+			// When
+			err := sut.Run(context.Background())
+
+			// Then
+			Expect(err).To(BeNil())
+			info := sut.Info()
+			Expect(info.Enabled).To(BeTrue())
+			Expect(info.LastRun).NotTo(BeZero())
+			Expect(info.Images).To(HaveLen(3))
+			Expect(info.Evictions).To(BeEmpty())
+		})
+
+		It("should evict unused images exceeding the maximum age", func() {
+			// Given
+			imageConfig.ImageGCMaxAge = time.Nanosecond
+			mockImages()
+			Expect(sut.Run(context.Background())).To(BeNil())
+
+			mockImages()
+			gomock.InOrder(
+				storeMock.EXPECT().ImageSize(unusedID).Return(int64(1024), nil),
+				imageServerMock.EXPECT().DeleteImage(gomock.Any(), gomock.Any()).Return(nil),
+			)
+
+			// When
+			err := sut.Run(context.Background())
+
+			// Then
+			Expect(err).To(BeNil())
+			info := sut.Info()
+			Expect(info.Evictions).To(HaveLen(1))
+			Expect(info.Evictions[0].ID).To(Equal(unusedID))
+			Expect(info.Evictions[0].Size).To(BeEquivalentTo(1024))
+			Expect(info.Evictions[0].Reason).To(Equal(storage.ImageGCReasonAge))
+			Expect(info.Images).To(HaveLen(2))
+		})
+
+		It("should not evict recently used images", func() {
+			// Given
+			imageConfig.ImageGCMaxAge = time.Hour
+			mockImages()
+			Expect(sut.Run(context.Background())).To(BeNil())
+			mockImages()
+
+			// When
+			sut.MarkUsed(unusedID)
+			err := sut.Run(context.Background())
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.Info().Evictions).To(BeEmpty())
+		})
+
+		It("should evict unused images on disk pressure", func() {
+			// Given
+			imageConfig.ImageGCHighThresholdPercent = 1
+			storeMock.EXPECT().GraphRoot().Return(t.MustTempDir("image-gc")).AnyTimes()
+			mockImages()
+			gomock.InOrder(
+				storeMock.EXPECT().ImageSize(unusedID).Return(int64(1024), nil),
+				imageServerMock.EXPECT().DeleteImage(gomock.Any(), gomock.Any()).Return(nil),
+			)
+
+			// When
+			err := sut.Run(context.Background())
+
+			// Then
+			Expect(err).To(BeNil())
+			info := sut.Info()
+			Expect(info.DiskUsagePercent).To(BeNumerically(">=", 1))
+			Expect(info.Evictions).To(HaveLen(1))
+			Expect(info.Evictions[0].ID).To(Equal(unusedID))
+			Expect(info.Evictions[0].Reason).To(Equal(storage.ImageGCReasonDiskPressure))
+		})
+
+		It("should fail if the image removal fails", func() {
+			// Given
+			imageConfig.ImageGCMaxAge = time.Nanosecond
+			mockImages()
+			Expect(sut.Run(context.Background())).To(BeNil())
+
+			mockImages()
+			gomock.InOrder(
+				storeMock.EXPECT().ImageSize(unusedID).Return(int64(1024), nil),
+				imageServerMock.EXPECT().DeleteImage(gomock.Any(), gomock.Any()).Return(errors.New("error")),
+			)
+
+			// When
+			err := sut.Run(context.Background())
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(sut.Info().Evictions).To(BeEmpty())
+		})
+
+		It("should fail if listing the images fails", func() {
+			// Given
+			storeMock.EXPECT().Images().Return(nil, errors.New("error"))
+
+			// When
+			err := sut.Run(context.Background())
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
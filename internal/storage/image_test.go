@@ -549,7 +549,7 @@ var _ = t.Describe("Image", func() {
 			Expect(err).To(BeNil())
 
 			// When
-			res, err := sut.PullImage(&types.SystemContext{
+			res, err := sut.PullImage(context.Background(), &types.SystemContext{
 				SignaturePolicyPath: "/not-existing",
 			}, imageRef, &storage.ImageCopyOptions{})
 
@@ -564,7 +564,7 @@ var _ = t.Describe("Image", func() {
 			Expect(err).To(BeNil())
 
 			// When
-			res, err := sut.PullImage(&types.SystemContext{
+			res, err := sut.PullImage(context.Background(), &types.SystemContext{
 				SignaturePolicyPath: "../../test/policy.json",
 			}, imageRef, &storage.ImageCopyOptions{})
 
@@ -579,7 +579,7 @@ var _ = t.Describe("Image", func() {
 			Expect(err).To(BeNil())
 
 			// When
-			res, err := sut.PullImage(&types.SystemContext{
+			res, err := sut.PullImage(context.Background(), &types.SystemContext{
 				SignaturePolicyPath: "../../test/policy.json",
 			}, imageRef, &storage.ImageCopyOptions{})
 
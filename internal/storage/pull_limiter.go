@@ -0,0 +1,222 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cri-o/cri-o/server/metrics"
+)
+
+// PullLimits are the concurrency limits of image pulls. A value of zero
+// disables the corresponding limit.
+type PullLimits struct {
+	// Node is the maximum number of concurrent pulls on the node.
+	Node int
+	// Registry is the maximum number of concurrent pulls per registry host.
+	Registry int
+	// Namespace is the maximum number of concurrent pulls per Kubernetes
+	// namespace.
+	Namespace int
+}
+
+// PullLimiter limits the number of concurrent image pulls. Pulls exceeding
+// the limits are queued per namespace, and the queues are served round-robin,
+// so a rollout within one namespace cannot starve the pulls of others.
+// Within a namespace, pulls are served in FIFO order.
+type PullLimiter struct {
+	limits PullLimits
+
+	mutex       sync.Mutex
+	active      int
+	perRegistry map[string]int
+	perNS       map[string]int
+	queues      map[string][]*pullWaiter
+	// order is the round-robin order of the namespaces having queued pulls.
+	order []string
+	// next is the index in order to start serving from.
+	next   int
+	queued int
+}
+
+// pullWaiter is a queued pull waiting for a free slot.
+type pullWaiter struct {
+	registry   string
+	namespace  string
+	enqueuedAt time.Time
+	// ready gets closed as soon as the slot has been granted.
+	ready chan struct{}
+}
+
+// NewPullLimiter creates a new pull limiter using the provided limits.
+func NewPullLimiter(limits PullLimits) *PullLimiter {
+	return &PullLimiter{
+		limits:      limits,
+		perRegistry: make(map[string]int),
+		perNS:       make(map[string]int),
+		queues:      make(map[string][]*pullWaiter),
+	}
+}
+
+// Acquire blocks until a pull slot for the provided registry host and
+// namespace is available or the context gets canceled. The returned function
+// has to be called to free the slot once the pull has finished.
+func (l *PullLimiter) Acquire(ctx context.Context, registry, namespace string) (release func(), err error) {
+	release = func() { l.release(registry, namespace) }
+
+	l.mutex.Lock()
+	if l.queued == 0 && l.fits(registry, namespace) {
+		l.take(registry, namespace)
+		l.mutex.Unlock()
+		return release, nil
+	}
+
+	waiter := &pullWaiter{
+		registry:   registry,
+		namespace:  namespace,
+		enqueuedAt: time.Now(),
+		ready:      make(chan struct{}),
+	}
+	l.enqueue(waiter)
+	l.dispatch()
+	l.mutex.Unlock()
+
+	select {
+	case <-waiter.ready:
+		metrics.Instance().MetricImagePullsQueueWaitObserve(time.Since(waiter.enqueuedAt))
+		return release, nil
+	case <-ctx.Done():
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	select {
+	case <-waiter.ready:
+		// The slot has been granted concurrently, pass it on.
+		l.free(registry, namespace)
+		l.dispatch()
+	default:
+		l.dequeue(waiter)
+	}
+	return nil, ctx.Err()
+}
+
+// Queued returns the number of pulls waiting for a free slot.
+func (l *PullLimiter) Queued() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.queued
+}
+
+func (l *PullLimiter) release(registry, namespace string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.free(registry, namespace)
+	l.dispatch()
+}
+
+// fits returns true if a pull for registry and namespace does not exceed any
+// limit. Must be called with the mutex held.
+func (l *PullLimiter) fits(registry, namespace string) bool {
+	if l.limits.Node > 0 && l.active >= l.limits.Node {
+		return false
+	}
+	if l.limits.Registry > 0 && l.perRegistry[registry] >= l.limits.Registry {
+		return false
+	}
+	if l.limits.Namespace > 0 && l.perNS[namespace] >= l.limits.Namespace {
+		return false
+	}
+	return true
+}
+
+func (l *PullLimiter) take(registry, namespace string) {
+	l.active++
+	l.perRegistry[registry]++
+	l.perNS[namespace]++
+}
+
+func (l *PullLimiter) free(registry, namespace string) {
+	l.active--
+	if l.perRegistry[registry]--; l.perRegistry[registry] <= 0 {
+		delete(l.perRegistry, registry)
+	}
+	if l.perNS[namespace]--; l.perNS[namespace] <= 0 {
+		delete(l.perNS, namespace)
+	}
+}
+
+func (l *PullLimiter) enqueue(waiter *pullWaiter) {
+	if _, ok := l.queues[waiter.namespace]; !ok {
+		l.order = append(l.order, waiter.namespace)
+	}
+	l.queues[waiter.namespace] = append(l.queues[waiter.namespace], waiter)
+	l.setQueued(l.queued + 1)
+}
+
+func (l *PullLimiter) dequeue(waiter *pullWaiter) {
+	queue := l.queues[waiter.namespace]
+	for i, w := range queue {
+		if w == waiter {
+			l.queues[waiter.namespace] = append(queue[:i], queue[i+1:]...)
+			l.setQueued(l.queued - 1)
+			break
+		}
+	}
+	l.removeEmptyQueue(waiter.namespace)
+}
+
+func (l *PullLimiter) removeEmptyQueue(namespace string) {
+	if len(l.queues[namespace]) > 0 {
+		return
+	}
+	delete(l.queues, namespace)
+	for i, ns := range l.order {
+		if ns != namespace {
+			continue
+		}
+		l.order = append(l.order[:i], l.order[i+1:]...)
+		if l.next > i {
+			l.next--
+		}
+		break
+	}
+	if l.next >= len(l.order) {
+		l.next = 0
+	}
+}
+
+// dispatch grants free slots to the queued pulls by serving the heads of the
+// namespace queues round-robin. Must be called with the mutex held.
+func (l *PullLimiter) dispatch() {
+	for granted := true; granted && len(l.order) > 0; {
+		granted = false
+		for i := 0; i < len(l.order); i++ {
+			idx := (l.next + i) % len(l.order)
+			namespace := l.order[idx]
+			waiter := l.queues[namespace][0]
+			if !l.fits(waiter.registry, waiter.namespace) {
+				continue
+			}
+
+			l.take(waiter.registry, waiter.namespace)
+			l.queues[namespace] = l.queues[namespace][1:]
+			l.setQueued(l.queued - 1)
+			close(waiter.ready)
+
+			// Continue with the next namespace on the next grant.
+			l.next = idx + 1
+			l.removeEmptyQueue(namespace)
+			if l.next >= len(l.order) {
+				l.next = 0
+			}
+			granted = true
+			break
+		}
+	}
+}
+
+func (l *PullLimiter) setQueued(queued int) {
+	l.queued = queued
+	metrics.Instance().MetricImagePullsQueueDepthSet(queued)
+}
@@ -0,0 +1,118 @@
+package storage_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/storage"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("PullLimiter", func() {
+	// acquireAsync acquires a slot in the background and sends the release
+	// function to the returned channel once the slot has been granted.
+	acquireAsync := func(ctx context.Context, sut *storage.PullLimiter, registry, namespace string) chan func() {
+		granted := make(chan func(), 1)
+		go func() {
+			defer GinkgoRecover()
+			release, err := sut.Acquire(ctx, registry, namespace)
+			if err == nil {
+				granted <- release
+			}
+		}()
+		return granted
+	}
+
+	It("should not limit without limits", func() {
+		// Given
+		sut := storage.NewPullLimiter(storage.PullLimits{})
+
+		// When
+		for i := 0; i < 10; i++ {
+			_, err := sut.Acquire(context.Background(), "quay.io", "default")
+
+			// Then
+			Expect(err).To(BeNil())
+		}
+		Expect(sut.Queued()).To(BeZero())
+	})
+
+	It("should limit the concurrent pulls of the node", func() {
+		// Given
+		sut := storage.NewPullLimiter(storage.PullLimits{Node: 1})
+		release, err := sut.Acquire(context.Background(), "quay.io", "default")
+		Expect(err).To(BeNil())
+
+		// When
+		granted := acquireAsync(context.Background(), sut, "docker.io", "other")
+
+		// Then
+		Eventually(sut.Queued).Should(Equal(1))
+		Consistently(granted, 50*time.Millisecond).ShouldNot(Receive())
+		release()
+		Eventually(granted).Should(Receive())
+		Expect(sut.Queued()).To(BeZero())
+	})
+
+	It("should limit the concurrent pulls per registry", func() {
+		// Given
+		sut := storage.NewPullLimiter(storage.PullLimits{Registry: 1})
+		release, err := sut.Acquire(context.Background(), "quay.io", "default")
+		Expect(err).To(BeNil())
+
+		// When
+		blocked := acquireAsync(context.Background(), sut, "quay.io", "default")
+		Eventually(sut.Queued).Should(Equal(1))
+		_, err = sut.Acquire(context.Background(), "docker.io", "other")
+
+		// Then
+		Expect(err).To(BeNil())
+		Consistently(blocked, 50*time.Millisecond).ShouldNot(Receive())
+		release()
+		Eventually(blocked).Should(Receive())
+	})
+
+	It("should serve the namespaces fairly", func() {
+		// Given
+		sut := storage.NewPullLimiter(storage.PullLimits{Node: 1})
+		release, err := sut.Acquire(context.Background(), "quay.io", "busy")
+		Expect(err).To(BeNil())
+
+		busy := []chan func(){}
+		for i := 0; i < 3; i++ {
+			busy = append(busy, acquireAsync(context.Background(), sut, "quay.io", "busy"))
+			Eventually(sut.Queued).Should(Equal(i + 1))
+		}
+		other := acquireAsync(context.Background(), sut, "quay.io", "other")
+		Eventually(sut.Queued).Should(Equal(4))
+
+		// When
+		release()
+		var next func()
+		Eventually(busy[0]).Should(Receive(&next))
+		next()
+
+		// Then
+		Eventually(other).Should(Receive())
+		Consistently(busy[1], 50*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("should stop waiting if the context gets canceled", func() {
+		// Given
+		sut := storage.NewPullLimiter(storage.PullLimits{Namespace: 1})
+		_, err := sut.Acquire(context.Background(), "quay.io", "default")
+		Expect(err).To(BeNil())
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		// When
+		release, err := sut.Acquire(ctx, "quay.io", "default")
+
+		// Then
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(release).To(BeNil())
+		Expect(sut.Queued()).To(BeZero())
+	})
+})
@@ -0,0 +1,373 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/pkg/sysregistriesv2"
+	"github.com/containers/image/v5/types"
+	crioTypes "github.com/cri-o/cri-o/pkg/types"
+	"github.com/cri-o/cri-o/server/metrics"
+	"github.com/google/renameio"
+	"github.com/opencontainers/go-digest"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrRegistryCircuitOpen is returned if all registries of an image pull are
+// considered as unhealthy and therefore skipped.
+var ErrRegistryCircuitOpen = errors.New("registry circuit breaker is open")
+
+// RegistryHealth tracks the recent failures of the registry endpoints used for
+// image pulls. An endpoint whose consecutive failures reach the threshold is
+// considered as unhealthy (the circuit is open) and gets skipped by image
+// pulls until the cooldown has passed. Afterwards, a single pull is allowed to
+// probe the endpoint again (the circuit is half-open), while all other pulls
+// keep skipping it: a success closes the circuit, while a failure opens it
+// for another cooldown period. Every mirror of a registry is tracked and
+// skipped on its own.
+type RegistryHealth struct {
+	// threshold is the number of consecutive failures which open the
+	// circuit. A value of zero disables the circuit breaker, but the health
+	// is still tracked.
+	threshold int
+	// cooldown is the time the circuit stays open.
+	cooldown time.Duration
+	// dir is the directory for the registries.conf files restricting the
+	// pulls to single pull sources.
+	dir string
+
+	mutex      sync.Mutex
+	registries map[string]*registryState
+}
+
+// registryState is the health state of a single registry endpoint.
+type registryState struct {
+	consecutiveFailures int
+	totalFailures       uint64
+	totalSuccesses      uint64
+	lastFailure         time.Time
+	lastError           string
+	// openUntil is the end of the cooldown of an opened circuit. It is kept
+	// after the cooldown, which means that the circuit is half-open, until
+	// the endpoint got probed successfully.
+	openUntil time.Time
+	// probeUntil is the time until the probe of a half-open circuit is
+	// considered in progress. A probe which never gets recorded, for example
+	// because another candidate has been pulled before, expires afterwards.
+	probeUntil time.Time
+}
+
+// NewRegistryHealth creates a new registry health tracker, which writes its
+// registries.conf files into dir.
+func NewRegistryHealth(threshold int, cooldown time.Duration, dir string) *RegistryHealth {
+	return &RegistryHealth{
+		threshold:  threshold,
+		cooldown:   cooldown,
+		dir:        dir,
+		registries: make(map[string]*registryState),
+	}
+}
+
+func (h *RegistryHealth) state(registry string) *registryState {
+	state, ok := h.registries[registry]
+	if !ok {
+		state = &registryState{}
+		h.registries[registry] = state
+	}
+	return state
+}
+
+// PullSource is a single endpoint to pull a candidate from, which is either
+// one of the mirrors of its registry or the registry itself.
+type PullSource struct {
+	// Candidate is the image to be pulled.
+	Candidate RegistryImageReference
+	// Endpoint is the registry endpoint which gets contacted for the pull
+	// and whose health gets tracked. It is empty if it is unknown.
+	Endpoint string
+	// registriesConf is the path to a registries.conf, which restricts the
+	// pull of the candidate to the endpoint. It is empty if the candidate
+	// has no mirrors, so that there is nothing to restrict.
+	registriesConf string
+}
+
+// SystemContext returns the system context to pull the candidate from the
+// pull source, based on the provided one.
+func (p *PullSource) SystemContext(sys *types.SystemContext) *types.SystemContext {
+	if p.registriesConf == "" {
+		return sys
+	}
+	sc := types.SystemContext{}
+	if sys != nil {
+		sc = *sys // A shallow copy
+	}
+	sc.SystemRegistriesConfPath = p.registriesConf
+	// Does not exist, which avoids applying any drop-in configuration.
+	sc.SystemRegistriesConfDirPath = filepath.Join(filepath.Dir(p.registriesConf), "registries.conf.d")
+	return &sc
+}
+
+// pullSources returns the pull sources of the candidate in the order they
+// get tried by containers/image, as configured in the registries.conf of the
+// system context. If the candidate can be pulled from mirrors, every pull
+// source gets its own registries.conf to restrict the pull to it, because the
+// failover between the mirrors would otherwise happen within containers/image,
+// which does not tell which of them failed.
+func (h *RegistryHealth) pullSources(sys *types.SystemContext, candidate RegistryImageReference) []PullSource {
+	ref := candidate.Raw()
+	registry, err := sysregistriesv2.FindRegistry(sys, ref.Name())
+	if err != nil {
+		// Let the pull itself report the invalid configuration.
+		return []PullSource{{Candidate: candidate}}
+	}
+	if registry == nil {
+		return []PullSource{{Candidate: candidate, Endpoint: reference.Domain(ref)}}
+	}
+	sources, err := registry.PullSourcesFromReference(ref)
+	if err != nil {
+		return []PullSource{{Candidate: candidate}}
+	}
+	if len(sources) == 1 {
+		return []PullSource{{Candidate: candidate, Endpoint: reference.Domain(sources[0].Reference)}}
+	}
+
+	pullSources := make([]PullSource, 0, len(sources))
+	for _, source := range sources {
+		registriesConf, err := h.writeRegistriesConf(sys, ref, &source)
+		if err != nil {
+			logrus.Warnf("Unable to track the pull sources of %s separately: %v", candidate, err)
+			return []PullSource{{Candidate: candidate}}
+		}
+		pullSources = append(pullSources, PullSource{
+			Candidate:      candidate,
+			Endpoint:       reference.Domain(source.Reference),
+			registriesConf: registriesConf,
+		})
+	}
+	return pullSources
+}
+
+// restrictedRegistriesConf is a registries.conf which restricts the pull of a
+// single image to a single pull source.
+type restrictedRegistriesConf struct {
+	CredentialHelpers []string                   `toml:"credential-helpers,omitempty"`
+	Registries        []sysregistriesv2.Registry `toml:"registry"`
+}
+
+// writeRegistriesConf writes a registries.conf which restricts the pull of the
+// reference to the pull source and returns its path. The file name is the
+// digest of its content, so that every restriction gets written only once and
+// containers/image can keep caching the parsed configuration.
+func (h *RegistryHealth) writeRegistriesConf(sys *types.SystemContext, ref reference.Named, source *sysregistriesv2.PullSource) (string, error) {
+	credentialHelpers, err := sysregistriesv2.CredentialHelpers(sys)
+	if err != nil {
+		return "", err
+	}
+	conf := restrictedRegistriesConf{
+		CredentialHelpers: credentialHelpers,
+		Registries: []sysregistriesv2.Registry{{
+			Prefix: ref.Name(),
+			Endpoint: sysregistriesv2.Endpoint{
+				Location: source.Reference.Name(),
+				Insecure: source.Endpoint.Insecure,
+			},
+		}},
+	}
+	// containers/image checks whether the contacted host is blocked.
+	host := reference.Domain(source.Reference)
+	if hostRegistry, err := sysregistriesv2.FindRegistry(sys, host); err == nil && hostRegistry != nil && hostRegistry.Blocked {
+		conf.Registries = append(conf.Registries, sysregistriesv2.Registry{
+			Prefix:   host,
+			Endpoint: sysregistriesv2.Endpoint{Location: host},
+			Blocked:  true,
+		})
+	}
+
+	var content bytes.Buffer
+	if err := toml.NewEncoder(&content).Encode(conf); err != nil {
+		return "", err
+	}
+	path := filepath.Join(h.dir, digest.FromBytes(content.Bytes()).Encoded()+".conf")
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if err := os.MkdirAll(h.dir, 0o755); err != nil {
+		return "", err
+	}
+	if err := renameio.WriteFile(path, content.Bytes(), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// RecordSuccess records a successful request to the registry endpoint, which
+// closes its circuit. Empty endpoints are ignored.
+func (h *RegistryHealth) RecordSuccess(registry string) {
+	if registry == "" {
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	state := h.state(registry)
+	state.totalSuccesses++
+	state.consecutiveFailures = 0
+	state.probeUntil = time.Time{}
+	if !state.openUntil.IsZero() {
+		state.openUntil = time.Time{}
+		metrics.Instance().MetricRegistryCircuitOpenSet(registry, false)
+	}
+}
+
+// RecordFailure records a failed request to the registry endpoint. Errors
+// which do not indicate an unreachable or unhealthy registry, like
+// authentication errors or unknown images, as well as empty endpoints are
+// ignored. It returns true if the error got recorded.
+func (h *RegistryHealth) RecordFailure(registry string, err error) bool {
+	if registry == "" || !IsRegistryFailure(err) {
+		return false
+	}
+	metrics.Instance().MetricRegistryFailuresInc(registry)
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	now := time.Now()
+	state := h.state(registry)
+	state.totalFailures++
+	state.consecutiveFailures++
+	state.lastFailure = now
+	state.lastError = err.Error()
+	state.probeUntil = time.Time{}
+	if h.threshold > 0 && state.consecutiveFailures >= h.threshold {
+		state.openUntil = now.Add(h.cooldown)
+		metrics.Instance().MetricRegistryCircuitOpenSet(registry, true)
+	}
+	return true
+}
+
+// IsRegistryFailure returns true if the error indicates that a registry is
+// not reachable or not healthy.
+func IsRegistryFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	for _, errno := range []syscall.Errno{
+		syscall.ECONNREFUSED,
+		syscall.ECONNRESET,
+		syscall.EHOSTUNREACH,
+		syscall.ENETUNREACH,
+		syscall.ETIMEDOUT,
+	} {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isOpen returns true if the circuit of the registry endpoint is open, or if
+// it is half-open and already being probed. Must be called with the mutex
+// held.
+func (h *RegistryHealth) isOpen(registry string, now time.Time) bool {
+	state, ok := h.registries[registry]
+	return ok && (now.Before(state.openUntil) || now.Before(state.probeUntil))
+}
+
+// Order returns the pull sources of the candidates to be tried for an image
+// pull. Pull sources of endpoints with an open circuit are skipped, while pull
+// sources of endpoints with recent failures are tried after the healthy ones.
+// A pull source of a half-open endpoint is only returned to a single caller,
+// which probes the endpoint. The original order is kept otherwise. If the
+// circuit breaker is disabled, all pull sources are returned in their
+// original order.
+func (h *RegistryHealth) Order(sys *types.SystemContext, candidates []RegistryImageReference) ([]PullSource, error) {
+	sources := []PullSource{}
+	for _, candidate := range candidates {
+		sources = append(sources, h.pullSources(sys, candidate)...)
+	}
+	if h.threshold <= 0 || len(sources) == 0 {
+		return sources, nil
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	now := time.Now()
+	healthy := make([]PullSource, 0, len(sources))
+	degraded := []PullSource{}
+	skipped := []string{}
+	for _, source := range sources {
+		state, ok := h.registries[source.Endpoint]
+		if source.Endpoint == "" || !ok || state.consecutiveFailures == 0 {
+			healthy = append(healthy, source)
+			continue
+		}
+		if h.isOpen(source.Endpoint, now) {
+			skipped = append(skipped, source.Endpoint)
+			continue
+		}
+		if !state.openUntil.IsZero() {
+			// Let this pull probe the half-open circuit.
+			state.probeUntil = now.Add(h.cooldown)
+		}
+		degraded = append(degraded, source)
+	}
+
+	ordered := append(healthy, degraded...)
+	if len(ordered) == 0 {
+		return nil, fmt.Errorf("%w for %v", ErrRegistryCircuitOpen, skipped)
+	}
+	return ordered, nil
+}
+
+// Info returns the health of all tracked registries.
+func (h *RegistryHealth) Info() []crioTypes.RegistryHealthInfo {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	now := time.Now()
+	infos := make([]crioTypes.RegistryHealthInfo, 0, len(h.registries))
+	for registry, state := range h.registries {
+		info := crioTypes.RegistryHealthInfo{
+			Registry:            registry,
+			Healthy:             state.consecutiveFailures == 0,
+			CircuitOpen:         h.isOpen(registry, now),
+			ConsecutiveFailures: state.consecutiveFailures,
+			TotalFailures:       state.totalFailures,
+			TotalSuccesses:      state.totalSuccesses,
+			LastError:           state.lastError,
+		}
+		if !state.lastFailure.IsZero() {
+			info.LastFailure = state.lastFailure.UnixNano()
+		}
+		if info.CircuitOpen && now.Before(state.openUntil) {
+			info.OpenUntil = state.openUntil.UnixNano()
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Registry < infos[j].Registry
+	})
+	return infos
+}
@@ -0,0 +1,405 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/containers/image/v5/pkg/sysregistriesv2"
+	"github.com/containers/image/v5/types"
+	"github.com/cri-o/cri-o/internal/storage"
+	"github.com/cri-o/cri-o/internal/storage/references"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("RegistryHealth", func() {
+	var (
+		errRefused = fmt.Errorf("pinging registry: %w", syscall.ECONNREFUSED)
+
+		quay   storage.RegistryImageReference
+		docker storage.RegistryImageReference
+		sys    *types.SystemContext
+		dir    string
+	)
+
+	writeRegistriesConf := func(content string) {
+		Expect(os.WriteFile(sys.SystemRegistriesConfPath, []byte(content), 0o644)).To(BeNil())
+	}
+
+	candidates := func(sources []storage.PullSource) []storage.RegistryImageReference {
+		res := []storage.RegistryImageReference{}
+		for _, source := range sources {
+			res = append(res, source.Candidate)
+		}
+		return res
+	}
+
+	endpoints := func(sources []storage.PullSource) []string {
+		res := []string{}
+		for _, source := range sources {
+			res = append(res, source.Endpoint)
+		}
+		return res
+	}
+
+	BeforeEach(func() {
+		sys = &types.SystemContext{
+			SystemRegistriesConfPath:    t.MustTempFile("registries"),
+			SystemRegistriesConfDirPath: t.MustTempDir("registries.d"),
+		}
+		dir = t.MustTempDir("pull-sources")
+		var err error
+		quay, err = references.ParseRegistryImageReferenceFromOutOfProcessData("quay.io/image:latest")
+		Expect(err).To(BeNil())
+		docker, err = references.ParseRegistryImageReferenceFromOutOfProcessData("docker.io/library/image:latest")
+		Expect(err).To(BeNil())
+	})
+
+	It("should not reorder candidates if the circuit breaker is disabled", func() {
+		// Given
+		sut := storage.NewRegistryHealth(0, time.Minute, dir)
+		Expect(sut.RecordFailure("quay.io", errRefused)).To(BeTrue())
+
+		// When
+		res, err := sut.Order(sys, []storage.RegistryImageReference{quay, docker})
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(candidates(res)).To(Equal([]storage.RegistryImageReference{quay, docker}))
+		Expect(sut.Info()).To(HaveLen(1))
+		Expect(sut.Info()[0].CircuitOpen).To(BeFalse())
+	})
+
+	It("should try degraded registries last", func() {
+		// Given
+		sut := storage.NewRegistryHealth(2, time.Minute, dir)
+		Expect(sut.RecordFailure("quay.io", errRefused)).To(BeTrue())
+
+		// When
+		res, err := sut.Order(sys, []storage.RegistryImageReference{quay, docker})
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(candidates(res)).To(Equal([]storage.RegistryImageReference{docker, quay}))
+		info := sut.Info()
+		Expect(info).To(HaveLen(1))
+		Expect(info[0].Healthy).To(BeFalse())
+		Expect(info[0].ConsecutiveFailures).To(Equal(1))
+		Expect(info[0].LastError).To(ContainSubstring("connection refused"))
+	})
+
+	It("should skip registries with an open circuit", func() {
+		// Given
+		sut := storage.NewRegistryHealth(2, time.Minute, dir)
+		Expect(sut.RecordFailure("quay.io", errRefused)).To(BeTrue())
+		Expect(sut.RecordFailure("quay.io", errRefused)).To(BeTrue())
+
+		// When
+		res, err := sut.Order(sys, []storage.RegistryImageReference{quay, docker})
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(candidates(res)).To(Equal([]storage.RegistryImageReference{docker}))
+		Expect(sut.Info()[0].CircuitOpen).To(BeTrue())
+		Expect(sut.Info()[0].OpenUntil).NotTo(BeZero())
+	})
+
+	It("should fail if all circuits are open", func() {
+		// Given
+		sut := storage.NewRegistryHealth(1, time.Minute, dir)
+		Expect(sut.RecordFailure("quay.io", errRefused)).To(BeTrue())
+
+		// When
+		res, err := sut.Order(sys, []storage.RegistryImageReference{quay})
+
+		// Then
+		Expect(err).To(MatchError(storage.ErrRegistryCircuitOpen))
+		Expect(res).To(BeEmpty())
+	})
+
+	It("should retry the registry after the cooldown", func() {
+		// Given
+		sut := storage.NewRegistryHealth(1, time.Nanosecond, dir)
+		Expect(sut.RecordFailure("quay.io", errRefused)).To(BeTrue())
+		time.Sleep(time.Millisecond)
+
+		// When
+		res, err := sut.Order(sys, []storage.RegistryImageReference{quay})
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(candidates(res)).To(Equal([]storage.RegistryImageReference{quay}))
+	})
+
+	It("should let a single pull probe the registry after the cooldown", func() {
+		// Given
+		sut := storage.NewRegistryHealth(1, 100*time.Millisecond, dir)
+		Expect(sut.RecordFailure("quay.io", errRefused)).To(BeTrue())
+		time.Sleep(150 * time.Millisecond)
+
+		// When
+		probe, probeErr := sut.Order(sys, []storage.RegistryImageReference{quay})
+		res, err := sut.Order(sys, []storage.RegistryImageReference{quay})
+
+		// Then
+		Expect(probeErr).To(BeNil())
+		Expect(candidates(probe)).To(Equal([]storage.RegistryImageReference{quay}))
+		Expect(err).To(MatchError(storage.ErrRegistryCircuitOpen))
+		Expect(res).To(BeEmpty())
+		Expect(sut.Info()[0].CircuitOpen).To(BeTrue())
+	})
+
+	It("should open the circuit again if the probe fails", func() {
+		// Given
+		sut := storage.NewRegistryHealth(1, 100*time.Millisecond, dir)
+		Expect(sut.RecordFailure("quay.io", errRefused)).To(BeTrue())
+		time.Sleep(150 * time.Millisecond)
+		_, err := sut.Order(sys, []storage.RegistryImageReference{quay})
+		Expect(err).To(BeNil())
+
+		// When
+		Expect(sut.RecordFailure("quay.io", errRefused)).To(BeTrue())
+
+		// Then
+		res, err := sut.Order(sys, []storage.RegistryImageReference{quay})
+		Expect(err).To(MatchError(storage.ErrRegistryCircuitOpen))
+		Expect(res).To(BeEmpty())
+		Expect(sut.Info()[0].OpenUntil).NotTo(BeZero())
+	})
+
+	It("should close the circuit if the probe succeeds", func() {
+		// Given
+		sut := storage.NewRegistryHealth(1, 100*time.Millisecond, dir)
+		Expect(sut.RecordFailure("quay.io", errRefused)).To(BeTrue())
+		time.Sleep(150 * time.Millisecond)
+		_, err := sut.Order(sys, []storage.RegistryImageReference{quay})
+		Expect(err).To(BeNil())
+
+		// When
+		sut.RecordSuccess("quay.io")
+
+		// Then
+		res, err := sut.Order(sys, []storage.RegistryImageReference{quay, docker})
+		Expect(err).To(BeNil())
+		Expect(candidates(res)).To(Equal([]storage.RegistryImageReference{quay, docker}))
+		Expect(sut.Info()[0].CircuitOpen).To(BeFalse())
+	})
+
+	It("should skip mirrors with an open circuit", func() {
+		// Given
+		writeRegistriesConf(`
+[[registry]]
+location = "quay.io"
+
+[[registry.mirror]]
+location = "mirror.example.com"
+`)
+		sut := storage.NewRegistryHealth(1, time.Minute, dir)
+		Expect(sut.RecordFailure("mirror.example.com", errRefused)).To(BeTrue())
+
+		// When
+		res, err := sut.Order(sys, []storage.RegistryImageReference{quay})
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(endpoints(res)).To(Equal([]string{"quay.io"}))
+		Expect(candidates(res)).To(Equal([]storage.RegistryImageReference{quay}))
+	})
+
+	It("should try the registry of a candidate after its unhealthy mirrors", func() {
+		// Given
+		writeRegistriesConf(`
+[[registry]]
+location = "quay.io"
+
+[[registry.mirror]]
+location = "mirror.example.com"
+`)
+		sut := storage.NewRegistryHealth(2, time.Minute, dir)
+		Expect(sut.RecordFailure("mirror.example.com", errRefused)).To(BeTrue())
+
+		// When
+		res, err := sut.Order(sys, []storage.RegistryImageReference{quay, docker})
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(endpoints(res)).To(Equal([]string{"quay.io", "docker.io", "mirror.example.com"}))
+	})
+
+	It("should close the circuit on success", func() {
+		// Given
+		sut := storage.NewRegistryHealth(1, time.Minute, dir)
+		Expect(sut.RecordFailure("quay.io", errRefused)).To(BeTrue())
+
+		// When
+		sut.RecordSuccess("quay.io")
+
+		// Then
+		res, err := sut.Order(sys, []storage.RegistryImageReference{quay, docker})
+		Expect(err).To(BeNil())
+		Expect(candidates(res)).To(Equal([]storage.RegistryImageReference{quay, docker}))
+		info := sut.Info()
+		Expect(info[0].Healthy).To(BeTrue())
+		Expect(info[0].CircuitOpen).To(BeFalse())
+		Expect(info[0].TotalFailures).To(BeEquivalentTo(1))
+		Expect(info[0].TotalSuccesses).To(BeEquivalentTo(1))
+	})
+
+	It("should ignore errors not caused by the registry health", func() {
+		// Given
+		sut := storage.NewRegistryHealth(1, time.Minute, dir)
+
+		// When
+		recorded := sut.RecordFailure("quay.io", errors.New("unauthorized"))
+
+		// Then
+		Expect(recorded).To(BeFalse())
+		Expect(sut.RecordFailure("quay.io", context.Canceled)).To(BeFalse())
+		Expect(sut.Info()).To(BeEmpty())
+	})
+
+	It("should ignore empty endpoints", func() {
+		// Given
+		sut := storage.NewRegistryHealth(1, time.Minute, dir)
+
+		// When
+		recorded := sut.RecordFailure("", errRefused)
+		sut.RecordSuccess("")
+
+		// Then
+		Expect(recorded).To(BeFalse())
+		Expect(sut.Info()).To(BeEmpty())
+	})
+
+	t.Describe("PullSource", func() {
+		It("should return the registry without configuration", func() {
+			// Given
+			sut := storage.NewRegistryHealth(0, time.Minute, dir)
+
+			// When
+			res, err := sut.Order(sys, []storage.RegistryImageReference{quay})
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(endpoints(res)).To(Equal([]string{"quay.io"}))
+			Expect(res[0].SystemContext(sys)).To(Equal(sys))
+		})
+
+		It("should return the configured location", func() {
+			// Given
+			writeRegistriesConf(`
+[[registry]]
+prefix = "quay.io"
+location = "registry.example.com/quay"
+`)
+			sut := storage.NewRegistryHealth(0, time.Minute, dir)
+
+			// When
+			res, err := sut.Order(sys, []storage.RegistryImageReference{quay})
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(endpoints(res)).To(Equal([]string{"registry.example.com"}))
+			Expect(res[0].SystemContext(sys)).To(Equal(sys))
+		})
+
+		It("should restrict every mirror to a pull source of its own", func() {
+			// Given
+			writeRegistriesConf(`
+credential-helpers = ["secret-helper"]
+
+[[registry]]
+location = "quay.io"
+insecure = true
+
+[[registry.mirror]]
+location = "mirror.example.com/quay"
+
+[[registry.mirror]]
+location = "other-mirror.example.com"
+`)
+			sut := storage.NewRegistryHealth(0, time.Minute, dir)
+
+			// When
+			res, err := sut.Order(sys, []storage.RegistryImageReference{quay})
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(endpoints(res)).To(Equal([]string{"mirror.example.com", "other-mirror.example.com", "quay.io"}))
+			for i, location := range []string{"mirror.example.com/quay/image", "other-mirror.example.com/image", "quay.io/image"} {
+				sourceSys := res[i].SystemContext(sys)
+				Expect(sourceSys).NotTo(Equal(sys))
+				registry, err := sysregistriesv2.FindRegistry(sourceSys, quay.Raw().Name())
+				Expect(err).To(BeNil())
+				sources, err := registry.PullSourcesFromReference(quay.Raw())
+				Expect(err).To(BeNil())
+				Expect(sources).To(HaveLen(1))
+				Expect(sources[0].Reference.String()).To(Equal(location + ":latest"))
+				Expect(sources[0].Endpoint.Insecure).To(Equal(i == 2))
+				helpers, err := sysregistriesv2.CredentialHelpers(sourceSys)
+				Expect(err).To(BeNil())
+				Expect(helpers).To(Equal([]string{"secret-helper"}))
+			}
+		})
+
+		It("should keep blocked hosts blocked", func() {
+			// Given
+			writeRegistriesConf(`
+[[registry]]
+location = "quay.io"
+
+[[registry.mirror]]
+location = "mirror.example.com"
+
+[[registry]]
+location = "mirror.example.com"
+blocked = true
+`)
+			sut := storage.NewRegistryHealth(0, time.Minute, dir)
+
+			// When
+			res, err := sut.Order(sys, []storage.RegistryImageReference{quay})
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(endpoints(res)).To(Equal([]string{"mirror.example.com", "quay.io"}))
+			registry, err := sysregistriesv2.FindRegistry(res[0].SystemContext(sys), "mirror.example.com")
+			Expect(err).To(BeNil())
+			Expect(registry.Blocked).To(BeTrue())
+		})
+
+		It("should return the registry if mirrors are only used for digests", func() {
+			// Given
+			writeRegistriesConf(`
+[[registry]]
+location = "quay.io"
+mirror-by-digest-only = true
+
+[[registry.mirror]]
+location = "mirror.example.com"
+`)
+			sut := storage.NewRegistryHealth(0, time.Minute, dir)
+
+			// When
+			res, err := sut.Order(sys, []storage.RegistryImageReference{quay})
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(endpoints(res)).To(Equal([]string{"quay.io"}))
+			Expect(res[0].SystemContext(sys)).To(Equal(sys))
+		})
+	})
+
+	It("should classify registry failures", func() {
+		Expect(storage.IsRegistryFailure(nil)).To(BeFalse())
+		Expect(storage.IsRegistryFailure(errRefused)).To(BeTrue())
+		Expect(storage.IsRegistryFailure(context.DeadlineExceeded)).To(BeTrue())
+		Expect(storage.IsRegistryFailure(fmt.Errorf("wrapped: %w", context.Canceled))).To(BeFalse())
+		Expect(storage.IsRegistryFailure(errors.New("manifest unknown"))).To(BeFalse())
+	})
+})
@@ -54,6 +54,10 @@ const (
 	// SeccompNotifierActionStop indicates that a container should be stopped if used via the SeccompNotifierActionAnnotation key.
 	SeccompNotifierActionStop = "stop"
 
+	// SeccompNotifierActionRecord indicates that the syscalls used by a container should be allowed and recorded into
+	// a seccomp profile if used via the SeccompNotifierActionAnnotation key.
+	SeccompNotifierActionRecord = "record"
+
 	// PodLinuxOverhead indicates the overheads associated with the pod
 	PodLinuxOverhead = "io.kubernetes.cri-o.PodLinuxOverhead"
 
@@ -66,6 +70,17 @@ const (
 
 	// PlatformRuntimePath indicates the runtime path that CRI-O should use for a specific platform.
 	PlatformRuntimePath = "io.kubernetes.cri-o.PlatformRuntimePath"
+
+	// PortForwardProtocolsAnnotation is a comma separated list of ports and their protocol
+	// (e.g. "53/udp,9999/sctp") to be used for port forwarding into the pod. Ports
+	// not listed there are forwarded using TCP.
+	PortForwardProtocolsAnnotation = "io.kubernetes.cri-o.PortForwardProtocols"
+
+	// ImageVolumesAnnotation contains the image volumes created by CRI-O for a container.
+	ImageVolumesAnnotation = "io.kubernetes.cri-o.ImageVolumes"
+
+	// BandwidthLimitsAnnotation contains the bandwidth limits applied by CRI-O on the pod network interface.
+	BandwidthLimitsAnnotation = "io.kubernetes.cri-o.BandwidthLimits"
 )
 
 var AllAllowedAnnotations = []string{
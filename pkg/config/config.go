@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/container-orchestrated-devices/container-device-interface/pkg/cdi"
@@ -20,6 +21,7 @@ import (
 	"github.com/containers/image/v5/types"
 	"github.com/containers/podman/v4/pkg/rootless"
 	"github.com/containers/storage"
+	"github.com/cri-o/cri-o/internal/audit"
 	"github.com/cri-o/cri-o/internal/config/apparmor"
 	"github.com/cri-o/cri-o/internal/config/blockio"
 	"github.com/cri-o/cri-o/internal/config/capabilities"
@@ -62,14 +64,19 @@ const (
 	defaultMonitorCgroup       = "system.slice"
 	MonitorExecCgroupDefault   = ""
 	MonitorExecCgroupContainer = "container"
+	defaultImageGCLowThreshold = 80
+	defaultImageGCMinAge       = 2 * time.Minute
+	defaultImageGCInterval     = 5 * time.Minute
+	defaultRegistryCooldown    = 30 * time.Second
 )
 
 // Config represents the entire set of configuration values that can be set for
 // the server. This is intended to be loaded from a toml-encoded config file.
 type Config struct {
 	Comment          string
-	singleConfigPath string // Path to the single config file
-	dropInConfigDir  string // Path to the drop-in config files
+	singleConfigPath string            // Path to the single config file
+	dropInConfigDir  string            // Path to the drop-in config files
+	origins          map[string]Origin // Origins of the set options by TOML key
 
 	RootConfig
 	APIConfig
@@ -116,6 +123,18 @@ const (
 	// DefaultLogSizeMax is the default value for the maximum log size
 	// allowed for a container. Negative values mean that no limit is imposed.
 	DefaultLogSizeMax = -1
+
+	// DefaultAuditLogJournaldIdentifier is the default syslog identifier of
+	// the audit records written to journald.
+	DefaultAuditLogJournaldIdentifier = "crio-audit"
+
+	// DefaultAuditLogMaxSize is the default size after which the audit log
+	// file gets rotated.
+	DefaultAuditLogMaxSize = 100 * 1024 * 1024
+
+	// DefaultAuditLogMaxFiles is the default number of rotated audit log
+	// files to keep.
+	DefaultAuditLogMaxFiles = 5
 )
 
 const (
@@ -325,6 +344,10 @@ type RuntimeConfig struct {
 	// default for the runtime.
 	SeccompProfile string `toml:"seccomp_profile"`
 
+	// SeccompProfileRecordDir is the directory where the seccomp profiles
+	// recorded by the seccomp notifier get written to on container exit.
+	SeccompProfileRecordDir string `toml:"seccomp_profile_record_dir"`
+
 	// ApparmorProfile is the apparmor profile name which is used as the
 	// default for the runtime.
 	ApparmorProfile string `toml:"apparmor_profile"`
@@ -438,6 +461,37 @@ type RuntimeConfig struct {
 	// EnablePodEvents specifies if the container pod-level events should be generated to optimize the PLEG at Kubelet.
 	EnablePodEvents bool `toml:"enable_pod_events"`
 
+	// ContainerEventsJournalSize is the maximum number of container events
+	// kept in the on-disk journal below the run root. The journal allows
+	// clients to replay the events they missed while being disconnected. A
+	// value of zero disables the journal.
+	ContainerEventsJournalSize int `toml:"container_events_journal_size"`
+
+	// AuditLog is the destination of the audit records of exec, attach and
+	// port forward sessions. It is either an absolute file path or "journald".
+	// An empty value disables the audit log.
+	AuditLog string `toml:"audit_log"`
+
+	// AuditLogJournaldIdentifier is the syslog identifier of the audit records
+	// written to journald.
+	AuditLogJournaldIdentifier string `toml:"audit_log_journald_identifier"`
+
+	// AuditLogMaxSize is the size in bytes after which the audit log file gets
+	// rotated. A value of zero disables the rotation.
+	AuditLogMaxSize int64 `toml:"audit_log_max_size"`
+
+	// AuditLogMaxFiles is the number of rotated audit log files to keep.
+	AuditLogMaxFiles int `toml:"audit_log_max_files"`
+
+	// AuditLogOperations are the audited operations, which can be "exec",
+	// "exec_sync", "attach" and "port_forward". All operations are audited if
+	// empty.
+	AuditLogOperations []string `toml:"audit_log_operations"`
+
+	// AuditLogNamespaces are the pod namespaces to be audited. All pods are
+	// audited if empty.
+	AuditLogNamespaces []string `toml:"audit_log_namespaces"`
+
 	// IrqBalanceConfigRestoreFile is the irqbalance service banned CPU list to restore.
 	// If empty, no restoration attempt will be done.
 	IrqBalanceConfigRestoreFile string `toml:"irqbalance_config_restore_file"`
@@ -527,6 +581,43 @@ type ImageConfig struct {
 	Registries []string `toml:"registries"`
 	// Temporary directory for big files
 	BigFilesTemporaryDir string `toml:"big_files_temporary_dir"`
+	// ImageGCHighThresholdPercent is the disk usage of the storage root in
+	// percent, which triggers the image garbage collection. A value of zero
+	// disables the disk usage based image garbage collection.
+	ImageGCHighThresholdPercent int `toml:"image_gc_high_threshold_percent"`
+	// ImageGCLowThresholdPercent is the disk usage of the storage root in
+	// percent, which the image garbage collection tries to reach once it has
+	// been triggered.
+	ImageGCLowThresholdPercent int `toml:"image_gc_low_threshold_percent"`
+	// ImageGCMinAge is the minimum time an image has to be unused before it
+	// can be removed by the image garbage collection.
+	ImageGCMinAge time.Duration `toml:"image_gc_min_age"`
+	// ImageGCMaxAge is the maximum time an image can be unused before it gets
+	// removed by the image garbage collection, independently of the disk
+	// usage. A value of zero disables the age based image garbage collection.
+	ImageGCMaxAge time.Duration `toml:"image_gc_max_age"`
+	// ImageGCInterval is the interval in which the image garbage collection
+	// runs.
+	ImageGCInterval time.Duration `toml:"image_gc_interval"`
+	// MaxConcurrentPulls is the maximum number of image pulls running in
+	// parallel on the node. A value of zero means no limit.
+	MaxConcurrentPulls int `toml:"max_concurrent_pulls"`
+	// MaxConcurrentPullsPerRegistry is the maximum number of image pulls
+	// running in parallel from a single registry host. A value of zero means
+	// no limit.
+	MaxConcurrentPullsPerRegistry int `toml:"max_concurrent_pulls_per_registry"`
+	// MaxConcurrentPullsPerNamespace is the maximum number of image pulls
+	// running in parallel for a single Kubernetes namespace. A value of zero
+	// means no limit.
+	MaxConcurrentPullsPerNamespace int `toml:"max_concurrent_pulls_per_namespace"`
+	// RegistryFailureThreshold is the number of consecutive failed requests
+	// to a registry, after which it gets skipped by image pulls for the
+	// RegistryCircuitBreakerCooldown. Every mirror of a registry is tracked
+	// on its own. A value of zero disables skipping unhealthy registries.
+	RegistryFailureThreshold int `toml:"registry_failure_threshold"`
+	// RegistryCircuitBreakerCooldown is the time an unhealthy registry gets
+	// skipped by image pulls, before a single pull probes it again.
+	RegistryCircuitBreakerCooldown time.Duration `toml:"registry_circuit_breaker_cooldown"`
 }
 
 // NetworkConfig represents the "crio.network" TOML config table
@@ -679,7 +770,7 @@ func (t *tomlConfig) fromConfig(c *Config) {
 // Returns errors encountered when reading or parsing the files, or nil
 // otherwise.
 func (c *Config) UpdateFromFile(path string) error {
-	if err := c.UpdateFromDropInFile(path); err != nil {
+	if err := c.updateFromFile(path, Origin{Kind: OriginFile, Source: path}); err != nil {
 		return err
 	}
 	c.singleConfigPath = path
@@ -692,6 +783,12 @@ func (c *Config) UpdateFromFile(path string) error {
 // Returns errors encountered when reading or parsing the files, or nil
 // otherwise.
 func (c *Config) UpdateFromDropInFile(path string) error {
+	return c.updateFromFile(path, Origin{Kind: OriginDropIn, Source: path})
+}
+
+// updateFromFile populates the Config from the TOML-encoded file at the given
+// path and records the origin of the options set in the file.
+func (c *Config) updateFromFile(path string, origin Origin) error {
 	// keeps the storage options from storage.conf and merge it to crio config
 	var storageOpts []string
 	storageOpts = append(storageOpts, c.StorageOptions...)
@@ -708,7 +805,7 @@ func (c *Config) UpdateFromDropInFile(path string) error {
 	t := new(tomlConfig)
 	t.fromConfig(c)
 
-	_, err = toml.Decode(string(data), t)
+	meta, err := toml.Decode(string(data), t)
 	if err != nil {
 		return fmt.Errorf("unable to decode configuration %v: %w", path, err)
 	}
@@ -735,6 +832,7 @@ func (c *Config) UpdateFromDropInFile(path string) error {
 	}
 
 	t.toConfig(c)
+	c.setFileOrigins(&meta, origin)
 	return nil
 }
 
@@ -778,6 +876,30 @@ func (c *Config) UpdateFromPath(path string) error {
 	return nil
 }
 
+// UnknownOptions returns the TOML keys of all options in the TOML-encoded file
+// at the given path which are not known by the configuration, for example
+// because they have been removed.
+func UnknownOptions(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := toml.Decode(string(data), new(tomlConfig))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode configuration %v: %w", path, err)
+	}
+
+	unknown := []string{}
+	for _, key := range meta.Undecoded() {
+		if meta.Type(key...) == "Hash" {
+			continue
+		}
+		unknown = append(unknown, key.String())
+	}
+	return unknown, nil
+}
+
 // ToFile outputs the given Config as a TOML-encoded file at the given path.
 // Returns errors encountered when generating or writing the file, or nil
 // otherwise.
@@ -862,6 +984,9 @@ func DefaultConfig() (*Config, error) {
 			MinimumMappableUID:          -1,
 			MinimumMappableGID:          -1,
 			LogSizeMax:                  DefaultLogSizeMax,
+			AuditLogJournaldIdentifier:  DefaultAuditLogJournaldIdentifier,
+			AuditLogMaxSize:             DefaultAuditLogMaxSize,
+			AuditLogMaxFiles:            DefaultAuditLogMaxFiles,
 			CtrStopTimeout:              defaultCtrStopTimeout,
 			DefaultCapabilities:         capabilities.Default(),
 			LogLevel:                    "info",
@@ -888,6 +1013,12 @@ func DefaultConfig() (*Config, error) {
 			PauseCommand:       "/pause",
 			ImageVolumes:       ImageVolumesMkdir,
 			SignaturePolicyDir: "/etc/crio/policies",
+
+			ImageGCLowThresholdPercent: defaultImageGCLowThreshold,
+			ImageGCMinAge:              defaultImageGCMinAge,
+			ImageGCInterval:            defaultImageGCInterval,
+
+			RegistryCircuitBreakerCooldown: defaultRegistryCooldown,
 		},
 		NetworkConfig: NetworkConfig{
 			NetworkDir: cniConfigDir,
@@ -1062,6 +1193,18 @@ func (c *RuntimeConfig) Validate(systemContext *types.SystemContext, onExecution
 		logrus.Warnf("Forcing ctr_stop_timeout to lowest possible value of %ds", c.CtrStopTimeout)
 	}
 
+	if c.ContainerEventsJournalSize < 0 {
+		return fmt.Errorf("container_events_journal_size %d must not be negative", c.ContainerEventsJournalSize)
+	}
+
+	if c.SeccompProfileRecordDir != "" && !filepath.IsAbs(c.SeccompProfileRecordDir) {
+		return fmt.Errorf("seccomp_profile_record_dir %q must be an absolute path", c.SeccompProfileRecordDir)
+	}
+
+	if err := c.AuditConfig().Validate(); err != nil {
+		return fmt.Errorf("invalid audit log configuration: %w", err)
+	}
+
 	if _, err := c.Sysctls(); err != nil {
 		return fmt.Errorf("invalid default_sysctls: %w", err)
 	}
@@ -1387,6 +1530,18 @@ func (c *RuntimeConfig) CheckpointRestore() bool {
 	return c.EnableCriuSupport
 }
 
+// AuditConfig returns the audit log configuration
+func (c *RuntimeConfig) AuditConfig() *audit.Config {
+	return &audit.Config{
+		Destination:        c.AuditLog,
+		JournaldIdentifier: c.AuditLogJournaldIdentifier,
+		MaxSize:            c.AuditLogMaxSize,
+		MaxFiles:           c.AuditLogMaxFiles,
+		Operations:         c.AuditLogOperations,
+		Namespaces:         c.AuditLogNamespaces,
+	}
+}
+
 func validateExecutablePath(executable, currentPath string) (string, error) {
 	if currentPath == "" {
 		path, err := exec.LookPath(executable)
@@ -1412,6 +1567,22 @@ func (c *ImageConfig) Validate(onExecution bool) error {
 	if _, err := c.ParsePauseImage(); err != nil {
 		return fmt.Errorf("invalid pause image %q: %w", c.PauseImage, err)
 	}
+	if c.RegistryFailureThreshold > 0 && c.RegistryCircuitBreakerCooldown <= 0 {
+		return fmt.Errorf("registry_circuit_breaker_cooldown %s has to be positive", c.RegistryCircuitBreakerCooldown)
+	}
+	if err := c.validateImageGC(); err != nil {
+		return fmt.Errorf("invalid image garbage collection config: %w", err)
+	}
+	for option, value := range map[string]int{
+		"max_concurrent_pulls":               c.MaxConcurrentPulls,
+		"max_concurrent_pulls_per_registry":  c.MaxConcurrentPullsPerRegistry,
+		"max_concurrent_pulls_per_namespace": c.MaxConcurrentPullsPerNamespace,
+		"registry_failure_threshold":         c.RegistryFailureThreshold,
+	} {
+		if value < 0 {
+			return fmt.Errorf("%s %d must not be negative", option, value)
+		}
+	}
 	if onExecution {
 		if err := os.MkdirAll(c.SignaturePolicyDir, 0o755); err != nil {
 			return fmt.Errorf("cannot create signature policy dir: %w", err)
@@ -1420,6 +1591,35 @@ func (c *ImageConfig) Validate(onExecution bool) error {
 	return nil
 }
 
+func (c *ImageConfig) validateImageGC() error {
+	if c.ImageGCHighThresholdPercent < 0 || c.ImageGCHighThresholdPercent > 100 {
+		return fmt.Errorf("image_gc_high_threshold_percent %d is not within 0 and 100", c.ImageGCHighThresholdPercent)
+	}
+	if c.ImageGCHighThresholdPercent > 0 &&
+		(c.ImageGCLowThresholdPercent < 0 || c.ImageGCLowThresholdPercent >= c.ImageGCHighThresholdPercent) {
+		return fmt.Errorf(
+			"image_gc_low_threshold_percent %d has to be within 0 and image_gc_high_threshold_percent %d",
+			c.ImageGCLowThresholdPercent, c.ImageGCHighThresholdPercent,
+		)
+	}
+	if c.ImageGCMinAge < 0 {
+		return fmt.Errorf("image_gc_min_age %s must not be negative", c.ImageGCMinAge)
+	}
+	if c.ImageGCMaxAge < 0 || (c.ImageGCMaxAge > 0 && c.ImageGCMaxAge < c.ImageGCMinAge) {
+		return fmt.Errorf("image_gc_max_age %s has to be zero or greater than image_gc_min_age %s", c.ImageGCMaxAge, c.ImageGCMinAge)
+	}
+	if c.ImageGCEnabled() && c.ImageGCInterval <= 0 {
+		return fmt.Errorf("image_gc_interval %s has to be positive", c.ImageGCInterval)
+	}
+	return nil
+}
+
+// ImageGCEnabled returns true if either the disk usage or the age based image
+// garbage collection is enabled.
+func (c *ImageConfig) ImageGCEnabled() bool {
+	return c.ImageGCHighThresholdPercent > 0 || c.ImageGCMaxAge > 0
+}
+
 // ParsePauseImage parses the .PauseImage value as into a validated, well-typed value.
 func (c *ImageConfig) ParsePauseImage() (references.RegistryImageReference, error) {
 	return references.ParseRegistryImageReferenceFromOutOfProcessData(c.PauseImage)
@@ -1568,6 +1768,58 @@ func (r *RuntimeHandler) ValidateRuntimeAllowedAnnotations() error {
 	return nil
 }
 
+// Probe checks if the runtime handler is currently usable without modifying
+// it. Contrary to Validate, this method is meant to be called periodically,
+// for example to verify that the runtime binary has not been removed from the
+// system after the server has been started.
+func (r *RuntimeHandler) Probe(name string) error {
+	runtimePath := r.RuntimePath
+	if runtimePath == "" {
+		executable, err := exec.LookPath(name)
+		if err != nil {
+			return fmt.Errorf("%q not found in $PATH: %w", name, err)
+		}
+		runtimePath = executable
+	}
+	if err := probeExecutable(runtimePath); err != nil {
+		return fmt.Errorf("runtime_path: %w", err)
+	}
+
+	if r.RuntimeType != RuntimeTypeVM && r.MonitorPath != "" {
+		if err := probeExecutable(r.MonitorPath); err != nil {
+			return fmt.Errorf("monitor_path: %w", err)
+		}
+	}
+
+	if r.RuntimeConfigPath != "" {
+		if _, err := os.Stat(r.RuntimeConfigPath); err != nil {
+			return fmt.Errorf("runtime_config_path: %w", err)
+		}
+	}
+	return nil
+}
+
+// probeExecutable returns an error if path is not an executable file.
+func probeExecutable(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() || info.Mode()&0o111 == 0 {
+		return fmt.Errorf("%s is not an executable file", path)
+	}
+	return nil
+}
+
+// RuntimeFeatures returns the output of the "features" subcommand of the
+// runtime, or nil if the runtime does not support it.
+func (r *RuntimeHandler) RuntimeFeatures() *features.Features {
+	if r.features.OCIVersionMin == "" && r.features.OCIVersionMax == "" {
+		return nil
+	}
+	return &r.features
+}
+
 // RuntimeSupportsIDMap returns whether this runtime supports the "runtime features"
 // command, and that the output of that command advertises IDMap mounts as an option
 func (r *RuntimeHandler) RuntimeSupportsIDMap() bool {
@@ -1613,6 +1865,41 @@ func (c *NetworkConfig) CNIPluginAddWatcher() chan bool {
 	return c.cniManager.AddWatcher()
 }
 
+// CNIManagerPodCIDRs returns the pod CIDRs known by the CNI manager
+func (c *NetworkConfig) CNIManagerPodCIDRs() []string {
+	if c.cniManager == nil {
+		return nil
+	}
+	return c.cniManager.PodCIDRs()
+}
+
+// CNIManagerSetPodCIDRs sets the pod CIDRs of the CNI manager and returns
+// whether they have been changed
+func (c *NetworkConfig) CNIManagerSetPodCIDRs(cidrs []string) bool {
+	if c.cniManager == nil {
+		return false
+	}
+	return c.cniManager.SetPodCIDRs(cidrs)
+}
+
+// CNIManagerPodIPRanges returns the pod CIDRs of the CNI manager as CNI
+// ipRanges capability
+func (c *NetworkConfig) CNIManagerPodIPRanges() [][]ocicni.IpRange {
+	if c.cniManager == nil {
+		return nil
+	}
+	return c.cniManager.PodIPRanges()
+}
+
+// CNIManagerDefaultNetworkHasCapability returns true if the default CNI
+// network supports the provided capability
+func (c *NetworkConfig) CNIManagerDefaultNetworkHasCapability(capability string) (bool, error) {
+	if c.cniManager == nil {
+		return false, errors.New("CNI manager is not initialized")
+	}
+	return c.cniManager.DefaultNetworkHasCapability(capability)
+}
+
 // CNIManagerShutdown shuts down the CNI Manager
 func (c *NetworkConfig) CNIManagerShutdown() {
 	c.cniManager.Shutdown()
@@ -5,6 +5,7 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"time"
 
 	"github.com/containers/storage"
 	crioann "github.com/cri-o/cri-o/pkg/annotations"
@@ -419,6 +420,39 @@ var _ = t.Describe("Config", func() {
 			// Then
 			Expect(err).NotTo(BeNil())
 		})
+
+		It("should fail on negative container_events_journal_size", func() {
+			// Given
+			sut.ContainerEventsJournalSize = -1
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail on relative audit_log", func() {
+			// Given
+			sut.AuditLog = "audit.log"
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail on invalid audit_log_operations", func() {
+			// Given
+			sut.AuditLogOperations = []string{"logs"}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
 	})
 	t.Describe("TranslateMonitorFields", func() {
 		It("should fail on invalid conmon cgroup", func() {
@@ -728,6 +762,89 @@ var _ = t.Describe("Config", func() {
 			// Then
 			Expect(err).NotTo(BeNil())
 		})
+
+		It("should succeed with image garbage collection enabled", func() {
+			// Given
+			sut.ImageConfig.ImageGCHighThresholdPercent = 90
+			sut.ImageConfig.ImageGCMaxAge = time.Hour
+
+			// When
+			err := sut.ImageConfig.Validate(false)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.ImageConfig.ImageGCEnabled()).To(BeTrue())
+		})
+
+		It("should fail when the image GC high threshold is out of range", func() {
+			// Given
+			sut.ImageConfig.ImageGCHighThresholdPercent = 101
+
+			// When
+			err := sut.ImageConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail when the image GC low threshold is not below the high threshold", func() {
+			// Given
+			sut.ImageConfig.ImageGCHighThresholdPercent = 80
+			sut.ImageConfig.ImageGCLowThresholdPercent = 80
+
+			// When
+			err := sut.ImageConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail when the image GC max age is lower than the min age", func() {
+			// Given
+			sut.ImageConfig.ImageGCMinAge = time.Hour
+			sut.ImageConfig.ImageGCMaxAge = time.Minute
+
+			// When
+			err := sut.ImageConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail when a pull concurrency limit is negative", func() {
+			// Given
+			sut.ImageConfig.MaxConcurrentPullsPerRegistry = -1
+
+			// When
+			err := sut.ImageConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail when the registry circuit breaker cooldown is not positive", func() {
+			// Given
+			sut.ImageConfig.RegistryFailureThreshold = 3
+			sut.ImageConfig.RegistryCircuitBreakerCooldown = 0
+
+			// When
+			err := sut.ImageConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail when the image GC interval is not positive", func() {
+			// Given
+			sut.ImageConfig.ImageGCHighThresholdPercent = 90
+			sut.ImageConfig.ImageGCInterval = 0
+
+			// When
+			err := sut.ImageConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
 	})
 
 	t.Describe("ImageConfig.ParsePauseImage", func() {
@@ -1376,4 +1493,79 @@ var _ = t.Describe("Config", func() {
 			Expect(err).To(BeNil())
 		})
 	})
+
+	t.Describe("RuntimeHandler.Probe", func() {
+		It("should succeed with executable runtime_path", func() {
+			// Given
+			handler := &config.RuntimeHandler{RuntimePath: validFilePath}
+
+			// When
+			err := handler.Probe("runc")
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail with not existing runtime_path", func() {
+			// Given
+			handler := &config.RuntimeHandler{RuntimePath: invalidPath}
+
+			// When
+			err := handler.Probe("runc")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with directory as runtime_path", func() {
+			// Given
+			handler := &config.RuntimeHandler{RuntimePath: validDirPath}
+
+			// When
+			err := handler.Probe("runc")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with not existing monitor_path", func() {
+			// Given
+			handler := &config.RuntimeHandler{
+				RuntimePath: validFilePath,
+				MonitorPath: invalidPath,
+			}
+
+			// When
+			err := handler.Probe("runc")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should ignore monitor_path for VM runtime type", func() {
+			// Given
+			handler := &config.RuntimeHandler{
+				RuntimePath: validFilePath,
+				RuntimeType: config.RuntimeTypeVM,
+				MonitorPath: invalidPath,
+			}
+
+			// When
+			err := handler.Probe("kata")
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should not modify the runtime handler", func() {
+			// Given
+			handler := &config.RuntimeHandler{}
+
+			// When
+			_ = handler.Probe("sh")
+
+			// Then
+			Expect(handler.RuntimePath).To(BeEmpty())
+		})
+	})
 })
@@ -0,0 +1,215 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/cri-o/cri-o/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+// OriginKind is the kind of source which set a configuration option.
+type OriginKind string
+
+const (
+	// OriginDefault is used for options which have not been set at all.
+	OriginDefault OriginKind = "default"
+
+	// OriginFile is used for options set in the main configuration file.
+	OriginFile OriginKind = "file"
+
+	// OriginDropIn is used for options set in a drop-in configuration file.
+	OriginDropIn OriginKind = "drop-in"
+
+	// OriginFlag is used for options set by a command line flag.
+	OriginFlag OriginKind = "flag"
+
+	// OriginEnv is used for options set by an environment variable.
+	OriginEnv OriginKind = "env"
+)
+
+// Origin is the source of an effective configuration option.
+type Origin struct {
+	// Kind is the kind of the source.
+	Kind OriginKind
+
+	// Source is the file path, flag or environment variable which set the
+	// option. It is empty for OriginDefault.
+	Source string
+}
+
+// String returns the origin in the form "kind:source", for example
+// "drop-in:/etc/crio/crio.conf.d/10-crun.conf".
+func (o Origin) String() string {
+	if o.Source == "" {
+		return string(o.Kind)
+	}
+	return string(o.Kind) + ":" + o.Source
+}
+
+// SetOptionOrigin records the origin of the option with the provided TOML key,
+// for example "crio.runtime.log_level". Recording the origin of a table applies
+// to all of its options and replaces their previously recorded origins.
+func (c *Config) SetOptionOrigin(key string, origin Origin) {
+	if c.origins == nil {
+		c.origins = make(map[string]Origin)
+	}
+	for k := range c.origins {
+		if strings.HasPrefix(k, key+".") {
+			delete(c.origins, k)
+		}
+	}
+	c.origins[key] = origin
+}
+
+// reloadOrigins replaces the recorded origins with the ones of the reloaded
+// configuration. The origins of options set by command line flags or
+// environment variables are kept, because the reloaded configuration is only
+// loaded from the configuration files. This does not apply to options whose
+// value got changed by the reload, because the values of the configuration
+// files now take precedence. oldValues are the option values before the
+// reload, as returned by optionValues.
+func (c *Config) reloadOrigins(newConfig *Config, oldValues map[string]string) {
+	newValues, err := c.optionValues()
+	if err != nil {
+		logrus.Warnf("Unable to compare the reloaded option values: %v", err)
+		newValues = oldValues
+	}
+
+	kept := []string{}
+	for k, origin := range c.origins {
+		if origin.Kind != OriginFlag && origin.Kind != OriginEnv {
+			continue
+		}
+		if oldValues[k] != newValues[k] {
+			continue
+		}
+		kept = append(kept, k)
+	}
+	// Tables sort before their options, which keeps the origins of options
+	// set more specifically than their table.
+	sort.Strings(kept)
+
+	origins := make(map[string]Origin, len(newConfig.origins)+len(kept))
+	for k, origin := range newConfig.origins {
+		origins[k] = origin
+	}
+	oldOrigins := c.origins
+	c.origins = origins
+	for _, k := range kept {
+		c.SetOptionOrigin(k, oldOrigins[k])
+	}
+}
+
+// optionValues returns the TOML representation of the values of all options
+// and tables of the configuration by their key.
+func (c *Config) optionValues() (map[string]string, error) {
+	values, meta, err := c.decodeOptions()
+	if err != nil {
+		return nil, err
+	}
+	formatted := make(map[string]string, len(meta.Keys()))
+	for _, key := range meta.Keys() {
+		value, err := formatOptionValue(lookupOptionValue(values, key))
+		if err != nil {
+			return nil, fmt.Errorf("format option %s: %w", key, err)
+		}
+		formatted[key.String()] = value
+	}
+	return formatted, nil
+}
+
+// OptionOrigin returns the origin of the option with the provided TOML key.
+// Options without a recorded origin inherit the origin of their closest
+// recorded table, or are reported as OriginDefault.
+func (c *Config) OptionOrigin(key string) Origin {
+	for k := key; k != ""; {
+		if origin, ok := c.origins[k]; ok {
+			return origin
+		}
+		i := strings.LastIndex(k, ".")
+		if i < 0 {
+			break
+		}
+		k = k[:i]
+	}
+	return Origin{Kind: OriginDefault}
+}
+
+// setFileOrigins records the origin of all options set in the decoded file.
+func (c *Config) setFileOrigins(meta *toml.MetaData, origin Origin) {
+	for _, key := range meta.Keys() {
+		// Tables are only containers of options, which would wrongly
+		// attribute all options of the table to the file.
+		if meta.Type(key...) == "Hash" {
+			continue
+		}
+		c.SetOptionOrigin(key.String(), origin)
+	}
+}
+
+// Options returns all effective options of the configuration together with
+// their value and origin, in the order of the TOML representation.
+func (c *Config) Options() ([]types.ConfigOption, error) {
+	values, meta, err := c.decodeOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	options := []types.ConfigOption{}
+	for _, key := range meta.Keys() {
+		if meta.Type(key...) == "Hash" {
+			continue
+		}
+		value, err := formatOptionValue(lookupOptionValue(values, key))
+		if err != nil {
+			return nil, fmt.Errorf("format option %s: %w", key, err)
+		}
+		options = append(options, types.ConfigOption{
+			Option: key.String(),
+			Value:  value,
+			Origin: c.OptionOrigin(key.String()).String(),
+		})
+	}
+	return options, nil
+}
+
+// decodeOptions returns the TOML representation of the configuration.
+func (c *Config) decodeOptions() (map[string]any, *toml.MetaData, error) {
+	b, err := c.ToBytes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := map[string]any{}
+	meta, err := toml.Decode(string(b), &values)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode configuration: %w", err)
+	}
+	return values, &meta, nil
+}
+
+func lookupOptionValue(values map[string]any, key toml.Key) any {
+	var value any = values
+	for _, part := range key {
+		table, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = table[part]
+	}
+	return value
+}
+
+// formatOptionValue returns the TOML representation of an option value.
+func formatOptionValue(value any) (string, error) {
+	const name = "v"
+	var buffer bytes.Buffer
+	if err := toml.NewEncoder(&buffer).Encode(map[string]any{name: value}); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(strings.TrimPrefix(buffer.String(), name+" = ")), nil
+}
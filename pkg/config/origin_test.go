@@ -0,0 +1,105 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/cri-o/cri-o/pkg/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("Origin", func() {
+	BeforeEach(beforeEach)
+
+	writeConfig := func(path, content string) {
+		Expect(os.WriteFile(path, []byte(content), 0o644)).To(BeNil())
+	}
+
+	optionOrigins := func() map[string]string {
+		options, err := sut.Options()
+		Expect(err).To(BeNil())
+		origins := map[string]string{}
+		for _, option := range options {
+			origins[option.Option] = option.Origin
+		}
+		return origins
+	}
+
+	It("should report the default origin without any file", func() {
+		// Given
+		// When
+		origin := sut.OptionOrigin("crio.runtime.log_level")
+
+		// Then
+		Expect(origin.Kind).To(Equal(config.OriginDefault))
+		Expect(origin.String()).To(Equal("default"))
+	})
+
+	It("should record the main file and drop-in origins", func() {
+		// Given
+		dir := t.MustTempDir("crio-origin")
+		mainFile := filepath.Join(dir, "crio.conf")
+		writeConfig(mainFile, "[crio.runtime]\nlog_level = \"debug\"\npids_limit = 10\n")
+		dropInDir := filepath.Join(dir, "crio.conf.d")
+		Expect(os.Mkdir(dropInDir, 0o755)).To(BeNil())
+		dropInFile := filepath.Join(dropInDir, "10-pids.conf")
+		writeConfig(dropInFile, "[crio.runtime]\npids_limit = 20\n")
+
+		// When
+		Expect(sut.UpdateFromFile(mainFile)).To(BeNil())
+		Expect(sut.UpdateFromPath(dropInDir)).To(BeNil())
+
+		// Then
+		Expect(sut.OptionOrigin("crio.runtime.log_level").String()).To(Equal("file:" + mainFile))
+		Expect(sut.OptionOrigin("crio.runtime.pids_limit").String()).To(Equal("drop-in:" + dropInFile))
+		Expect(sut.OptionOrigin("crio.runtime.log_filter").Kind).To(Equal(config.OriginDefault))
+	})
+
+	It("should inherit the origin of a recorded table", func() {
+		// Given
+		file := filepath.Join(t.MustTempDir("crio-origin"), "crio.conf")
+		writeConfig(file, "[crio.runtime.runtimes.foo]\nruntime_path = \"/usr/bin/foo\"\n")
+		Expect(sut.UpdateFromFile(file)).To(BeNil())
+
+		// When
+		sut.SetOptionOrigin("crio.runtime.runtimes.foo", config.Origin{
+			Kind: config.OriginFlag, Source: "--runtimes",
+		})
+
+		// Then
+		Expect(sut.OptionOrigin("crio.runtime.runtimes.foo.runtime_path").String()).To(Equal("flag:--runtimes"))
+		Expect(sut.OptionOrigin("crio.runtime.runtimes.foo.runtime_root").String()).To(Equal("flag:--runtimes"))
+		Expect(sut.OptionOrigin("crio.runtime.runtimes.runc.runtime_root").Kind).To(Equal(config.OriginDefault))
+	})
+
+	It("should list the options with their value and origin", func() {
+		// Given
+		file := filepath.Join(t.MustTempDir("crio-origin"), "crio.conf")
+		writeConfig(file, "[crio.runtime]\ndefault_capabilities = [\"CHOWN\"]\n")
+		Expect(sut.UpdateFromFile(file)).To(BeNil())
+		sut.SetOptionOrigin("crio.runtime.log_level", config.Origin{
+			Kind: config.OriginEnv, Source: "CONTAINER_LOG_LEVEL",
+		})
+
+		// When
+		options, err := sut.Options()
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(options).NotTo(BeEmpty())
+		for _, option := range options {
+			switch option.Option {
+			case "crio.runtime.default_capabilities":
+				Expect(option.Value).To(Equal(`["CHOWN"]`))
+			case "crio.runtime.log_level":
+				Expect(option.Value).To(Equal(`"info"`))
+			}
+		}
+		origins := optionOrigins()
+		Expect(origins).To(HaveKeyWithValue("crio.runtime.default_capabilities", "file:"+file))
+		Expect(origins).To(HaveKeyWithValue("crio.runtime.log_level", "env:CONTAINER_LOG_LEVEL"))
+		Expect(origins).To(HaveKeyWithValue("crio.runtime.pids_limit", "default"))
+	})
+})
@@ -4,28 +4,90 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"sort"
+	"strings"
 
+	"github.com/BurntSushi/toml"
 	"github.com/container-orchestrated-devices/container-device-interface/pkg/cdi"
 	"github.com/containers/image/v5/pkg/sysregistriesv2"
+	imageTypes "github.com/containers/image/v5/types"
+	"github.com/cri-o/cri-o/internal/config/device"
+	"github.com/cri-o/cri-o/internal/config/ulimits"
 	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/pkg/types"
 	"github.com/sirupsen/logrus"
 )
 
 // Reload reloads the configuration for the single crio.conf and the drop-in
 // configuration directory.
 func (c *Config) Reload() error {
+	_, err := c.ReloadConfig(false)
+	return err
+}
+
+// ReloadConfig reloads the configuration for the single crio.conf and the
+// drop-in configuration directory and returns the effective changes. All
+// reloadable options get validated before any of them is applied, which means
+// that the configuration is either reloaded completely or not at all. The
+// changes are only computed but not applied if dryRun is true.
+func (c *Config) ReloadConfig(dryRun bool) ([]types.ConfigChange, error) {
 	logrus.Infof("Reloading configuration")
 
-	// Reload the config
+	newConfig, err := c.loadReloadedConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := c.prepareReload(newConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []types.ConfigChange{}
+	for _, step := range steps {
+		changes = append(changes, step.changes...)
+	}
+	if dryRun {
+		logrus.Infof("Validated configuration reload with %d changes", len(changes))
+		return changes, nil
+	}
+
+	oldValues, err := c.optionValues()
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make([]*reloadStep, 0, len(steps))
+	for _, step := range steps {
+		if err := step.apply(); err != nil {
+			for i := len(applied) - 1; i >= 0; i-- {
+				if applied[i].rollback != nil {
+					applied[i].rollback()
+				}
+			}
+			return nil, err
+		}
+		applied = append(applied, step)
+	}
+	cdi.GetRegistry(cdi.WithSpecDirs(newConfig.CDISpecDirs...))
+	c.reloadOrigins(newConfig, oldValues)
+
+	return changes, nil
+}
+
+// loadReloadedConfig loads the new configuration from the single crio.conf
+// and the drop-in configuration directory.
+func (c *Config) loadReloadedConfig() (*Config, error) {
 	newConfig, err := DefaultConfig()
 	if err != nil {
-		return fmt.Errorf("unable to create default config")
+		return nil, fmt.Errorf("unable to create default config")
 	}
 
 	if _, err := os.Stat(c.singleConfigPath); !os.IsNotExist(err) {
 		logrus.Infof("Updating config from file %s", c.singleConfigPath)
 		if err := newConfig.UpdateFromFile(c.singleConfigPath); err != nil {
-			return err
+			return nil, err
 		}
 	} else {
 		logrus.Infof("Skipping not-existing config file %q", c.singleConfigPath)
@@ -34,45 +96,89 @@ func (c *Config) Reload() error {
 	if _, err := os.Stat(c.dropInConfigDir); !os.IsNotExist(err) {
 		logrus.Infof("Updating config from path %s", c.dropInConfigDir)
 		if err := newConfig.UpdateFromPath(c.dropInConfigDir); err != nil {
-			return err
+			return nil, err
 		}
 	} else {
 		logrus.Infof("Skipping not-existing config path %q", c.dropInConfigDir)
 	}
 
-	// Reload all available options
-	if err := c.ReloadLogLevel(newConfig); err != nil {
-		return err
-	}
-	if err := c.ReloadLogFilter(newConfig); err != nil {
-		return err
-	}
-	if err := c.ReloadPauseImage(newConfig); err != nil {
-		return err
-	}
-	c.ReloadPinnedImages(newConfig)
-	if err := c.ReloadRegistries(); err != nil {
-		return err
-	}
-	c.ReloadDecryptionKeyConfig(newConfig)
-	if err := c.ReloadSeccompProfile(newConfig); err != nil {
-		return err
-	}
-	if err := c.ReloadAppArmorProfile(newConfig); err != nil {
-		return err
+	return newConfig, nil
+}
+
+// reloadStep is a validated change of one or more reloadable options.
+type reloadStep struct {
+	// changes are the effective option changes of the step.
+	changes []types.ConfigChange
+
+	// apply applies the step. Only steps modifying the system state can
+	// fail, which are therefore applied before all other steps.
+	apply func() error
+
+	// rollback reverts an applied step, if the step modifies the system
+	// state.
+	rollback func()
+}
+
+// prepareReload validates all reloadable options of the new config and
+// returns the steps to apply them. The steps modifying the system state come
+// first.
+func (c *Config) prepareReload(newConfig *Config) ([]*reloadStep, error) {
+	preparers := []func(*Config) (*reloadStep, error){
+		c.prepareBlockIOConfig,
+		c.prepareRdtConfig,
+		func(*Config) (*reloadStep, error) { return c.prepareRegistries() },
+		c.prepareLogLevel,
+		c.prepareLogFilter,
+		c.preparePauseImage,
+		func(newConfig *Config) (*reloadStep, error) { return c.preparePinnedImages(newConfig), nil },
+		func(newConfig *Config) (*reloadStep, error) { return c.prepareDecryptionKeyConfig(newConfig), nil },
+		c.prepareSeccompProfile,
+		c.prepareAppArmorProfile,
+		c.prepareRuntimes,
+		c.prepareWorkloads,
+		c.prepareDefaultCapabilities,
+		c.prepareDefaultSysctls,
+		c.prepareDefaultUlimits,
+		c.prepareDevices,
+		c.prepareDefaultEnv,
+		c.prepareAbsentMountSourcesToReject,
 	}
-	if err := c.ReloadBlockIOConfig(newConfig); err != nil {
-		return err
+
+	steps := []*reloadStep{}
+	for _, prepare := range preparers {
+		step, err := prepare(newConfig)
+		if err != nil {
+			return nil, err
+		}
+		if step != nil {
+			steps = append(steps, step)
+		}
 	}
-	if err := c.ReloadRdtConfig(newConfig); err != nil {
+	return steps, nil
+}
+
+// applyReloadStep applies a prepared step, if there is one.
+func applyReloadStep(step *reloadStep, err error) error {
+	if err != nil || step == nil {
 		return err
 	}
-	if err := c.ReloadRuntimes(newConfig); err != nil {
-		return err
+	return step.apply()
+}
+
+// configChange returns the change of the option from old to new.
+func configChange(option string, old, new any) types.ConfigChange {
+	return types.ConfigChange{
+		Option: option,
+		Old:    formatConfigValue(old),
+		New:    formatConfigValue(new),
 	}
-	cdi.GetRegistry(cdi.WithSpecDirs(newConfig.CDISpecDirs...))
+}
 
-	return nil
+func formatConfigValue(value any) string {
+	if list, ok := value.([]string); ok {
+		return "[" + strings.Join(list, ", ") + "]"
+	}
+	return fmt.Sprint(value)
 }
 
 // logConfig logs a config set operation as with info verbosity. Please always
@@ -85,60 +191,102 @@ func logConfig(option, value string) {
 // ReloadLogLevel updates the LogLevel with the provided `newConfig`. It errors
 // if the level is not parsable.
 func (c *Config) ReloadLogLevel(newConfig *Config) error {
-	if c.LogLevel != newConfig.LogLevel {
-		level, err := logrus.ParseLevel(newConfig.LogLevel)
-		if err != nil {
-			return err
-		}
-		// Always log this message without considering the current
-		logrus.SetLevel(logrus.InfoLevel)
-		logConfig("log_level", newConfig.LogLevel)
+	return applyReloadStep(c.prepareLogLevel(newConfig))
+}
 
-		logrus.SetLevel(level)
-		c.LogLevel = newConfig.LogLevel
+func (c *Config) prepareLogLevel(newConfig *Config) (*reloadStep, error) {
+	if c.LogLevel == newConfig.LogLevel {
+		return nil, nil
 	}
-	return nil
+	level, err := logrus.ParseLevel(newConfig.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+	return &reloadStep{
+		changes: []types.ConfigChange{configChange("log_level", c.LogLevel, newConfig.LogLevel)},
+		apply: func() error {
+			// Always log this message without considering the current
+			logrus.SetLevel(logrus.InfoLevel)
+			logConfig("log_level", newConfig.LogLevel)
+
+			logrus.SetLevel(level)
+			c.LogLevel = newConfig.LogLevel
+			return nil
+		},
+	}, nil
 }
 
 // ReloadLogFilter updates the LogFilter with the provided `newConfig`. It errors
 // if the filter is not applicable.
 func (c *Config) ReloadLogFilter(newConfig *Config) error {
-	if c.LogFilter != newConfig.LogFilter {
-		hook, err := log.NewFilterHook(newConfig.LogFilter)
-		if err != nil {
-			return err
-		}
-		logger := logrus.StandardLogger()
-		log.RemoveHook(logger, "FilterHook")
-		logConfig("log_filter", newConfig.LogFilter)
-		logger.AddHook(hook)
-		c.LogFilter = newConfig.LogFilter
+	return applyReloadStep(c.prepareLogFilter(newConfig))
+}
+
+func (c *Config) prepareLogFilter(newConfig *Config) (*reloadStep, error) {
+	if c.LogFilter == newConfig.LogFilter {
+		return nil, nil
 	}
-	return nil
+	hook, err := log.NewFilterHook(newConfig.LogFilter)
+	if err != nil {
+		return nil, err
+	}
+	return &reloadStep{
+		changes: []types.ConfigChange{configChange("log_filter", c.LogFilter, newConfig.LogFilter)},
+		apply: func() error {
+			logger := logrus.StandardLogger()
+			log.RemoveHook(logger, "FilterHook")
+			logConfig("log_filter", newConfig.LogFilter)
+			logger.AddHook(hook)
+			c.LogFilter = newConfig.LogFilter
+			return nil
+		},
+	}, nil
 }
 
 func (c *Config) ReloadPauseImage(newConfig *Config) error {
+	return applyReloadStep(c.preparePauseImage(newConfig))
+}
+
+func (c *Config) preparePauseImage(newConfig *Config) (*reloadStep, error) {
+	changes := []types.ConfigChange{}
 	if c.PauseImage != newConfig.PauseImage {
 		if _, err := newConfig.ParsePauseImage(); err != nil {
-			return err
+			return nil, err
 		}
-		c.PauseImage = newConfig.PauseImage
-		logConfig("pause_image", c.PauseImage)
+		changes = append(changes, configChange("pause_image", c.PauseImage, newConfig.PauseImage))
 	}
 	if c.PauseImageAuthFile != newConfig.PauseImageAuthFile {
 		if newConfig.PauseImageAuthFile != "" {
 			if _, err := os.Stat(newConfig.PauseImageAuthFile); err != nil {
-				return err
+				return nil, err
 			}
 		}
-		c.PauseImageAuthFile = newConfig.PauseImageAuthFile
-		logConfig("pause_image_auth_file", c.PauseImageAuthFile)
+		changes = append(changes, configChange("pause_image_auth_file", c.PauseImageAuthFile, newConfig.PauseImageAuthFile))
 	}
 	if c.PauseCommand != newConfig.PauseCommand {
-		c.PauseCommand = newConfig.PauseCommand
-		logConfig("pause_command", c.PauseCommand)
+		changes = append(changes, configChange("pause_command", c.PauseCommand, newConfig.PauseCommand))
 	}
-	return nil
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return &reloadStep{
+		changes: changes,
+		apply: func() error {
+			if c.PauseImage != newConfig.PauseImage {
+				c.PauseImage = newConfig.PauseImage
+				logConfig("pause_image", c.PauseImage)
+			}
+			if c.PauseImageAuthFile != newConfig.PauseImageAuthFile {
+				c.PauseImageAuthFile = newConfig.PauseImageAuthFile
+				logConfig("pause_image_auth_file", c.PauseImageAuthFile)
+			}
+			if c.PauseCommand != newConfig.PauseCommand {
+				c.PauseCommand = newConfig.PauseCommand
+				logConfig("pause_command", c.PauseCommand)
+			}
+			return nil
+		},
+	}, nil
 }
 
 // ReloadPinnedImages updates the PinnedImages with the provided `newConfig`.
@@ -155,18 +303,116 @@ func (c *Config) ReloadPinnedImages(newConfig *Config) {
 	c.PinnedImages = updatedPinnedImages
 }
 
+func (c *Config) preparePinnedImages(newConfig *Config) *reloadStep {
+	if reflect.DeepEqual(c.PinnedImages, newConfig.PinnedImages) {
+		return nil
+	}
+	return &reloadStep{
+		changes: []types.ConfigChange{configChange("pinned_images", c.PinnedImages, newConfig.PinnedImages)},
+		apply: func() error {
+			c.ReloadPinnedImages(newConfig)
+			return nil
+		},
+	}
+}
+
 // ReloadRegistries reloads the registry configuration from the Configs
 // `SystemContext`. The method errors in case of any update failure.
 func (c *Config) ReloadRegistries() error {
-	registries, err := sysregistriesv2.TryUpdatingCache(c.SystemContext)
-	if err != nil {
-		return fmt.Errorf(
-			"system registries reload failed: %s: %w",
+	return applyReloadStep(c.prepareRegistries())
+}
+
+// prepareRegistries returns the step for reloading the registry
+// configuration. Only registries.conf can be validated upfront, because the
+// registries are cached globally and the cache can only be updated together
+// with loading the drop-in files. This is why the step has to be applied
+// before all steps which cannot be rolled back.
+func (c *Config) prepareRegistries() (*reloadStep, error) {
+	if err := validateRegistriesConf(c.SystemContext); err != nil {
+		return nil, fmt.Errorf(
+			"system registries validation failed: %s: %w",
 			sysregistriesv2.ConfigPath(c.SystemContext),
 			err,
 		)
 	}
-	logrus.Infof("Applied new registry configuration: %+v", registries)
+	return &reloadStep{
+		apply: func() error {
+			registries, err := sysregistriesv2.TryUpdatingCache(c.SystemContext)
+			if err != nil {
+				return fmt.Errorf(
+					"system registries reload failed: %s: %w",
+					sysregistriesv2.ConfigPath(c.SystemContext),
+					err,
+				)
+			}
+			logrus.Infof("Applied new registry configuration: %+v", registries)
+			return nil
+		},
+	}, nil
+}
+
+// validateRegistriesConf parses registries.conf of the system context like
+// sysregistriesv2 does, but without updating the cached registries.
+func validateRegistriesConf(ctx *imageTypes.SystemContext) error {
+	// Either the v1 or v2 format can be used, but not both at once.
+	var conf struct {
+		sysregistriesv2.V2RegistriesConf
+		sysregistriesv2.V1RegistriesConf
+	}
+	if _, err := toml.DecodeFile(sysregistriesv2.ConfigPath(ctx), &conf); err != nil {
+		// A missing default configuration results in no registries.
+		if os.IsNotExist(err) && (ctx == nil || ctx.SystemRegistriesConfPath == "") {
+			return nil
+		}
+		return err
+	}
+
+	registries := conf.V2RegistriesConf.Registries
+	if conf.V1RegistriesConf.Nonempty() {
+		if conf.V2RegistriesConf.Nonempty() {
+			return errors.New("mixing sysregistry v1/v2 is not supported")
+		}
+		converted, err := conf.V1RegistriesConf.ConvertToV2()
+		if err != nil {
+			return err
+		}
+		registries = converted.Registries
+	}
+
+	for i := range registries {
+		reg := &registries[i]
+		if err := validateRegistryLocation(reg.Location); err != nil {
+			return err
+		}
+		if err := validateRegistryLocation(reg.Prefix); err != nil {
+			return err
+		}
+		if reg.Location == "" {
+			if reg.Prefix == "" {
+				return errors.New("invalid condition: both location and prefix are unset")
+			}
+			if !strings.HasPrefix(reg.Prefix, "*.") {
+				return errors.New("invalid condition: location is unset and prefix is not in the format: *.example.com")
+			}
+		}
+		for _, mirror := range reg.Mirrors {
+			if err := validateRegistryLocation(mirror.Location); err != nil {
+				return err
+			}
+			if strings.TrimRight(mirror.Location, "/") == "" {
+				return errors.New("invalid condition: mirror location is unset")
+			}
+		}
+	}
+	return nil
+}
+
+// validateRegistryLocation returns an error if the location of a registry
+// or mirror contains an URI scheme.
+func validateRegistryLocation(location string) error {
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		return fmt.Errorf("invalid location '%s': URI schemes are not supported", location)
+	}
 	return nil
 }
 
@@ -179,93 +425,458 @@ func (c *Config) ReloadDecryptionKeyConfig(newConfig *Config) {
 	}
 }
 
+func (c *Config) prepareDecryptionKeyConfig(newConfig *Config) *reloadStep {
+	if c.DecryptionKeysPath == newConfig.DecryptionKeysPath {
+		return nil
+	}
+	return &reloadStep{
+		changes: []types.ConfigChange{configChange("decryption_keys_path", c.DecryptionKeysPath, newConfig.DecryptionKeysPath)},
+		apply: func() error {
+			c.ReloadDecryptionKeyConfig(newConfig)
+			return nil
+		},
+	}
+}
+
 // ReloadSeccompProfile reloads the seccomp profile from the new config if
 // their paths differ.
 func (c *Config) ReloadSeccompProfile(newConfig *Config) error {
+	return applyReloadStep(c.prepareSeccompProfile(newConfig))
+}
+
+func (c *Config) prepareSeccompProfile(newConfig *Config) (*reloadStep, error) {
 	// Reload the seccomp profile in any case because its content could have
-	// changed as well
-	if err := c.seccompConfig.LoadProfile(newConfig.SeccompProfile); err != nil {
+	// changed as well. The profile gets loaded into a copy of the seccomp
+	// configuration, which replaces the current one on apply.
+	staged := *c.seccompConfig
+	if err := staged.LoadProfile(newConfig.SeccompProfile); err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
-			return fmt.Errorf("unable to load seccomp profile: %w", err)
+			return nil, fmt.Errorf("unable to load seccomp profile: %w", err)
 		}
 
 		logrus.Info("Specified profile does not exist on disk")
-		if err := c.seccompConfig.LoadDefaultProfile(); err != nil {
-			return fmt.Errorf("load default seccomp profile: %w", err)
+		if err := staged.LoadDefaultProfile(); err != nil {
+			return nil, fmt.Errorf("load default seccomp profile: %w", err)
 		}
 	}
 
-	c.SeccompProfile = newConfig.SeccompProfile
-	logConfig("seccomp_profile", c.SeccompProfile)
-	return nil
+	step := &reloadStep{
+		apply: func() error {
+			*c.seccompConfig = staged
+			c.SeccompProfile = newConfig.SeccompProfile
+			logConfig("seccomp_profile", c.SeccompProfile)
+			return nil
+		},
+	}
+	if c.SeccompProfile != newConfig.SeccompProfile {
+		step.changes = []types.ConfigChange{configChange("seccomp_profile", c.SeccompProfile, newConfig.SeccompProfile)}
+	}
+	return step, nil
 }
 
 // ReloadAppArmorProfile reloads the AppArmor profile from the new config if
 // they differ.
 func (c *Config) ReloadAppArmorProfile(newConfig *Config) error {
-	if c.ApparmorProfile != newConfig.ApparmorProfile {
-		if err := c.AppArmor().LoadProfile(newConfig.ApparmorProfile); err != nil {
-			return fmt.Errorf("unable to reload apparmor_profile: %w", err)
-		}
-		c.ApparmorProfile = newConfig.ApparmorProfile
-		logConfig("apparmor_profile", c.ApparmorProfile)
+	return applyReloadStep(c.prepareAppArmorProfile(newConfig))
+}
+
+func (c *Config) prepareAppArmorProfile(newConfig *Config) (*reloadStep, error) {
+	if c.ApparmorProfile == newConfig.ApparmorProfile {
+		return nil, nil
 	}
-	return nil
+	staged := *c.AppArmor()
+	if err := staged.LoadProfile(newConfig.ApparmorProfile); err != nil {
+		return nil, fmt.Errorf("unable to reload apparmor_profile: %w", err)
+	}
+	return &reloadStep{
+		changes: []types.ConfigChange{configChange("apparmor_profile", c.ApparmorProfile, newConfig.ApparmorProfile)},
+		apply: func() error {
+			*c.AppArmor() = staged
+			c.ApparmorProfile = newConfig.ApparmorProfile
+			logConfig("apparmor_profile", c.ApparmorProfile)
+			return nil
+		},
+	}, nil
 }
 
 // ReloadBlockIOConfig reloads the blockio configuration from the new config
 func (c *Config) ReloadBlockIOConfig(newConfig *Config) error {
+	return applyReloadStep(c.prepareBlockIOConfig(newConfig))
+}
+
+// prepareBlockIOConfig returns the step for reloading the blockio
+// configuration. Loading the configuration modifies the system state, which
+// is why it happens on apply and gets rolled back by loading the previous
+// configuration again.
+func (c *Config) prepareBlockIOConfig(newConfig *Config) (*reloadStep, error) {
+	changes := []types.ConfigChange{}
 	if c.BlockIOConfigFile != newConfig.BlockIOConfigFile {
-		if err := c.BlockIO().Load(newConfig.BlockIOConfigFile); err != nil {
-			return fmt.Errorf("unable to reload blockio_config_file: %w", err)
-		}
-		c.BlockIOConfigFile = newConfig.BlockIOConfigFile
-		logConfig("blockio_config_file", c.BlockIOConfigFile)
+		changes = append(changes, configChange("blockio_config_file", c.BlockIOConfigFile, newConfig.BlockIOConfigFile))
 	}
 	if c.BlockIOReload != newConfig.BlockIOReload {
-		c.BlockIOReload = newConfig.BlockIOReload
-		logConfig("blockio_reload", fmt.Sprintf("%v", c.BlockIOReload))
+		changes = append(changes, configChange("blockio_reload", c.BlockIOReload, newConfig.BlockIOReload))
 	}
-	return nil
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	oldFile, oldReload := c.BlockIOConfigFile, c.BlockIOReload
+	return &reloadStep{
+		changes: changes,
+		apply: func() error {
+			if c.BlockIOConfigFile != newConfig.BlockIOConfigFile {
+				previous := *c.BlockIO()
+				if err := c.BlockIO().Load(newConfig.BlockIOConfigFile); err != nil {
+					*c.BlockIO() = previous
+					return fmt.Errorf("unable to reload blockio_config_file: %w", err)
+				}
+				c.BlockIOConfigFile = newConfig.BlockIOConfigFile
+				logConfig("blockio_config_file", c.BlockIOConfigFile)
+			}
+			if c.BlockIOReload != newConfig.BlockIOReload {
+				c.BlockIOReload = newConfig.BlockIOReload
+				logConfig("blockio_reload", fmt.Sprintf("%v", c.BlockIOReload))
+			}
+			return nil
+		},
+		rollback: func() {
+			if c.BlockIOConfigFile != oldFile {
+				if err := c.BlockIO().Load(oldFile); err != nil {
+					logrus.Errorf("Unable to restore blockio_config_file %q: %v", oldFile, err)
+				}
+				c.BlockIOConfigFile = oldFile
+			}
+			c.BlockIOReload = oldReload
+		},
+	}, nil
 }
 
 // ReloadRdtConfig reloads the RDT configuration if changed
 func (c *Config) ReloadRdtConfig(newConfig *Config) error {
-	if c.RdtConfigFile != newConfig.RdtConfigFile {
-		if err := c.Rdt().Load(newConfig.RdtConfigFile); err != nil {
-			return fmt.Errorf("unable to reload rdt_config_file: %w", err)
-		}
-		c.RdtConfigFile = newConfig.RdtConfigFile
-		logConfig("rdt_config_file", c.RdtConfigFile)
+	return applyReloadStep(c.prepareRdtConfig(newConfig))
+}
+
+// prepareRdtConfig returns the step for reloading the RDT configuration,
+// which modifies the system state like the blockio configuration.
+func (c *Config) prepareRdtConfig(newConfig *Config) (*reloadStep, error) {
+	if c.RdtConfigFile == newConfig.RdtConfigFile {
+		return nil, nil
 	}
-	return nil
+
+	oldFile := c.RdtConfigFile
+	return &reloadStep{
+		changes: []types.ConfigChange{configChange("rdt_config_file", c.RdtConfigFile, newConfig.RdtConfigFile)},
+		apply: func() error {
+			previous := *c.Rdt()
+			if err := c.Rdt().Load(newConfig.RdtConfigFile); err != nil {
+				*c.Rdt() = previous
+				return fmt.Errorf("unable to reload rdt_config_file: %w", err)
+			}
+			c.RdtConfigFile = newConfig.RdtConfigFile
+			logConfig("rdt_config_file", c.RdtConfigFile)
+			return nil
+		},
+		rollback: func() {
+			if err := c.Rdt().Load(oldFile); err != nil {
+				logrus.Errorf("Unable to restore rdt_config_file %q: %v", oldFile, err)
+			}
+			c.RdtConfigFile = oldFile
+		},
+	}, nil
 }
 
 // ReloadRuntimes reloads the runtimes configuration if changed
 func (c *Config) ReloadRuntimes(newConfig *Config) error {
-	var updated bool
-	if !RuntimesEqual(c.Runtimes, newConfig.Runtimes) {
-		logrus.Infof("Updating runtime configuration")
-		c.Runtimes = newConfig.Runtimes
-		updated = true
+	return applyReloadStep(c.prepareRuntimes(newConfig))
+}
+
+// prepareRuntimes validates the new runtimes like on startup, by using a copy
+// of the runtime configuration.
+func (c *Config) prepareRuntimes(newConfig *Config) (*reloadStep, error) {
+	if RuntimesEqual(c.Runtimes, newConfig.Runtimes) && c.DefaultRuntime == newConfig.DefaultRuntime {
+		return nil, nil
+	}
+
+	staged := c.RuntimeConfig
+	staged.Runtimes = make(Runtimes, len(newConfig.Runtimes))
+	for name, handler := range newConfig.Runtimes {
+		staged.Runtimes[name] = handler
 	}
+	staged.DefaultRuntime = newConfig.DefaultRuntime
 
-	if c.DefaultRuntime != newConfig.DefaultRuntime {
-		c.DefaultRuntime = newConfig.DefaultRuntime
-		if err := c.ValidateDefaultRuntime(); err != nil {
-			return fmt.Errorf("unable to reload runtimes: %w", err)
+	if c.DefaultRuntime != staged.DefaultRuntime {
+		if err := staged.ValidateDefaultRuntime(); err != nil {
+			return nil, fmt.Errorf("unable to reload runtimes: %w", err)
 		}
-		logConfig("default_runtime", c.DefaultRuntime)
-		updated = true
+	}
+	if err := staged.ValidateRuntimes(); err != nil {
+		return nil, fmt.Errorf("unabled to reload runtimes: %w", err)
 	}
 
-	if !updated {
-		return nil
+	changes := []types.ConfigChange{}
+	if c.DefaultRuntime != staged.DefaultRuntime {
+		changes = append(changes, configChange("default_runtime", c.DefaultRuntime, staged.DefaultRuntime))
 	}
+	changes = append(changes, runtimesChanges(c.Runtimes, staged.Runtimes)...)
 
-	if err := c.ValidateRuntimes(); err != nil {
-		return fmt.Errorf("unabled to reload runtimes: %w", err)
+	return &reloadStep{
+		changes: changes,
+		apply: func() error {
+			if !RuntimesEqual(c.Runtimes, staged.Runtimes) {
+				logrus.Infof("Updating runtime configuration")
+				c.Runtimes = staged.Runtimes
+			}
+			if c.DefaultRuntime != staged.DefaultRuntime {
+				c.DefaultRuntime = staged.DefaultRuntime
+				logConfig("default_runtime", c.DefaultRuntime)
+			}
+			return nil
+		},
+	}, nil
+}
+
+// runtimesChanges returns the changes of the runtime handlers, where the
+// runtime path represents the value of each handler.
+func runtimesChanges(old, new Runtimes) []types.ConfigChange {
+	names := []string{}
+	for name := range old {
+		names = append(names, name)
 	}
+	for name := range new {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
 
-	return nil
+	changes := []types.ConfigChange{}
+	for _, name := range names {
+		oldHandler, newHandler := old[name], new[name]
+		if reflect.DeepEqual(oldHandler, newHandler) {
+			continue
+		}
+		change := types.ConfigChange{Option: "runtimes." + name}
+		if oldHandler != nil {
+			change.Old = oldHandler.RuntimePath
+		}
+		if newHandler != nil {
+			change.New = newHandler.RuntimePath
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// ReloadWorkloads updates the Workloads with the provided `newConfig`. It
+// errors if any of the new workloads is invalid.
+func (c *Config) ReloadWorkloads(newConfig *Config) error {
+	return applyReloadStep(c.prepareWorkloads(newConfig))
+}
+
+func (c *Config) prepareWorkloads(newConfig *Config) (*reloadStep, error) {
+	// Validating the workloads populates their disallowed annotations, which
+	// is required before comparing them to the current ones.
+	if err := newConfig.Workloads.Validate(); err != nil {
+		return nil, fmt.Errorf("unable to reload workloads: %w", err)
+	}
+	if WorkloadsEqual(c.Workloads, newConfig.Workloads) {
+		return nil, nil
+	}
+	return &reloadStep{
+		changes: workloadsChanges(c.Workloads, newConfig.Workloads),
+		apply: func() error {
+			logrus.Infof("Updating workloads configuration")
+			c.Workloads = newConfig.Workloads
+			return nil
+		},
+	}, nil
+}
+
+// workloadsChanges returns the changes of the workloads, where the activation
+// annotation represents the value of each workload.
+func workloadsChanges(old, new Workloads) []types.ConfigChange {
+	names := []string{}
+	for name := range old {
+		names = append(names, name)
+	}
+	for name := range new {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []types.ConfigChange{}
+	for _, name := range names {
+		oldWorkload, newWorkload := old[name], new[name]
+		if reflect.DeepEqual(oldWorkload, newWorkload) {
+			continue
+		}
+		change := types.ConfigChange{Option: "workloads." + name}
+		if oldWorkload != nil {
+			change.Old = oldWorkload.ActivationAnnotation
+		}
+		if newWorkload != nil {
+			change.New = newWorkload.ActivationAnnotation
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// ReloadDefaultCapabilities updates the DefaultCapabilities with the provided
+// `newConfig`. It errors if any of the capabilities is not available.
+func (c *Config) ReloadDefaultCapabilities(newConfig *Config) error {
+	return applyReloadStep(c.prepareDefaultCapabilities(newConfig))
+}
+
+func (c *Config) prepareDefaultCapabilities(newConfig *Config) (*reloadStep, error) {
+	if stringSliceEqual(c.DefaultCapabilities, newConfig.DefaultCapabilities) {
+		return nil, nil
+	}
+	if err := newConfig.DefaultCapabilities.Validate(); err != nil {
+		return nil, fmt.Errorf("unable to reload default_capabilities: %w", err)
+	}
+	return &reloadStep{
+		changes: []types.ConfigChange{configChange(
+			"default_capabilities", []string(c.DefaultCapabilities), []string(newConfig.DefaultCapabilities),
+		)},
+		apply: func() error {
+			c.DefaultCapabilities = newConfig.DefaultCapabilities
+			logConfig("default_capabilities", strings.Join(c.DefaultCapabilities, ","))
+			return nil
+		},
+	}, nil
+}
+
+// ReloadDefaultSysctls updates the DefaultSysctls with the provided
+// `newConfig`. It errors if any of the sysctls is not parsable.
+func (c *Config) ReloadDefaultSysctls(newConfig *Config) error {
+	return applyReloadStep(c.prepareDefaultSysctls(newConfig))
+}
+
+func (c *Config) prepareDefaultSysctls(newConfig *Config) (*reloadStep, error) {
+	if stringSliceEqual(c.DefaultSysctls, newConfig.DefaultSysctls) {
+		return nil, nil
+	}
+	if _, err := newConfig.Sysctls(); err != nil {
+		return nil, fmt.Errorf("unable to reload default_sysctls: %w", err)
+	}
+	return &reloadStep{
+		changes: []types.ConfigChange{configChange("default_sysctls", c.DefaultSysctls, newConfig.DefaultSysctls)},
+		apply: func() error {
+			c.DefaultSysctls = newConfig.DefaultSysctls
+			logConfig("default_sysctls", strings.Join(c.DefaultSysctls, ","))
+			return nil
+		},
+	}, nil
+}
+
+// ReloadDefaultUlimits updates the DefaultUlimits with the provided
+// `newConfig`. It errors if any of the ulimits is not parsable.
+func (c *Config) ReloadDefaultUlimits(newConfig *Config) error {
+	return applyReloadStep(c.prepareDefaultUlimits(newConfig))
+}
+
+func (c *Config) prepareDefaultUlimits(newConfig *Config) (*reloadStep, error) {
+	if stringSliceEqual(c.DefaultUlimits, newConfig.DefaultUlimits) {
+		return nil, nil
+	}
+	// The ulimits get loaded into a new configuration, which replaces the
+	// content of the current one on apply.
+	staged := ulimits.New()
+	if err := staged.LoadUlimits(newConfig.DefaultUlimits); err != nil {
+		return nil, fmt.Errorf("unable to reload default_ulimits: %w", err)
+	}
+	return &reloadStep{
+		changes: []types.ConfigChange{configChange("default_ulimits", c.DefaultUlimits, newConfig.DefaultUlimits)},
+		apply: func() error {
+			*c.ulimitsConfig = *staged
+			c.DefaultUlimits = newConfig.DefaultUlimits
+			logConfig("default_ulimits", strings.Join(c.DefaultUlimits, ","))
+			return nil
+		},
+	}, nil
+}
+
+// ReloadDevices updates the AdditionalDevices and AllowedDevices with the
+// provided `newConfig`. It errors if any of the additional devices is invalid.
+func (c *Config) ReloadDevices(newConfig *Config) error {
+	return applyReloadStep(c.prepareDevices(newConfig))
+}
+
+func (c *Config) prepareDevices(newConfig *Config) (*reloadStep, error) {
+	changes := []types.ConfigChange{}
+	// The devices get loaded into a new configuration, which replaces the
+	// content of the current one on apply.
+	var staged *device.Config
+	if !stringSliceEqual(c.AdditionalDevices, newConfig.AdditionalDevices) {
+		staged = device.New()
+		if err := staged.LoadDevices(newConfig.AdditionalDevices); err != nil {
+			return nil, fmt.Errorf("unable to reload additional_devices: %w", err)
+		}
+		changes = append(changes, configChange("additional_devices", c.AdditionalDevices, newConfig.AdditionalDevices))
+	}
+	if !stringSliceEqual(c.AllowedDevices, newConfig.AllowedDevices) {
+		changes = append(changes, configChange("allowed_devices", c.AllowedDevices, newConfig.AllowedDevices))
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return &reloadStep{
+		changes: changes,
+		apply: func() error {
+			if staged != nil {
+				*c.deviceConfig = *staged
+				c.AdditionalDevices = newConfig.AdditionalDevices
+				logConfig("additional_devices", strings.Join(c.AdditionalDevices, ","))
+			}
+			if !stringSliceEqual(c.AllowedDevices, newConfig.AllowedDevices) {
+				c.AllowedDevices = newConfig.AllowedDevices
+				logConfig("allowed_devices", strings.Join(c.AllowedDevices, ","))
+			}
+			return nil
+		},
+	}, nil
+}
+
+// ReloadDefaultEnv updates the DefaultEnv with the provided `newConfig`.
+func (c *Config) ReloadDefaultEnv(newConfig *Config) error {
+	return applyReloadStep(c.prepareDefaultEnv(newConfig))
+}
+
+func (c *Config) prepareDefaultEnv(newConfig *Config) (*reloadStep, error) {
+	if stringSliceEqual(c.DefaultEnv, newConfig.DefaultEnv) {
+		return nil, nil
+	}
+	return &reloadStep{
+		changes: []types.ConfigChange{configChange("default_env", c.DefaultEnv, newConfig.DefaultEnv)},
+		apply: func() error {
+			c.DefaultEnv = newConfig.DefaultEnv
+			logConfig("default_env", strings.Join(c.DefaultEnv, ","))
+			return nil
+		},
+	}, nil
+}
+
+// ReloadAbsentMountSourcesToReject updates the AbsentMountSourcesToReject with
+// the provided `newConfig`.
+func (c *Config) ReloadAbsentMountSourcesToReject(newConfig *Config) error {
+	return applyReloadStep(c.prepareAbsentMountSourcesToReject(newConfig))
+}
+
+func (c *Config) prepareAbsentMountSourcesToReject(newConfig *Config) (*reloadStep, error) {
+	if stringSliceEqual(c.AbsentMountSourcesToReject, newConfig.AbsentMountSourcesToReject) {
+		return nil, nil
+	}
+	return &reloadStep{
+		changes: []types.ConfigChange{configChange(
+			"absent_mount_sources_to_reject", c.AbsentMountSourcesToReject, newConfig.AbsentMountSourcesToReject,
+		)},
+		apply: func() error {
+			c.AbsentMountSourcesToReject = newConfig.AbsentMountSourcesToReject
+			logConfig("absent_mount_sources_to_reject", strings.Join(c.AbsentMountSourcesToReject, ","))
+			return nil
+		},
+	}, nil
 }
@@ -82,6 +82,138 @@ var _ = t.Describe("Config", func() {
 			// Then
 			Expect(err).To(BeNil())
 		})
+
+		It("should return the changes without applying them on dry run", func() {
+			// Given
+			modifyDefaultConfig(
+				`log_level = "info"`,
+				`log_level = "debug"`,
+			)
+
+			// When
+			changes, err := sut.ReloadConfig(true)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(changes).To(HaveLen(1))
+			Expect(changes[0].Option).To(Equal("log_level"))
+			Expect(changes[0].Old).To(Equal("info"))
+			Expect(changes[0].New).To(Equal("debug"))
+			Expect(sut.LogLevel).To(Equal("info"))
+		})
+
+		It("should fail on dry run if registries file is invalid", func() {
+			// Given
+			filePath := t.MustTempFile("config")
+			Expect(sut.ToFile(filePath)).To(BeNil())
+			Expect(sut.UpdateFromFile(filePath)).To(BeNil())
+			regConf := t.MustTempFile("reload-registries")
+			Expect(os.WriteFile(regConf, []byte("invalid"), 0o755)).To(BeNil())
+			sut.SystemContext.SystemRegistriesConfPath = regConf
+
+			// When
+			changes, err := sut.ReloadConfig(true)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(changes).To(BeEmpty())
+		})
+
+		It("should apply the changes", func() {
+			// Given
+			modifyDefaultConfig(
+				`log_level = "info"`,
+				`log_level = "debug"`,
+			)
+
+			// When
+			changes, err := sut.ReloadConfig(false)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(changes).To(HaveLen(1))
+			Expect(sut.LogLevel).To(Equal("debug"))
+		})
+
+		It("should update the origins of the options", func() {
+			// Given
+			filePath := t.MustTempFile("config")
+			Expect(sut.ToFile(filePath)).To(BeNil())
+			Expect(sut.UpdateFromFile(filePath)).To(BeNil())
+			sut.SetOptionOrigin("crio.runtime.log_level", config.Origin{
+				Kind: config.OriginDropIn, Source: "/etc/crio/crio.conf.d/removed.conf",
+			})
+			sut.SetOptionOrigin("crio.runtime.log_filter", config.Origin{
+				Kind: config.OriginFlag, Source: "--log-filter",
+			})
+
+			// When
+			_, err := sut.ReloadConfig(false)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.OptionOrigin("crio.runtime.log_level").String()).To(Equal("file:" + filePath))
+			Expect(sut.OptionOrigin("crio.runtime.log_filter").String()).To(Equal("flag:--log-filter"))
+		})
+
+		It("should drop the origins of flags overridden by the reload", func() {
+			// Given
+			filePath := t.MustTempFile("config")
+			Expect(sut.ToFile(filePath)).To(BeNil())
+			Expect(sut.UpdateFromFile(filePath)).To(BeNil())
+			sut.LogLevel = "debug"
+			sut.SetOptionOrigin("crio.runtime.log_level", config.Origin{
+				Kind: config.OriginFlag, Source: "--log-level",
+			})
+
+			// When
+			_, err := sut.ReloadConfig(false)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.LogLevel).To(Equal("info"))
+			Expect(sut.OptionOrigin("crio.runtime.log_level").String()).To(Equal("file:" + filePath))
+		})
+
+		It("should not update the origins on dry run", func() {
+			// Given
+			filePath := t.MustTempFile("config")
+			Expect(sut.ToFile(filePath)).To(BeNil())
+			Expect(sut.UpdateFromFile(filePath)).To(BeNil())
+			sut.SetOptionOrigin("crio.runtime.log_level", config.Origin{
+				Kind: config.OriginDropIn, Source: "/etc/crio/crio.conf.d/removed.conf",
+			})
+
+			// When
+			_, err := sut.ReloadConfig(true)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.OptionOrigin("crio.runtime.log_level").Kind).To(Equal(config.OriginDropIn))
+		})
+
+		It("should not apply any change if a later option is invalid", func() {
+			// Given
+			filePath := t.MustTempFile("config")
+			Expect(sut.ToFile(filePath)).To(BeNil())
+			Expect(sut.UpdateFromFile(filePath)).To(BeNil())
+
+			read, err := os.ReadFile(filePath)
+			Expect(err).To(BeNil())
+			newContents := strings.NewReplacer(
+				`log_level = "info"`, `log_level = "debug"`,
+				`pause_image_auth_file = ""`, `pause_image_auth_file = "`+invalidPath+`"`,
+			).Replace(string(read))
+			Expect(os.WriteFile(filePath, []byte(newContents), 0)).To(BeNil())
+
+			// When
+			changes, err := sut.ReloadConfig(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(changes).To(BeEmpty())
+			Expect(sut.LogLevel).To(Equal("info"))
+		})
 	})
 
 	t.Describe("ReloadLogLevel", func() {
@@ -268,6 +400,62 @@ var _ = t.Describe("Config", func() {
 			// Then
 			Expect(err).NotTo(BeNil())
 		})
+
+		It("should succeed with valid registries file", func() {
+			// Given
+			regConf := t.MustTempFile("reload-registries")
+			Expect(os.WriteFile(regConf, []byte(`
+unqualified-search-registries = ["quay.io"]
+
+[[registry]]
+location = "quay.io"
+
+[[registry.mirror]]
+location = "mirror.example.com"
+`), 0o755)).To(BeNil())
+			sut.SystemContext.SystemRegistriesConfPath = regConf
+
+			// When
+			err := sut.ReloadRegistries()
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail if registry location contains a scheme", func() {
+			// Given
+			regConf := t.MustTempFile("reload-registries")
+			Expect(os.WriteFile(regConf, []byte(`
+[[registry]]
+location = "https://quay.io"
+`), 0o755)).To(BeNil())
+			sut.SystemContext.SystemRegistriesConfPath = regConf
+
+			// When
+			err := sut.ReloadRegistries()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail if mirror location is unset", func() {
+			// Given
+			regConf := t.MustTempFile("reload-registries")
+			Expect(os.WriteFile(regConf, []byte(`
+[[registry]]
+location = "quay.io"
+
+[[registry.mirror]]
+insecure = true
+`), 0o755)).To(BeNil())
+			sut.SystemContext.SystemRegistriesConfPath = regConf
+
+			// When
+			err := sut.ReloadRegistries()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
 	})
 
 	t.Describe("ReloadSeccompProfile", func() {
@@ -439,4 +627,204 @@ var _ = t.Describe("Config", func() {
 			Expect(sut.PinnedImages).To(Equal([]string{"image1", "image2", "image3"}))
 		})
 	})
+
+	t.Describe("ReloadWorkloads", func() {
+		It("should succeed without any config change", func() {
+			// Given
+			// When
+			err := sut.ReloadWorkloads(sut)
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should succeed with config change", func() {
+			// Given
+			newConfig := defaultConfig()
+			newConfig.Workloads = config.Workloads{
+				"management": &config.WorkloadConfig{
+					ActivationAnnotation: "io.crio/management",
+					AnnotationPrefix:     "io.crio.management",
+				},
+			}
+
+			// When
+			err := sut.ReloadWorkloads(newConfig)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.Workloads).To(HaveKey("management"))
+			Expect(sut.Workloads["management"].DisallowedAnnotations).NotTo(BeEmpty())
+		})
+
+		It("should fail with empty activation_annotation", func() {
+			// Given
+			newConfig := defaultConfig()
+			newConfig.Workloads = config.Workloads{
+				"management": &config.WorkloadConfig{},
+			}
+
+			// When
+			err := sut.ReloadWorkloads(newConfig)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(sut.Workloads).To(BeEmpty())
+		})
+	})
+
+	t.Describe("ReloadDefaultCapabilities", func() {
+		It("should succeed without any config change", func() {
+			// Given
+			// When
+			err := sut.ReloadDefaultCapabilities(sut)
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should succeed with config change", func() {
+			// Given
+			newConfig := defaultConfig()
+			newConfig.DefaultCapabilities = []string{"CHOWN", "NET_RAW"}
+
+			// When
+			err := sut.ReloadDefaultCapabilities(newConfig)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect([]string(sut.DefaultCapabilities)).To(Equal([]string{"CHOWN", "NET_RAW"}))
+		})
+
+		It("should fail with invalid capability", func() {
+			// Given
+			newConfig := defaultConfig()
+			newConfig.DefaultCapabilities = []string{invalid}
+
+			// When
+			err := sut.ReloadDefaultCapabilities(newConfig)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect([]string(sut.DefaultCapabilities)).NotTo(ContainElement(invalid))
+		})
+	})
+
+	t.Describe("ReloadDefaultSysctls", func() {
+		It("should succeed with config change", func() {
+			// Given
+			newConfig := defaultConfig()
+			newConfig.DefaultSysctls = []string{"net.ipv4.ping_group_range=0 2147483647"}
+
+			// When
+			err := sut.ReloadDefaultSysctls(newConfig)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.DefaultSysctls).To(Equal(newConfig.DefaultSysctls))
+		})
+
+		It("should fail with invalid sysctl", func() {
+			// Given
+			newConfig := defaultConfig()
+			newConfig.DefaultSysctls = []string{invalid}
+
+			// When
+			err := sut.ReloadDefaultSysctls(newConfig)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(sut.DefaultSysctls).To(BeEmpty())
+		})
+	})
+
+	t.Describe("ReloadDefaultUlimits", func() {
+		It("should succeed with config change", func() {
+			// Given
+			newConfig := defaultConfig()
+			newConfig.DefaultUlimits = []string{"nofile=1024:2048"}
+
+			// When
+			err := sut.ReloadDefaultUlimits(newConfig)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.DefaultUlimits).To(Equal(newConfig.DefaultUlimits))
+			Expect(sut.Ulimits()).To(HaveLen(1))
+		})
+
+		It("should fail with invalid ulimit", func() {
+			// Given
+			newConfig := defaultConfig()
+			newConfig.DefaultUlimits = []string{invalid}
+
+			// When
+			err := sut.ReloadDefaultUlimits(newConfig)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(sut.DefaultUlimits).To(BeEmpty())
+			Expect(sut.Ulimits()).To(BeEmpty())
+		})
+	})
+
+	t.Describe("ReloadDevices", func() {
+		It("should succeed with allowed_devices change", func() {
+			// Given
+			newConfig := defaultConfig()
+			newConfig.AllowedDevices = []string{"/dev/fuse", "/dev/net/tun"}
+
+			// When
+			err := sut.ReloadDevices(newConfig)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.AllowedDevices).To(Equal(newConfig.AllowedDevices))
+		})
+
+		It("should fail with invalid additional_devices", func() {
+			// Given
+			newConfig := defaultConfig()
+			newConfig.AdditionalDevices = []string{invalid}
+			newConfig.AllowedDevices = []string{"/dev/net/tun"}
+
+			// When
+			err := sut.ReloadDevices(newConfig)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(sut.AdditionalDevices).To(BeEmpty())
+			Expect(sut.AllowedDevices).NotTo(Equal(newConfig.AllowedDevices))
+		})
+	})
+
+	t.Describe("ReloadDefaultEnv", func() {
+		It("should succeed with config change", func() {
+			// Given
+			newConfig := defaultConfig()
+			newConfig.DefaultEnv = []string{"FOO=bar"}
+
+			// When
+			err := sut.ReloadDefaultEnv(newConfig)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.DefaultEnv).To(Equal([]string{"FOO=bar"}))
+		})
+	})
+
+	t.Describe("ReloadAbsentMountSourcesToReject", func() {
+		It("should succeed with config change", func() {
+			// Given
+			newConfig := defaultConfig()
+			newConfig.AbsentMountSourcesToReject = []string{"/etc/hostname"}
+
+			// When
+			err := sut.ReloadAbsentMountSourcesToReject(newConfig)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.AbsentMountSourcesToReject).To(Equal([]string{"/etc/hostname"}))
+		})
+	})
 })
@@ -255,6 +255,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.SeccompProfile, c.SeccompProfile),
 		},
+		{
+			templateString: templateStringCrioRuntimeSeccompProfileRecordDir,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.SeccompProfileRecordDir, c.SeccompProfileRecordDir),
+		},
 		{
 			templateString: templateStringCrioRuntimeSeccompUseDefaultWhenEmpty,
 			group:          crioRuntimeConfig,
@@ -445,6 +450,41 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.EnablePodEvents, c.EnablePodEvents),
 		},
+		{
+			templateString: templateStringCrioRuntimeContainerEventsJournalSize,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.ContainerEventsJournalSize, c.ContainerEventsJournalSize),
+		},
+		{
+			templateString: templateStringCrioRuntimeAuditLog,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.AuditLog, c.AuditLog),
+		},
+		{
+			templateString: templateStringCrioRuntimeAuditLogJournaldIdentifier,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.AuditLogJournaldIdentifier, c.AuditLogJournaldIdentifier),
+		},
+		{
+			templateString: templateStringCrioRuntimeAuditLogMaxSize,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.AuditLogMaxSize, c.AuditLogMaxSize),
+		},
+		{
+			templateString: templateStringCrioRuntimeAuditLogMaxFiles,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.AuditLogMaxFiles, c.AuditLogMaxFiles),
+		},
+		{
+			templateString: templateStringCrioRuntimeAuditLogOperations,
+			group:          crioRuntimeConfig,
+			isDefaultValue: stringSliceEqual(dc.AuditLogOperations, c.AuditLogOperations),
+		},
+		{
+			templateString: templateStringCrioRuntimeAuditLogNamespaces,
+			group:          crioRuntimeConfig,
+			isDefaultValue: stringSliceEqual(dc.AuditLogNamespaces, c.AuditLogNamespaces),
+		},
 		{
 			templateString: templateStringCrioRuntimeDefaultRuntime,
 			group:          crioRuntimeConfig,
@@ -530,6 +570,56 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioImageConfig,
 			isDefaultValue: simpleEqual(dc.BigFilesTemporaryDir, c.BigFilesTemporaryDir),
 		},
+		{
+			templateString: templateStringCrioImageImageGCHighThresholdPercent,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.ImageGCHighThresholdPercent, c.ImageGCHighThresholdPercent),
+		},
+		{
+			templateString: templateStringCrioImageImageGCLowThresholdPercent,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.ImageGCLowThresholdPercent, c.ImageGCLowThresholdPercent),
+		},
+		{
+			templateString: templateStringCrioImageImageGCMinAge,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.ImageGCMinAge, c.ImageGCMinAge),
+		},
+		{
+			templateString: templateStringCrioImageImageGCMaxAge,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.ImageGCMaxAge, c.ImageGCMaxAge),
+		},
+		{
+			templateString: templateStringCrioImageImageGCInterval,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.ImageGCInterval, c.ImageGCInterval),
+		},
+		{
+			templateString: templateStringCrioImageMaxConcurrentPulls,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.MaxConcurrentPulls, c.MaxConcurrentPulls),
+		},
+		{
+			templateString: templateStringCrioImageMaxConcurrentPullsPerRegistry,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.MaxConcurrentPullsPerRegistry, c.MaxConcurrentPullsPerRegistry),
+		},
+		{
+			templateString: templateStringCrioImageMaxConcurrentPullsPerNamespace,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.MaxConcurrentPullsPerNamespace, c.MaxConcurrentPullsPerNamespace),
+		},
+		{
+			templateString: templateStringCrioImageRegistryFailureThreshold,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.RegistryFailureThreshold, c.RegistryFailureThreshold),
+		},
+		{
+			templateString: templateStringCrioImageRegistryCircuitBreakerCooldown,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.RegistryCircuitBreakerCooldown, c.RegistryCircuitBreakerCooldown),
+		},
 		{
 			templateString: templateStringCrioNetworkCniDefaultNetwork,
 			group:          crioNetworkConfig,
@@ -693,7 +783,7 @@ func WorkloadsEqual(a, b Workloads) bool {
 		if !ok {
 			return false
 		}
-		if !reflect.DeepEqual(valueA, valueB) {
+		if !workloadConfigsEqual(valueA, valueB) {
 			return false
 		}
 	}
@@ -701,6 +791,42 @@ func WorkloadsEqual(a, b Workloads) bool {
 	return true
 }
 
+func workloadConfigsEqual(a, b *WorkloadConfig) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.ActivationAnnotation == b.ActivationAnnotation &&
+		a.AnnotationPrefix == b.AnnotationPrefix &&
+		stringSliceEqual(a.AllowedAnnotations, b.AllowedAnnotations) &&
+		stringSliceEqual(a.DisallowedAnnotations, b.DisallowedAnnotations) &&
+		resourcesEqual(a.Resources, b.Resources)
+}
+
+// resourcesEqual compares the workload resources, where unset resources are
+// equal to empty ones.
+func resourcesEqual(a, b *Resources) bool {
+	if a == nil {
+		a = &Resources{}
+	}
+	if b == nil {
+		b = &Resources{}
+	}
+	if len(a.HugepageLimits) != len(b.HugepageLimits) {
+		return false
+	}
+	for pageSize, limitA := range a.HugepageLimits {
+		limitB, ok := b.HugepageLimits[pageSize]
+		if !ok || limitA != limitB {
+			return false
+		}
+	}
+	return a.CPUShares == b.CPUShares &&
+		a.CPUSet == b.CPUSet &&
+		a.MemoryLimit == b.MemoryLimit &&
+		a.PidsLimit == b.PidsLimit &&
+		a.BlockIOClass == b.BlockIOClass
+}
+
 const templateStringPrefix = `# The CRI-O configuration file specifies all of the available configuration
 # options and command-line flags for the crio(8) OCI Kubernetes Container Runtime
 # daemon, but in a TOML format that can be more easily modified and versioned.
@@ -860,7 +986,8 @@ const templateStringCrioRuntime = `# The crio.runtime table contains settings pe
 const templateStringCrioRuntimeDefaultUlimits = `# A list of ulimits to be set in containers by default, specified as
 # "<ulimit name>=<soft limit>:<hard limit>", for example:
 # "nofile=1024:2048"
-# If nothing is set here, settings will be inherited from the CRI-O daemon
+# If nothing is set here, settings will be inherited from the CRI-O daemon.
+# This option supports live configuration reload.
 {{ $.Comment }}default_ulimits = [
 {{ range $ulimit := .DefaultUlimits }}{{ $.Comment }}{{ printf "\t%q,\n" $ulimit }}{{ end }}{{ $.Comment }}]
 
@@ -901,6 +1028,7 @@ const templateStringCrioRuntimeConmonEnv = `# Environment variable list for the
 const templateStringCrioRuntimeDefaultEnv = `# Additional environment variables to set for all the
 # containers. These are overridden if set in the
 # container image spec or in the container runtime configuration.
+# This option supports live configuration reload.
 {{ $.Comment }}default_env = [
 {{ range $env := .DefaultEnv }}{{ $.Comment }}{{ printf "\t%q,\n" $env }}{{ end }}{{ $.Comment }}]
 
@@ -918,6 +1046,14 @@ const templateStringCrioRuntimeSeccompProfile = `# Path to the seccomp.json prof
 
 `
 
+const templateStringCrioRuntimeSeccompProfileRecordDir = `# Directory where the seccomp profiles of containers using the seccomp notifier
+# action "record" get written to as <container ID>.json when the container exits.
+# The recorded profiles are not written if empty, but can still be retrieved via
+# the /seccomp/<container ID> endpoint of the CRI-O socket.
+{{ $.Comment }}seccomp_profile_record_dir = "{{ .SeccompProfileRecordDir }}"
+
+`
+
 const templateStringCrioRuntimeSeccompUseDefaultWhenEmpty = `# Changes the meaning of an empty seccomp profile. By default
 # (and according to CRI spec), an empty profile means unconfined.
 # This option tells CRI-O to treat an empty profile as the default profile,
@@ -974,6 +1110,7 @@ const templateStringCrioRuntimeSeparatePullCgroup = `# Specify whether the image
 const templateStringCrioRuntimeDefaultCapabilities = `# List of default capabilities for containers. If it is empty or commented out,
 # only the capabilities defined in the containers json file by the user/kube
 # will be added.
+# This option supports live configuration reload.
 {{ $.Comment }}default_capabilities = [
 {{ range $capability := .DefaultCapabilities}}{{ $.Comment }}{{ printf "\t%q,\n" $capability}}{{ end }}{{ $.Comment }}]
 
@@ -987,6 +1124,7 @@ const templateStringCrioRuntimeAddInheritableCapabilities = `# Add capabilities
 
 const templateStringCrioRuntimeDefaultSysctls = `# List of default sysctls. If it is empty or commented out, only the sysctls
 # defined in the container json file by the user/kube will be added.
+# This option supports live configuration reload.
 {{ $.Comment }}default_sysctls = [
 {{ range $sysctl := .DefaultSysctls}}{{ $.Comment }}{{ printf "\t%q,\n" $sysctl}}{{ end }}{{ $.Comment }}]
 
@@ -994,6 +1132,7 @@ const templateStringCrioRuntimeDefaultSysctls = `# List of default sysctls. If i
 
 const templateStringCrioRuntimeAllowedDevices = `# List of devices on the host that a
 # user can specify with the "io.kubernetes.cri-o.Devices" allowed annotation.
+# This option supports live configuration reload.
 {{ $.Comment }}allowed_devices = [
 {{ range $device := .AllowedDevices}}{{ $.Comment }}{{ printf "\t%q,\n" $device}}{{ end }}{{ $.Comment }}]
 
@@ -1003,6 +1142,7 @@ const templateStringCrioRuntimeAdditionalDevices = `# List of additional devices
 # "<device-on-host>:<device-on-container>:<permissions>", for example: "--device=/dev/sdc:/dev/xvdc:rwm".
 # If it is empty or commented out, only the devices
 # defined in the container json file by the user/kube will be added.
+# This option supports live configuration reload.
 {{ $.Comment }}additional_devices = [
 {{ range $device := .AdditionalDevices}}{{ $.Comment }}{{ printf "\t%q,\n" $device}}{{ end }}{{ $.Comment }}]
 
@@ -1177,6 +1317,49 @@ const templateStringCrioRuntimeEnablePodEvents = `# Enable/disable the generatio
 
 `
 
+const templateStringCrioRuntimeContainerEventsJournalSize = `# Maximum number of container events kept in the journal below the run root.
+# The journal allows clients to replay the events they missed, for example
+# while the kubelet reconnects. Set to 0 to disable the journal.
+{{ $.Comment }}container_events_journal_size = {{ .ContainerEventsJournalSize }}
+
+`
+
+const templateStringCrioRuntimeAuditLog = `# Destination of the audit records of exec, exec_sync, attach and port forward
+# sessions, which is either an absolute file path or "journald". Every record is
+# a JSON object. Set to an empty string to disable the audit log.
+{{ $.Comment }}audit_log = "{{ .AuditLog }}"
+
+`
+
+const templateStringCrioRuntimeAuditLogJournaldIdentifier = `# Syslog identifier of the audit records written to journald.
+{{ $.Comment }}audit_log_journald_identifier = "{{ .AuditLogJournaldIdentifier }}"
+
+`
+
+const templateStringCrioRuntimeAuditLogMaxSize = `# Size in bytes after which the audit log file gets rotated.
+# Set to 0 to disable the rotation.
+{{ $.Comment }}audit_log_max_size = {{ .AuditLogMaxSize }}
+
+`
+
+const templateStringCrioRuntimeAuditLogMaxFiles = `# Number of rotated audit log files to keep.
+{{ $.Comment }}audit_log_max_files = {{ .AuditLogMaxFiles }}
+
+`
+
+const templateStringCrioRuntimeAuditLogOperations = `# List of audited operations, which can be "exec", "exec_sync", "attach" and
+# "port_forward". All operations are audited if the list is empty.
+{{ $.Comment }}audit_log_operations = [
+{{ range $op := .AuditLogOperations}}{{ $.Comment }}{{ printf "\t%q,\n" $op}}{{ end }}{{ $.Comment }}]
+
+`
+
+const templateStringCrioRuntimeAuditLogNamespaces = `# List of pod namespaces to be audited. All pods are audited if the list is empty.
+{{ $.Comment }}audit_log_namespaces = [
+{{ range $ns := .AuditLogNamespaces}}{{ $.Comment }}{{ printf "\t%q,\n" $ns}}{{ end }}{{ $.Comment }}]
+
+`
+
 const templateStringCrioRuntimeDefaultRuntime = `# default_runtime is the _name_ of the OCI runtime to be used as the default.
 # default_runtime is the _name_ of the OCI runtime to be used as the default.
 # The name is matched against the runtimes map below.
@@ -1190,6 +1373,7 @@ const templateStringCrioRuntimeAbsentMountSourcesToReject = `# A list of paths t
 # creation as a file is not desired either.
 # An example is /etc/hostname, which will cause failures on reboot if it's created as a directory, but often doesn't exist because
 # the hostname is being managed dynamically.
+# This option supports live configuration reload.
 {{ $.Comment }}absent_mount_sources_to_reject = [
 {{ range $mount := .AbsentMountSourcesToReject}}{{ $.Comment }}{{ printf "\t%q,\n" $mount}}{{ end }}{{ $.Comment }}]
 
@@ -1272,6 +1456,12 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 # Please be aware that CRI-O is not able to get notified if a syscall gets
 # blocked based on the seccomp defaultAction, which is a general runtime
 # limitation.
+#
+# If the value is "io.kubernetes.cri-o.seccompNotifierAction=record", then
+# CRI-O allows all syscalls of the container while recording them. Once the
+# container exits, a minimal seccomp profile allowing the used syscalls gets
+# written to the seccomp_profile_record_dir. It can also be retrieved from the
+# /seccomp/<container ID> endpoint of the CRI-O socket.
 
 {{ range $runtime_name, $runtime_handler := .Runtimes  }}
 {{ $.Comment }}[crio.runtime.runtimes.{{ $runtime_name }}]
@@ -1298,12 +1488,16 @@ const templateStringCrioRuntimeWorkloads = `# The workloads table defines ways t
 # that work based on annotations, rather than the CRI.
 # Note, the behavior of this table is EXPERIMENTAL and may change at any time.
 # Each workload, has a name, activation_annotation, annotation_prefix and set of resources it supports mutating.
-# The currently supported resources are "cpu" (to configure the cpu shares) and "cpuset" to configure the cpuset.
+# The currently supported resources are "cpushares" (to configure the cpu shares), "cpuset" (to configure the cpuset),
+# "memorylimit" (to configure the memory limit in bytes), "pidslimit" (to configure the pids limit),
+# "hugepagelimits" (to configure the hugepage limits in bytes per page size) and "blockioclass"
+# (to configure the blockio class defined in the blockio_config_file).
 # Each resource can have a default value specified, or be empty.
 # For a container to opt-into this workload, the pod should be configured with the annotation $activation_annotation (key only, value is ignored).
 # To customize per-container, an annotation of the form $annotation_prefix.$resource/$ctrName = "value" can be specified
 # signifying for that resource type to override the default value.
 # If the annotation_prefix is not present, every container in the pod will be given the default values.
+# The workloads table supports live configuration reload.
 # Example:
 # [crio.runtime.workloads.workload-type]
 # activation_annotation = "io.crio/workload"
@@ -1311,6 +1505,10 @@ const templateStringCrioRuntimeWorkloads = `# The workloads table defines ways t
 # [crio.runtime.workloads.workload-type.resources]
 # cpuset = 0
 # cpushares = "0-1"
+# memorylimit = 268435456
+# pidslimit = 1024
+# hugepagelimits = { "2MB" = 1073741824 }
+# blockioclass = "lowprio"
 # Where:
 # The workload name is workload-type.
 # To specify, the pod must have the "io.crio.workload" annotation (this is a precise string match).
@@ -1324,7 +1522,13 @@ const templateStringCrioRuntimeWorkloads = `# The workloads table defines ways t
 {{ $.Comment }}annotation_prefix = "{{ $workload_config.AnnotationPrefix }}"
 {{ if $workload_config.Resources }}{{ $.Comment }}[crio.runtime.workloads.{{ $workload_type }}.resources]
 {{ $.Comment }}cpuset = "{{ $workload_config.Resources.CPUSet }}"
-{{ $.Comment }}cpushares = {{ $workload_config.Resources.CPUShares }}{{ end }}
+{{ $.Comment }}cpushares = {{ $workload_config.Resources.CPUShares }}
+{{ $.Comment }}memorylimit = {{ $workload_config.Resources.MemoryLimit }}
+{{ $.Comment }}pidslimit = {{ $workload_config.Resources.PidsLimit }}
+{{ if $workload_config.Resources.HugepageLimits }}{{ $.Comment }}hugepagelimits = {
+{{- $first := true }}{{- range $pageSize, $limit := $workload_config.Resources.HugepageLimits }}
+{{- if not $first }},{{ end }} {{ printf "%q = %d" $pageSize $limit }}{{- $first = false }}{{- end }} }
+{{ end }}{{ $.Comment }}blockioclass = "{{ $workload_config.Resources.BlockIOClass }}"{{ end }}
 {{ end }}
 `
 
@@ -1423,7 +1627,11 @@ const templateStringCrioImageInsecureRegistries = `# List of registries to skip
 `
 
 const templateStringCrioImageImageVolumes = `# Controls how image volumes are handled. The valid values are mkdir, bind and
-# ignore; the latter will ignore volumes entirely.
+# ignore; the latter will ignore volumes entirely. With bind, CRI-O creates a
+# volume for each image volume path, copies the image content of the path into
+# it and bind mounts it into the container. The volumes are accounted in the
+# writable layer usage of the container stats and removed together with the
+# container.
 {{ $.Comment }}image_volumes = "{{ .ImageVolumes }}"
 
 `
@@ -1433,6 +1641,74 @@ const templateStringCrioImageBigFilesTemporaryDir = `# Temporary directory to us
 
 `
 
+const templateStringCrioImageImageGCHighThresholdPercent = `# Disk usage of the storage root in percent, which triggers the image garbage
+# collection. Unused and not pinned images get removed until the disk usage
+# drops below image_gc_low_threshold_percent. The value 0 disables the disk
+# usage based image garbage collection.
+{{ $.Comment }}image_gc_high_threshold_percent = {{ .ImageGCHighThresholdPercent }}
+
+`
+
+const templateStringCrioImageImageGCLowThresholdPercent = `# Disk usage of the storage root in percent, which the image garbage collection
+# tries to reach once triggered by image_gc_high_threshold_percent.
+{{ $.Comment }}image_gc_low_threshold_percent = {{ .ImageGCLowThresholdPercent }}
+
+`
+
+const templateStringCrioImageImageGCMinAge = `# Minimum time an image has to be unused before it can be removed by the image
+# garbage collection.
+{{ $.Comment }}image_gc_min_age = "{{ .ImageGCMinAge }}"
+
+`
+
+const templateStringCrioImageImageGCMaxAge = `# Maximum time an image can be unused before it gets removed by the image
+# garbage collection, independently of the disk usage. The value 0 disables the
+# age based image garbage collection.
+{{ $.Comment }}image_gc_max_age = "{{ .ImageGCMaxAge }}"
+
+`
+
+const templateStringCrioImageImageGCInterval = `# Interval in which the image garbage collection runs.
+{{ $.Comment }}image_gc_interval = "{{ .ImageGCInterval }}"
+
+`
+
+const templateStringCrioImageMaxConcurrentPulls = `# Maximum number of image pulls running in parallel on the node. Pulls exceeding
+# one of the max_concurrent_pulls* limits are queued fairly between the
+# Kubernetes namespaces. The value 0 disables the limit.
+{{ $.Comment }}max_concurrent_pulls = {{ .MaxConcurrentPulls }}
+
+`
+
+const templateStringCrioImageMaxConcurrentPullsPerRegistry = `# Maximum number of image pulls running in parallel from a single registry host.
+# The value 0 disables the limit.
+{{ $.Comment }}max_concurrent_pulls_per_registry = {{ .MaxConcurrentPullsPerRegistry }}
+
+`
+
+const templateStringCrioImageMaxConcurrentPullsPerNamespace = `# Maximum number of image pulls running in parallel for a single Kubernetes
+# namespace. The value 0 disables the limit.
+{{ $.Comment }}max_concurrent_pulls_per_namespace = {{ .MaxConcurrentPullsPerNamespace }}
+
+`
+
+const templateStringCrioImageRegistryFailureThreshold = `# Number of consecutive failed requests to a registry, like refused connections
+# or timeouts, after which the registry is considered as unhealthy. Unhealthy
+# registries are skipped by image pulls for registry_circuit_breaker_cooldown,
+# while registries with recent failures are tried after the healthy ones. Note
+# that this can change the order in which short names get resolved. Every
+# mirror of a registry is tracked and skipped on its own. The value 0 disables
+# skipping unhealthy registries.
+{{ $.Comment }}registry_failure_threshold = {{ .RegistryFailureThreshold }}
+
+`
+
+const templateStringCrioImageRegistryCircuitBreakerCooldown = `# Time an unhealthy registry is skipped by image pulls. Afterwards, a single
+# image pull probes the registry again, while all others keep skipping it.
+{{ $.Comment }}registry_circuit_breaker_cooldown = "{{ .RegistryCircuitBreakerCooldown }}"
+
+`
+
 const templateStringCrioNetwork = `# The crio.network table containers settings pertaining to the management of
 # CNI plugins.
 [crio.network]
@@ -2,6 +2,7 @@ package config_test
 
 import (
 	"bytes"
+	"os"
 
 	"github.com/cri-o/cri-o/pkg/config"
 	. "github.com/onsi/ginkgo/v2"
@@ -98,4 +99,123 @@ var _ = t.Describe("Config", func() {
 			Expect(config.RuntimesEqual(r1, r2)).To(BeTrue())
 		})
 	})
+	t.Describe("WorkloadsEqual", func() {
+		It("not equal if different resources", func() {
+			// When
+			w1 := config.Workloads{
+				"1": &config.WorkloadConfig{
+					Resources: &config.Resources{PidsLimit: 1024},
+				},
+			}
+			w2 := config.Workloads{
+				"1": &config.WorkloadConfig{
+					Resources: &config.Resources{PidsLimit: 2048},
+				},
+			}
+
+			// Then
+			Expect(config.WorkloadsEqual(w1, w2)).To(BeFalse())
+		})
+		It("not equal if different hugepage limits", func() {
+			// When
+			w1 := config.Workloads{
+				"1": &config.WorkloadConfig{
+					Resources: &config.Resources{
+						HugepageLimits: map[string]uint64{"2MB": 1024},
+					},
+				},
+			}
+			w2 := config.Workloads{
+				"1": &config.WorkloadConfig{
+					Resources: &config.Resources{
+						HugepageLimits: map[string]uint64{"1GB": 1024},
+					},
+				},
+			}
+
+			// Then
+			Expect(config.WorkloadsEqual(w1, w2)).To(BeFalse())
+		})
+		It("equal if unset and empty resources", func() {
+			// When
+			w1 := config.Workloads{
+				"1": &config.WorkloadConfig{ActivationAnnotation: "1"},
+			}
+			w2 := config.Workloads{
+				"1": &config.WorkloadConfig{
+					ActivationAnnotation: "1",
+					Resources: &config.Resources{
+						HugepageLimits: map[string]uint64{},
+					},
+				},
+			}
+
+			// Then
+			Expect(config.WorkloadsEqual(w1, w2)).To(BeTrue())
+		})
+		It("equal if same values", func() {
+			// When
+			w1 := config.Workloads{
+				"1": &config.WorkloadConfig{
+					ActivationAnnotation: "1",
+					Resources: &config.Resources{
+						MemoryLimit:    268435456,
+						HugepageLimits: map[string]uint64{"2MB": 1024},
+						BlockIOClass:   "lowprio",
+					},
+				},
+			}
+			w2 := config.Workloads{
+				"1": &config.WorkloadConfig{
+					ActivationAnnotation: "1",
+					Resources: &config.Resources{
+						MemoryLimit:    268435456,
+						HugepageLimits: map[string]uint64{"2MB": 1024},
+						BlockIOClass:   "lowprio",
+					},
+				},
+			}
+
+			// Then
+			Expect(config.WorkloadsEqual(w1, w2)).To(BeTrue())
+		})
+	})
+	t.Describe("WriteTemplate workloads", func() {
+		BeforeEach(beforeEach)
+		It("should write the workload resources", func() {
+			// Given
+			sut.Workloads = config.Workloads{
+				"management": &config.WorkloadConfig{
+					ActivationAnnotation: "io.crio/workload",
+					AnnotationPrefix:     "io.crio.workload-type",
+					Resources: &config.Resources{
+						CPUShares:   1024,
+						CPUSet:      "0-1",
+						MemoryLimit: 268435456,
+						PidsLimit:   1024,
+						HugepageLimits: map[string]uint64{
+							"1GB": 2147483648,
+							"2MB": 1073741824,
+						},
+						BlockIOClass: "lowprio",
+					},
+				},
+			}
+			var wr bytes.Buffer
+
+			// When
+			err := sut.WriteTemplate(false, &wr)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(wr.String()).To(ContainSubstring(
+				`hugepagelimits = { "1GB" = 2147483648, "2MB" = 1073741824 }`,
+			))
+			file := t.MustTempFile("crio.conf")
+			Expect(os.WriteFile(file, wr.Bytes(), 0o644)).To(BeNil())
+			written := defaultConfig()
+			Expect(written.UpdateFromFile(file)).To(BeNil())
+			Expect(config.WorkloadsEqual(written.Workloads, sut.Workloads)).To(BeTrue())
+		})
+	})
 })
@@ -3,8 +3,14 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/cri-o/cri-o/internal/config/cgmgr"
+	"github.com/cri-o/cri-o/internal/config/node"
+	"github.com/docker/go-units"
+	"github.com/intel/goresctrl/pkg/blockio"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/opencontainers/runtime-tools/generate"
 	"github.com/sirupsen/logrus"
 	"k8s.io/utils/cpuset"
@@ -32,6 +38,10 @@ type WorkloadConfig struct {
 	// The key of the map is the resource name. The following resources are supported:
 	// `cpushares`: configure cpu shares for a given container
 	// `cpuset`: configure cpuset for a given container
+	// `memorylimit`: configure the memory limit in bytes for a given container
+	// `pidslimit`: configure the pids limit for a given container
+	// `hugepagelimits`: configure the hugepage limits in bytes per page size for a given container
+	// `blockioclass`: configure the blockio class for a given container
 	// The value of the map is the default value for that resource.
 	// If a container is configured to use this workload, and does not specify
 	// the annotation with the resource and value, the default value will apply.
@@ -47,6 +57,16 @@ type Resources struct {
 	CPUShares uint64 `json:"cpushares,omitempty"`
 	// Specifies the cpuset this pod has access to.
 	CPUSet string `json:"cpuset,omitempty"`
+	// Specifies the memory limit in bytes of this pod.
+	MemoryLimit int64 `json:"memorylimit,omitempty"`
+	// Specifies the maximum number of processes of this pod, -1 for unlimited.
+	PidsLimit int64 `json:"pidslimit,omitempty"`
+	// Specifies the hugepage limits in bytes of this pod. The key of the map
+	// is the hugepage size, for example "2MB".
+	HugepageLimits map[string]uint64 `json:"hugepagelimits,omitempty"`
+	// Specifies the blockio class of this pod, as defined in the blockio
+	// configuration file.
+	BlockIOClass string `json:"blockioclass,omitempty"`
 }
 
 func (w Workloads) Validate() error {
@@ -118,9 +138,7 @@ func (w Workloads) MutateSpecGivenAnnotations(ctrName string, specgen *generate.
 	if err != nil {
 		return err
 	}
-	resources.MutateSpec(specgen)
-
-	return nil
+	return resources.MutateSpec(specgen)
 }
 
 func (w Workloads) workloadGivenActivationAnnotation(sboxAnnotations map[string]string) *WorkloadConfig {
@@ -148,6 +166,12 @@ func resourcesFromAnnotation(prefix, ctrName string, allAnnotations map[string]s
 	if resources == nil {
 		return nil, nil
 	}
+	if err := resources.validate(); err != nil {
+		return nil, fmt.Errorf("invalid resources in annotation %s: %w", annotationKey, err)
+	}
+	if defaultResources == nil {
+		return resources, nil
+	}
 
 	if resources.CPUSet == "" {
 		resources.CPUSet = defaultResources.CPUSet
@@ -155,6 +179,18 @@ func resourcesFromAnnotation(prefix, ctrName string, allAnnotations map[string]s
 	if resources.CPUShares == 0 {
 		resources.CPUShares = defaultResources.CPUShares
 	}
+	if resources.MemoryLimit == 0 {
+		resources.MemoryLimit = defaultResources.MemoryLimit
+	}
+	if resources.PidsLimit == 0 {
+		resources.PidsLimit = defaultResources.PidsLimit
+	}
+	if resources.HugepageLimits == nil {
+		resources.HugepageLimits = defaultResources.HugepageLimits
+	}
+	if resources.BlockIOClass == "" {
+		resources.BlockIOClass = defaultResources.BlockIOClass
+	}
 
 	return resources, nil
 }
@@ -163,16 +199,47 @@ func (r *Resources) ValidateDefaults() error {
 	if r == nil {
 		return nil
 	}
-	if r.CPUSet == "" {
-		return nil
+	return r.validate()
+}
+
+// hugePageSizeUnits are the units of the hugepage sizes in the kernel format.
+var hugePageSizeUnits = []string{"B", "KB", "MB", "GB", "TB", "PB"}
+
+func (r *Resources) validate() error {
+	if r.CPUSet != "" {
+		if _, err := cpuset.Parse(r.CPUSet); err != nil {
+			return err
+		}
+	}
+	if r.MemoryLimit < 0 {
+		return fmt.Errorf("memory limit %d must not be negative", r.MemoryLimit)
+	}
+	if err := cgmgr.VerifyMemoryIsEnough(r.MemoryLimit); err != nil {
+		return err
 	}
-	_, err := cpuset.Parse(r.CPUSet)
-	return err
+	if r.PidsLimit < -1 {
+		return fmt.Errorf("pids limit %d must be -1 or greater", r.PidsLimit)
+	}
+	for pageSize := range r.HugepageLimits {
+		size, err := units.RAMInBytes(pageSize)
+		if err != nil {
+			return fmt.Errorf("invalid hugepage size %q: %w", pageSize, err)
+		}
+		if size <= 0 {
+			return fmt.Errorf("invalid hugepage size %q: must be positive", pageSize)
+		}
+		// The runtime looks up the hugetlb cgroup files by the page size as
+		// is, which are named in the kernel format, for example "2MB".
+		if kernelSize := units.CustomSize("%g%s", float64(size), 1024.0, hugePageSizeUnits); pageSize != kernelSize {
+			return fmt.Errorf("invalid hugepage size %q: must be in the kernel format %q", pageSize, kernelSize)
+		}
+	}
+	return nil
 }
 
-func (r *Resources) MutateSpec(specgen *generate.Generator) {
+func (r *Resources) MutateSpec(specgen *generate.Generator) error {
 	if r == nil {
-		return
+		return nil
 	}
 	if r.CPUSet != "" {
 		specgen.SetLinuxResourcesCPUCpus(r.CPUSet)
@@ -180,4 +247,38 @@ func (r *Resources) MutateSpec(specgen *generate.Generator) {
 	if r.CPUShares != 0 {
 		specgen.SetLinuxResourcesCPUShares(r.CPUShares)
 	}
+	if r.MemoryLimit != 0 {
+		specgen.SetLinuxResourcesMemoryLimit(r.MemoryLimit)
+		// If node doesn't have memory swap, then skip setting
+		// otherwise the container creation fails.
+		if node.CgroupHasMemorySwap() {
+			specgen.SetLinuxResourcesMemorySwap(r.MemoryLimit)
+		}
+	}
+	// If the kernel has no support for the pids or hugetlb controller,
+	// silently ignore the limits, like done for the CRI resources.
+	if r.PidsLimit != 0 && node.CgroupHasPid() {
+		specgen.SetLinuxResourcesPidsLimit(r.PidsLimit)
+	}
+	if len(r.HugepageLimits) > 0 && node.CgroupHasHugetlb() {
+		pageSizes := make([]string, 0, len(r.HugepageLimits))
+		for pageSize := range r.HugepageLimits {
+			pageSizes = append(pageSizes, pageSize)
+		}
+		sort.Strings(pageSizes)
+		for _, pageSize := range pageSizes {
+			specgen.AddLinuxResourcesHugepageLimit(pageSize, r.HugepageLimits[pageSize])
+		}
+	}
+	if r.BlockIOClass != "" {
+		linuxBlockIO, err := blockio.OciLinuxBlockIO(r.BlockIOClass)
+		if err != nil {
+			return fmt.Errorf("get blockio class %q: %w", r.BlockIOClass, err)
+		}
+		if specgen.Config.Linux.Resources == nil {
+			specgen.Config.Linux.Resources = &rspec.LinuxResources{}
+		}
+		specgen.Config.Linux.Resources.BlockIO = linuxBlockIO
+	}
+	return nil
 }
@@ -0,0 +1,214 @@
+package config_test
+
+import (
+	"github.com/cri-o/cri-o/internal/config/node"
+	"github.com/cri-o/cri-o/pkg/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/runtime-tools/generate"
+)
+
+// The actual test suite
+var _ = t.Describe("Workloads", func() {
+	const (
+		activationAnnotation = "io.crio/workload"
+		annotationPrefix     = "io.crio.workload-type"
+		ctrName              = "ctr"
+	)
+	var workloads config.Workloads
+
+	BeforeEach(func() {
+		workloads = config.Workloads{
+			"management": &config.WorkloadConfig{
+				ActivationAnnotation: activationAnnotation,
+				AnnotationPrefix:     annotationPrefix,
+				Resources: &config.Resources{
+					CPUShares:   512,
+					MemoryLimit: 268435456,
+					PidsLimit:   1024,
+					HugepageLimits: map[string]uint64{
+						"2MB": 1073741824,
+					},
+				},
+			},
+		}
+	})
+
+	t.Describe("Validate", func() {
+		It("should succeed with valid resources", func() {
+			// Given
+			// When
+			err := workloads.Validate()
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail with negative memory limit", func() {
+			// Given
+			workloads["management"].Resources.MemoryLimit = -1
+
+			// When
+			err := workloads.Validate()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with too low memory limit", func() {
+			// Given
+			workloads["management"].Resources.MemoryLimit = 1024
+
+			// When
+			err := workloads.Validate()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should succeed with unlimited pids", func() {
+			// Given
+			workloads["management"].Resources.PidsLimit = -1
+
+			// When
+			err := workloads.Validate()
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail with invalid pids limit", func() {
+			// Given
+			workloads["management"].Resources.PidsLimit = -2
+
+			// When
+			err := workloads.Validate()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with invalid hugepage size", func() {
+			// Given
+			workloads["management"].Resources.HugepageLimits = map[string]uint64{"invalid": 1}
+
+			// When
+			err := workloads.Validate()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with hugepage size not in the kernel format", func() {
+			for _, pageSize := range []string{"2m", "2Mi", "2MiB", "2048KB"} {
+				// Given
+				workloads["management"].Resources.HugepageLimits = map[string]uint64{pageSize: 1}
+
+				// When
+				err := workloads.Validate()
+
+				// Then
+				Expect(err).NotTo(BeNil(), pageSize)
+			}
+		})
+
+		It("should succeed with hugepage sizes in the kernel format", func() {
+			// Given
+			workloads["management"].Resources.HugepageLimits = map[string]uint64{"64KB": 1, "2MB": 1, "1GB": 1}
+
+			// When
+			err := workloads.Validate()
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+	})
+
+	t.Describe("MutateSpecGivenAnnotations", func() {
+		It("should apply the default resources", func() {
+			// Given
+			specgen, err := generate.New("linux")
+			Expect(err).To(BeNil())
+
+			// When
+			err = workloads.MutateSpecGivenAnnotations(ctrName, &specgen, map[string]string{
+				activationAnnotation: "",
+			})
+
+			// Then
+			Expect(err).To(BeNil())
+			resources := specgen.Config.Linux.Resources
+			Expect(*resources.CPU.Shares).To(BeEquivalentTo(512))
+			Expect(*resources.Memory.Limit).To(BeEquivalentTo(268435456))
+			if node.CgroupHasPid() {
+				Expect(resources.Pids.Limit).To(BeEquivalentTo(1024))
+			}
+			if node.CgroupHasHugetlb() {
+				Expect(resources.HugepageLimits).To(HaveLen(1))
+				Expect(resources.HugepageLimits[0].Pagesize).To(Equal("2MB"))
+				Expect(resources.HugepageLimits[0].Limit).To(BeEquivalentTo(1073741824))
+			}
+		})
+
+		It("should override the default resources by annotation", func() {
+			// Given
+			specgen, err := generate.New("linux")
+			Expect(err).To(BeNil())
+
+			// When
+			err = workloads.MutateSpecGivenAnnotations(ctrName, &specgen, map[string]string{
+				activationAnnotation:             "",
+				annotationPrefix + "/" + ctrName: `{"memorylimit": 536870912}`,
+			})
+
+			// Then
+			Expect(err).To(BeNil())
+			resources := specgen.Config.Linux.Resources
+			Expect(*resources.CPU.Shares).To(BeEquivalentTo(512))
+			Expect(*resources.Memory.Limit).To(BeEquivalentTo(536870912))
+		})
+
+		It("should fail with invalid resources in annotation", func() {
+			// Given
+			specgen, err := generate.New("linux")
+			Expect(err).To(BeNil())
+
+			// When
+			err = workloads.MutateSpecGivenAnnotations(ctrName, &specgen, map[string]string{
+				activationAnnotation:             "",
+				annotationPrefix + "/" + ctrName: `{"pidslimit": -2}`,
+			})
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with unknown blockio class", func() {
+			// Given
+			workloads["management"].Resources.BlockIOClass = "unknown"
+			specgen, err := generate.New("linux")
+			Expect(err).To(BeNil())
+
+			// When
+			err = workloads.MutateSpecGivenAnnotations(ctrName, &specgen, map[string]string{
+				activationAnnotation: "",
+			})
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should not mutate without activation annotation", func() {
+			// Given
+			specgen, err := generate.New("linux")
+			Expect(err).To(BeNil())
+
+			// When
+			err = workloads.MutateSpecGivenAnnotations(ctrName, &specgen, map[string]string{})
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(specgen.Config.Linux.Resources.Memory).To(BeNil())
+		})
+	})
+})
@@ -33,4 +33,106 @@ type CrioInfo struct {
 	StorageRoot       string     `json:"storage_root"`
 	CgroupDriver      string     `json:"cgroup_driver"`
 	DefaultIDMappings IDMappings `json:"default_id_mappings"`
+	PodCIDRs          []string   `json:"pod_cidrs,omitempty"`
+}
+
+// PodRestoreInfo stores information about a restored pod
+type PodRestoreInfo struct {
+	ID string `json:"id"`
+}
+
+// ImageGCInfo stores information about the image garbage collection
+type ImageGCInfo struct {
+	Enabled              bool              `json:"enabled"`
+	HighThresholdPercent int               `json:"high_threshold_percent"`
+	LowThresholdPercent  int               `json:"low_threshold_percent"`
+	MinAge               string            `json:"min_age"`
+	MaxAge               string            `json:"max_age"`
+	LastRun              int64             `json:"last_run"`
+	DiskUsagePercent     int               `json:"disk_usage_percent"`
+	Images               []ImageGCImage    `json:"images"`
+	Evictions            []ImageGCEviction `json:"evictions"`
+}
+
+// ImageGCImage stores the last usage of an image tracked by the image
+// garbage collection
+type ImageGCImage struct {
+	ID       string `json:"id"`
+	LastUsed int64  `json:"last_used"`
+}
+
+// ImageGCEviction stores information about an image removed by the image
+// garbage collection
+type ImageGCEviction struct {
+	ID        string   `json:"id"`
+	Names     []string `json:"names"`
+	Size      int64    `json:"size"`
+	LastUsed  int64    `json:"last_used"`
+	EvictedAt int64    `json:"evicted_at"`
+	Reason    string   `json:"reason"`
+}
+
+// PullInfo stores information about an image pull in progress
+type PullInfo struct {
+	ID        string          `json:"id"`
+	Image     string          `json:"image"`
+	Namespace string          `json:"namespace"`
+	StartedAt int64           `json:"started_at"`
+	Waiters   int             `json:"waiters"`
+	Aborted   bool            `json:"aborted"`
+	Layers    []PullLayerInfo `json:"layers"`
+}
+
+// PullLayerInfo stores the progress of a single artifact of an image pull
+type PullLayerInfo struct {
+	Digest    string `json:"digest"`
+	MediaType string `json:"media_type"`
+	Size      int64  `json:"size"`
+	Offset    uint64 `json:"offset"`
+	Done      bool   `json:"done"`
+	Skipped   bool   `json:"skipped"`
+}
+
+// RegistryHealthInfo stores the health of a registry used for image pulls
+type RegistryHealthInfo struct {
+	Registry            string `json:"registry"`
+	Healthy             bool   `json:"healthy"`
+	CircuitOpen         bool   `json:"circuit_open"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	TotalFailures       uint64 `json:"total_failures"`
+	TotalSuccesses      uint64 `json:"total_successes"`
+	LastFailure         int64  `json:"last_failure"`
+	LastError           string `json:"last_error"`
+	OpenUntil           int64  `json:"open_until"`
+}
+
+// ContainerEventInfo stores a container event recorded in the journal
+type ContainerEventInfo struct {
+	Seq          uint64 `json:"seq"`
+	ContainerID  string `json:"container_id"`
+	SandboxID    string `json:"sandbox_id"`
+	PodName      string `json:"pod_name"`
+	PodNamespace string `json:"pod_namespace"`
+	EventType    string `json:"event_type"`
+	CreatedAt    int64  `json:"created_at"`
+}
+
+// ConfigChange stores an effective change of a configuration option
+type ConfigChange struct {
+	Option string `json:"option"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+}
+
+// ConfigOption stores an effective configuration option and its origin
+type ConfigOption struct {
+	Option string `json:"option"`
+	Value  string `json:"value"`
+	Origin string `json:"origin"`
+}
+
+// ConfigReloadInfo stores the result of a configuration reload
+type ConfigReloadInfo struct {
+	DryRun  bool           `json:"dry_run"`
+	Changes []ConfigChange `json:"changes"`
 }
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/audit"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/oci"
+	utilexec "k8s.io/utils/exec"
+)
+
+// auditSession is an audited exec, attach or port forward session. All methods
+// can be called on a nil session, which is returned if the operation does not
+// get audited.
+type auditSession struct {
+	logger  *audit.Logger
+	record  *audit.Record
+	start   time.Time
+	counter audit.Counter
+}
+
+// startAudit starts auditing the operation of the record in the pod sandbox
+// and container. The container may be nil for operations on the sandbox.
+func (s *Server) startAudit(record *audit.Record, sb *sandbox.Sandbox, c *oci.Container) *auditSession {
+	if sb == nil && c != nil {
+		sb = s.GetSandbox(c.Sandbox())
+	}
+	if sb != nil {
+		record.PodID = sb.ID()
+		record.PodNamespace = sb.Namespace()
+		if metadata := sb.Metadata(); metadata != nil {
+			record.PodName = metadata.Name
+			record.PodUID = metadata.Uid
+		}
+	}
+	if !s.auditLogger.Enabled(record.Operation, record.PodNamespace) {
+		return nil
+	}
+	if c != nil {
+		record.ContainerID = c.ID()
+		record.ContainerName = c.Name()
+		if metadata := c.Metadata(); metadata != nil {
+			record.ContainerName = metadata.Name
+		}
+	}
+	return &auditSession{
+		logger: s.auditLogger,
+		record: record,
+		start:  time.Now(),
+	}
+}
+
+// reader wraps the input stream of the client for counting the bytes.
+func (a *auditSession) reader(r io.Reader) io.Reader {
+	if a == nil {
+		return r
+	}
+	return a.counter.Reader(r)
+}
+
+// writeCloser wraps an output stream to the client for counting the bytes.
+func (a *auditSession) writeCloser(w io.WriteCloser) io.WriteCloser {
+	if a == nil {
+		return w
+	}
+	return a.counter.WriteCloser(w)
+}
+
+// readWriteCloser wraps a bidirectional stream of the client for counting the
+// bytes.
+func (a *auditSession) readWriteCloser(rwc io.ReadWriteCloser) io.ReadWriteCloser {
+	if a == nil {
+		return rwc
+	}
+	return a.counter.ReadWriteCloser(rwc)
+}
+
+// finish writes the audit record of the session, where the exit code is nil
+// if it is unknown.
+func (a *auditSession) finish(exitCode *int32, err error) {
+	if a == nil {
+		return
+	}
+	a.record.Timestamp = a.start
+	a.record.Duration = time.Since(a.start)
+	a.record.ExitCode = exitCode
+	a.record.BytesIn = a.counter.BytesIn()
+	a.record.BytesOut = a.counter.BytesOut()
+	if err != nil {
+		a.record.Error = err.Error()
+	}
+	a.logger.Log(a.record)
+}
+
+// execExitCode returns the exit code of an exec session, or nil if the
+// command did not run to completion.
+func execExitCode(err error) *int32 {
+	var code int32
+	if err == nil {
+		return &code
+	}
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) {
+		code = int32(exitErr.ExitStatus())
+		return &code
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/cri-o/cri-o/internal/audit"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/internal/oci"
 	"golang.org/x/net/context"
@@ -41,5 +42,12 @@ func (s StreamService) Attach(ctx context.Context, containerID string, inputStre
 		return fmt.Errorf("container is not created or running")
 	}
 
-	return s.runtimeServer.Runtime().AttachContainer(s.ctx, c, inputStream, outputStream, errorStream, tty, resizeChan)
+	audited := s.runtimeServer.startAudit(&audit.Record{
+		Operation: audit.OperationAttach,
+		TTY:       tty,
+		Stdin:     inputStream != nil,
+	}, nil, c)
+	err = s.runtimeServer.Runtime().AttachContainer(s.ctx, c, audited.reader(inputStream), audited.writeCloser(outputStream), audited.writeCloser(errorStream), tty, resizeChan)
+	audited.finish(nil, err)
+	return err
 }
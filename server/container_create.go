@@ -11,7 +11,6 @@ import (
 
 	"github.com/containers/storage/pkg/idtools"
 	"github.com/containers/storage/pkg/mount"
-	"github.com/containers/storage/pkg/stringid"
 	"github.com/cri-o/cri-o/internal/factory/container"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/log"
@@ -139,21 +138,21 @@ func addImageVolumes(ctx context.Context, rootfs string, s *Server, containerInf
 				}
 			}
 		case config.ImageVolumesBind:
-			volumeDirName := stringid.GenerateNonCryptoID()
-			src := filepath.Join(containerInfo.RunDir, "mounts", volumeDirName)
-			if err1 := os.MkdirAll(src, 0o755); err1 != nil {
+			IDs := idtools.IDPair{UID: int(specgen.Config.Process.User.UID), GID: int(specgen.Config.Process.User.GID)}
+			vol, err1 := s.ImageVolumes().Create(containerInfo.ID, containerInfo.Dir, rootfs, dest, IDs)
+			if err1 != nil {
 				return nil, err1
 			}
 			// Label the source with the sandbox selinux mount label
 			if mountLabel != "" {
-				if err1 := securityLabel(src, mountLabel, true, false); err1 != nil {
+				if err1 := securityLabel(vol.Source, mountLabel, true, false); err1 != nil {
 					return nil, err1
 				}
 			}
 
-			log.Debugf(ctx, "Adding bind mounted volume: %s to %s", src, dest)
+			log.Debugf(ctx, "Adding bind mounted volume: %s to %s", vol.Source, dest)
 			mounts = append(mounts, rspec.Mount{
-				Source:      src,
+				Source:      vol.Source,
 				Destination: dest,
 				Type:        "bind",
 				Options:     []string{"private", "bind", "rw"},
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -237,6 +238,7 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 
 	imageName := imgResult.Name
 	imageRef := imgResult.ID
+	s.imageGC.MarkUsed(imageRef)
 
 	labelOptions, err := ctr.SelinuxLabel(sb.ProcessLabel())
 	if err != nil {
@@ -672,7 +674,7 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 
 	// First add any configured environment variables from crio config.
 	// They will get overridden if specified in the image or container config.
-	specgen.AddMultipleProcessEnv(s.Config().DefaultEnv)
+	specgen.AddMultipleProcessEnv(s.config.DefaultEnv)
 
 	// Add environment variables from image the CRI configuration
 	envs := mergeEnvs(containerImageConfig, containerConfig.Envs)
@@ -689,11 +691,29 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 	}
 
 	// Add image volumes
+	defer func() {
+		if retErr != nil {
+			if err := s.ImageVolumes().Remove(containerID); err != nil {
+				log.Warnf(ctx, "Failed to cleanup image volumes: %v", err)
+			}
+		}
+	}()
 	volumeMounts, err := addImageVolumes(ctx, mountPoint, s, &containerInfo, mountLabel, specgen)
 	if err != nil {
 		return nil, err
 	}
 
+	// The image volumes are kept in the annotations to track them again after a restart
+	if imageVolumes := s.ImageVolumes().Volumes(containerID); len(imageVolumes) > 0 {
+		imageVolumesJSON, err := json.Marshal(imageVolumes)
+		if err != nil {
+			return nil, err
+		}
+		specgen.AddAnnotation(crioann.ImageVolumesAnnotation, string(imageVolumesJSON))
+	} else {
+		specgen.RemoveAnnotation(crioann.ImageVolumesAnnotation)
+	}
+
 	// Set working directory
 	// Pick it up from image config first and override if specified in CRI
 	containerCwd := "/"
@@ -1,13 +1,39 @@
 package server
 
 import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
 	"sync"
+	"time"
 
+	"github.com/cri-o/cri-o/internal/events"
+	crioTypes "github.com/cri-o/cri-o/pkg/types"
+	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
+const (
+	// containerEventsJournalFileName is the name of the file inside the run
+	// root, which persists the container events journal across CRI-O
+	// restarts.
+	containerEventsJournalFileName = "crio-container-events"
+
+	// ContainerEventsSinceSeqKey is the gRPC metadata key used by clients of
+	// GetContainerEvents to replay the journaled events having a greater
+	// sequence number.
+	ContainerEventsSinceSeqKey = "crio-events-since-seq"
+
+	// ContainerEventsSinceKey is the gRPC metadata key used by clients of
+	// GetContainerEvents to replay the journaled events created at or after
+	// the provided time, either in RFC 3339 format or in Unix nanoseconds.
+	ContainerEventsSinceKey = "crio-events-since"
+)
+
 type containerEventConn struct {
 	wg  sync.WaitGroup
 	err error
@@ -28,8 +54,9 @@ func (s *Server) GetContainerEvents(_ *types.GetEventsRequest, ces types.Runtime
 		wg: sync.WaitGroup{},
 	}
 
-	s.containerEventClients.Store(ces, conn)
-	conn.wg.Add(1)
+	if err := s.registerContainerEventClient(ces, conn); err != nil {
+		return err
+	}
 
 	// wait here until we don't want to send events to this client anymore
 	conn.wg.Wait()
@@ -37,6 +64,33 @@ func (s *Server) GetContainerEvents(_ *types.GetEventsRequest, ces types.Runtime
 	return conn.err
 }
 
+// registerContainerEventClient replays the journaled events requested by the
+// client and registers it for receiving new events.
+func (s *Server) registerContainerEventClient(ces types.RuntimeService_GetContainerEventsServer, conn *containerEventConn) error {
+	// Keep the broadcaster from sending new events until the client has been
+	// registered, to neither miss nor duplicate any of them.
+	s.containerEventsLock.Lock()
+	defer s.containerEventsLock.Unlock()
+
+	if s.containerEventsJournal != nil {
+		seq, since, err := containerEventsReplayOptions(ces.Context())
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		if seq > 0 || since > 0 {
+			for _, entry := range s.containerEventsJournal.Since(seq, since) {
+				if err := ces.Send(entry.Event); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	s.containerEventClients.Store(ces, conn)
+	conn.wg.Add(1)
+	return nil
+}
+
 func (s *Server) broadcastEvents() {
 	// notify all connections that ContainerEventsChan has been closed
 	defer s.containerEventClients.Range(func(_, value any) bool { // nolint: unparam
@@ -48,7 +102,22 @@ func (s *Server) broadcastEvents() {
 		return true
 	})
 
+	if s.containerEventsJournal != nil {
+		defer func() {
+			if err := s.containerEventsJournal.Close(); err != nil {
+				logrus.Warnf("Unable to close container events journal: %v", err)
+			}
+		}()
+	}
+
 	for containerEvent := range s.ContainerEventsChan {
+		s.containerEventsLock.Lock()
+		if s.containerEventsJournal != nil {
+			if _, err := s.containerEventsJournal.Append(&containerEvent); err != nil {
+				logrus.Warnf("Unable to record container event in journal: %v", err)
+			}
+		}
+
 		s.containerEventClients.Range(func(key, value any) bool {
 			stream, ok := key.(types.RuntimeService_GetContainerEventsServer)
 			if !ok {
@@ -72,5 +141,85 @@ func (s *Server) broadcastEvents() {
 			}
 			return true
 		})
+		s.containerEventsLock.Unlock()
+	}
+}
+
+// openContainerEventsJournal opens the container events journal below the run
+// root if it is enabled.
+func (s *Server) openContainerEventsJournal() error {
+	if !s.config.EnablePodEvents || s.config.ContainerEventsJournalSize == 0 {
+		return nil
+	}
+	journal, err := events.Open(
+		filepath.Join(s.config.RunRoot, containerEventsJournalFileName),
+		s.config.ContainerEventsJournalSize,
+	)
+	if err != nil {
+		return fmt.Errorf("open container events journal: %w", err)
+	}
+	s.containerEventsJournal = journal
+
+	// Record the events right away, even if no client is connected.
+	s.containerEventStreamBroadcaster.Do(func() {
+		go s.broadcastEvents()
+	})
+	return nil
+}
+
+// containerEventsReplayOptions returns the sequence number and time in Unix
+// nanoseconds requested by the client to replay the journaled events.
+func containerEventsReplayOptions(ctx context.Context) (seq uint64, since int64, err error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0, 0, nil
+	}
+	if values := md.Get(ContainerEventsSinceSeqKey); len(values) > 0 {
+		seq, err = strconv.ParseUint(values[0], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid %s: %w", ContainerEventsSinceSeqKey, err)
+		}
+	}
+	if values := md.Get(ContainerEventsSinceKey); len(values) > 0 {
+		since, err = parseEventsSince(values[0])
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid %s: %w", ContainerEventsSinceKey, err)
+		}
+	}
+	return seq, since, nil
+}
+
+// parseEventsSince parses a time in RFC 3339 format or in Unix nanoseconds.
+func parseEventsSince(value string) (int64, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.UnixNano(), nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// getContainerEventsInfo returns the journaled container events having a
+// sequence number greater than seq and which have been created at or after
+// since.
+func (s *Server) getContainerEventsInfo(seq uint64, since int64) []crioTypes.ContainerEventInfo {
+	infos := []crioTypes.ContainerEventInfo{}
+	if s.containerEventsJournal == nil {
+		return infos
+	}
+	for _, entry := range s.containerEventsJournal.Since(seq, since) {
+		info := crioTypes.ContainerEventInfo{
+			Seq:         entry.Seq,
+			ContainerID: entry.Event.ContainerId,
+			EventType:   entry.Event.ContainerEventType.String(),
+			CreatedAt:   entry.Event.CreatedAt,
+		}
+		if sb := entry.Event.PodSandboxStatus; sb != nil {
+			info.SandboxID = sb.Id
+			if sb.Metadata != nil {
+				info.PodName = sb.Metadata.Name
+				info.PodNamespace = sb.Metadata.Namespace
+			}
+		}
+		infos = append(infos, info)
 	}
+	return infos
 }
@@ -1,11 +1,19 @@
 package server_test
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"time"
 
+	crioTypes "github.com/cri-o/cri-o/pkg/types"
+	"github.com/cri-o/cri-o/server"
 	containereventservermock "github.com/cri-o/cri-o/test/mocks/containereventserver"
+	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/metadata"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
@@ -28,9 +36,10 @@ var _ = t.Describe("ContainerEvents", func() {
 
 		// close after all events have been processed,
 		// so we are not waiting for move events to come.
+		eventsChan := sut.ContainerEventsChan
 		go func() {
 			time.Sleep(2 * time.Second)
-			close(sut.ContainerEventsChan)
+			close(eventsChan)
 		}()
 	})
 
@@ -79,3 +88,87 @@ var _ = t.Describe("ContainerEvents", func() {
 		})
 	})
 })
+
+var _ = t.Describe("ContainerEventsJournal", func() {
+	BeforeEach(func() {
+		beforeEach()
+		serverConfig.RunRoot = testPath
+		serverConfig.ContainerEventsJournalSize = 10
+		setupSUT()
+	})
+
+	AfterEach(afterEach)
+
+	journaled := func() []crioTypes.ContainerEventInfo {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, server.InspectEventsEndpoint, http.NoBody)
+		Expect(err).To(BeNil())
+		sut.GetExtendInterfaceMux(false).ServeHTTP(recorder, request)
+		Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+		infos := []crioTypes.ContainerEventInfo{}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &infos)).To(Succeed())
+		return infos
+	}
+
+	It("should record events without connected clients", func() {
+		// Given
+		// When
+		for _, event := range events {
+			sut.ContainerEventsChan <- event
+		}
+
+		// Then
+		Eventually(journaled).Should(HaveLen(len(events)))
+		infos := journaled()
+		Expect(infos[0].Seq).To(BeEquivalentTo(1))
+		Expect(infos[2].ContainerID).To(Equal("3"))
+		close(sut.ContainerEventsChan)
+	})
+
+	It("should replay the events since the provided sequence number", func() {
+		// Given
+		for _, event := range events {
+			sut.ContainerEventsChan <- event
+		}
+		Eventually(journaled).Should(HaveLen(len(events)))
+
+		cesMock := containereventservermock.NewMockRuntimeService_GetContainerEventsServer(mockCtrl)
+		ctx := metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(server.ContainerEventsSinceSeqKey, "1"))
+		cesMock.EXPECT().Context().Return(ctx)
+		replayed := []string{}
+		cesMock.EXPECT().Send(gomock.Any()).Times(2).DoAndReturn(
+			func(event *types.ContainerEventResponse) error {
+				replayed = append(replayed, event.ContainerId)
+				return nil
+			},
+		)
+
+		// When
+		eventsChan := sut.ContainerEventsChan
+		go func() {
+			time.Sleep(time.Second)
+			close(eventsChan)
+		}()
+		err := sut.GetContainerEvents(nil, cesMock)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(replayed).To(Equal([]string{"2", "3"}))
+	})
+
+	It("should fail with an invalid replay time", func() {
+		// Given
+		cesMock := containereventservermock.NewMockRuntimeService_GetContainerEventsServer(mockCtrl)
+		ctx := metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(server.ContainerEventsSinceKey, "yesterday"))
+		cesMock.EXPECT().Context().Return(ctx)
+
+		// When
+		err := sut.GetContainerEvents(nil, cesMock)
+
+		// Then
+		Expect(err).NotTo(BeNil())
+		close(sut.ContainerEventsChan)
+	})
+})
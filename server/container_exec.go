@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/cri-o/cri-o/internal/audit"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/internal/oci"
 	"golang.org/x/net/context"
@@ -41,5 +42,13 @@ func (s StreamService) Exec(ctx context.Context, containerID string, cmd []strin
 		return fmt.Errorf("container is not created or running")
 	}
 
-	return s.runtimeServer.Runtime().ExecContainer(s.ctx, c, cmd, stdin, stdout, stderr, tty, resizeChan)
+	audited := s.runtimeServer.startAudit(&audit.Record{
+		Operation: audit.OperationExec,
+		Command:   cmd,
+		TTY:       tty,
+		Stdin:     stdin != nil,
+	}, nil, c)
+	err = s.runtimeServer.Runtime().ExecContainer(s.ctx, c, cmd, audited.reader(stdin), audited.writeCloser(stdout), audited.writeCloser(stderr), tty, resizeChan)
+	audited.finish(execExitCode(err), err)
+	return err
 }
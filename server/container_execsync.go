@@ -3,6 +3,7 @@ package server
 import (
 	"errors"
 
+	"github.com/cri-o/cri-o/internal/audit"
 	"github.com/cri-o/cri-o/internal/log"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
@@ -28,5 +29,18 @@ func (s *Server) ExecSync(ctx context.Context, req *types.ExecSyncRequest) (*typ
 		return nil, errors.New("exec command cannot be empty")
 	}
 
-	return s.Runtime().ExecSyncContainer(ctx, c, cmd, req.Timeout)
+	audited := s.startAudit(&audit.Record{
+		Operation: audit.OperationExecSync,
+		Command:   cmd,
+	}, nil, c)
+	resp, err := s.Runtime().ExecSyncContainer(ctx, c, cmd, req.Timeout)
+	if audited != nil {
+		var exitCode *int32
+		if resp != nil {
+			exitCode = &resp.ExitCode
+			audited.counter.Add(0, int64(len(resp.Stdout)+len(resp.Stderr)))
+		}
+		audited.finish(exitCode, err)
+	}
+	return resp, err
 }
@@ -3,9 +3,14 @@ package server
 import (
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 
 	"github.com/containers/storage/pkg/pools"
+	"github.com/cri-o/cri-o/internal/audit"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/pkg/annotations"
 	"golang.org/x/net/context"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
@@ -61,5 +66,64 @@ func (s StreamService) PortForward(ctx context.Context, podSandboxID string, por
 	// defer responsibility of emptying stream to PortForwardContainer
 	emptyStreamOnError = false
 
-	return s.runtimeServer.Runtime().PortForwardContainer(ctx, sb.InfraContainer(), netNsPath, port, stream)
+	protocol, err := portForwardProtocol(sb, port)
+	if err != nil {
+		// The stream has not been consumed yet.
+		emptyStreamOnError = true
+		return err
+	}
+
+	audited := s.runtimeServer.startAudit(&audit.Record{
+		Operation: audit.OperationPortForward,
+		Port:      port,
+		Protocol:  strings.ToLower(protocol.String()),
+	}, sb, nil)
+	err = s.runtimeServer.Runtime().PortForwardContainer(ctx, sb.InfraContainer(), netNsPath, port, protocol, audited.readWriteCloser(stream))
+	audited.finish(nil, err)
+	return err
+}
+
+// portForwardProtocol returns the protocol used for forwarding the port into
+// the sandbox. The protocol can be selected per port by using the
+// PortForwardProtocolsAnnotation, otherwise TCP is used. The port mappings are
+// not considered, because clients like kubectl only speak TCP.
+func portForwardProtocol(sb *sandbox.Sandbox, port int32) (types.Protocol, error) {
+	value, ok := sb.Annotations()[annotations.PortForwardProtocolsAnnotation]
+	if !ok {
+		return types.Protocol_TCP, nil
+	}
+	protocols, err := parsePortForwardProtocols(value)
+	if err != nil {
+		return types.Protocol_TCP, fmt.Errorf("invalid %s annotation: %w", annotations.PortForwardProtocolsAnnotation, err)
+	}
+	if protocol, ok := protocols[port]; ok {
+		return protocol, nil
+	}
+	return types.Protocol_TCP, nil
+}
+
+// parsePortForwardProtocols parses a comma separated list of ports and their
+// protocols, like "53/udp,9999/sctp".
+func parsePortForwardProtocols(value string) (map[int32]types.Protocol, error) {
+	protocols := make(map[int32]types.Protocol)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		portValue, protocolValue, ok := strings.Cut(entry, "/")
+		if !ok {
+			return nil, fmt.Errorf("missing protocol for port %q", entry)
+		}
+		port, err := strconv.ParseInt(portValue, 10, 32)
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("invalid port %q", portValue)
+		}
+		protocol, ok := types.Protocol_value[strings.ToUpper(protocolValue)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported protocol %q", protocolValue)
+		}
+		protocols[int32(port)] = types.Protocol(protocol)
+	}
+	return protocols, nil
 }
@@ -2,7 +2,12 @@ package server_test
 
 import (
 	"context"
+	"time"
 
+	"github.com/cri-o/cri-o/internal/hostport"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/pkg/annotations"
+	"github.com/cri-o/cri-o/server"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
@@ -55,4 +60,93 @@ var _ = t.Describe("ContainerPortforward", func() {
 			Expect(err).NotTo(BeNil())
 		})
 	})
+
+	t.Describe("PortForwardProtocol", func() {
+		newSandbox := func(podAnnotations map[string]string, mappings ...*hostport.PortMapping) *sandbox.Sandbox {
+			sb, err := sandbox.New("sandboxID", "", "", "", ".",
+				make(map[string]string), podAnnotations, "", "",
+				&types.PodSandboxMetadata{}, "", "", false, "", "", "",
+				mappings, false, time.Now(), "", nil, nil)
+			Expect(err).To(BeNil())
+			return sb
+		}
+
+		It("should default to TCP without annotation", func() {
+			// Given
+			sb := newSandbox(map[string]string{})
+
+			// When
+			protocol, err := server.PortForwardProtocol(sb, 53)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(protocol).To(Equal(types.Protocol_TCP))
+		})
+
+		It("should ignore the port mappings without annotation", func() {
+			// Given
+			sb := newSandbox(map[string]string{}, &hostport.PortMapping{
+				HostPort:      5353,
+				ContainerPort: 53,
+				Protocol:      "UDP",
+			})
+
+			// When
+			protocol, err := server.PortForwardProtocol(sb, 53)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(protocol).To(Equal(types.Protocol_TCP))
+		})
+
+		It("should use the protocols of the annotation", func() {
+			// Given
+			sb := newSandbox(map[string]string{
+				annotations.PortForwardProtocolsAnnotation: "53/udp, 9999/SCTP,8080/tcp",
+			})
+
+			// When
+			udp, udpErr := server.PortForwardProtocol(sb, 53)
+			sctp, sctpErr := server.PortForwardProtocol(sb, 9999)
+			tcp, tcpErr := server.PortForwardProtocol(sb, 8080)
+
+			// Then
+			Expect(udpErr).To(BeNil())
+			Expect(udp).To(Equal(types.Protocol_UDP))
+			Expect(sctpErr).To(BeNil())
+			Expect(sctp).To(Equal(types.Protocol_SCTP))
+			Expect(tcpErr).To(BeNil())
+			Expect(tcp).To(Equal(types.Protocol_TCP))
+		})
+
+		It("should default to TCP for ports missing in the annotation", func() {
+			// Given
+			sb := newSandbox(map[string]string{
+				annotations.PortForwardProtocolsAnnotation: "53/udp",
+			})
+
+			// When
+			protocol, err := server.PortForwardProtocol(sb, 80)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(protocol).To(Equal(types.Protocol_TCP))
+		})
+
+		for _, invalid := range []string{"53", "udp/53", "0/udp", "70000/udp", "53/icmp"} {
+			invalid := invalid
+			It("should fail with invalid annotation "+invalid, func() {
+				// Given
+				sb := newSandbox(map[string]string{
+					annotations.PortForwardProtocolsAnnotation: invalid,
+				})
+
+				// When
+				_, err := server.PortForwardProtocol(sb, 53)
+
+				// Then
+				Expect(err).NotTo(BeNil())
+			})
+		}
+	})
 })
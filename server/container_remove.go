@@ -42,6 +42,7 @@ func (s *Server) RemoveContainer(ctx context.Context, req *types.RemoveContainer
 				log.Errorf(ctx, "Unable to close seccomp notifier: %v", err)
 			}
 		}
+		s.seccompNotifiers.Delete(c.ID())
 	}
 	s.generateCRIEvent(ctx, c, types.ContainerEventType_CONTAINER_DELETED_EVENT)
 	log.Infof(ctx, "Removed container %s: %s", c.ID(), c.Description())
@@ -70,6 +71,10 @@ func (s *Server) removeContainerInPod(ctx context.Context, sb *sandbox.Sandbox,
 		return fmt.Errorf("failed to remove container exit file %s: %w", c.ID(), err)
 	}
 
+	if err := s.ImageVolumes().Remove(c.ID()); err != nil {
+		return fmt.Errorf("failed to remove image volumes of container %s: %w", c.ID(), err)
+	}
+
 	c.CleanupConmonCgroup(ctx)
 
 	if err := s.StorageRuntimeServer().StopContainer(ctx, c.ID()); err != nil && err != storage.ErrContainerUnknown {
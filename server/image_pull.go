@@ -13,6 +13,7 @@ import (
 
 	"github.com/containers/image/v5/signature"
 	imageTypes "github.com/containers/image/v5/types"
+	"github.com/containers/storage/pkg/stringid"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/internal/storage"
 	"github.com/cri-o/cri-o/server/metrics"
@@ -25,6 +26,8 @@ import (
 
 var localRegistryHostname = "localhost"
 
+var errPullNotInProgress = errors.New("pull operation not in progress")
+
 // PullImage pulls a image with authentication config.
 func (s *Server) PullImage(ctx context.Context, req *types.PullImageRequest) (*types.PullImageResponse, error) {
 	ctx, span := log.StartSpan(ctx)
@@ -71,41 +74,43 @@ func (s *Server) PullImage(ctx context.Context, req *types.PullImageRequest) (*t
 
 	// We use the server's pullOperationsInProgress to record which images are
 	// currently being pulled. This allows for avoiding pulling the same image
-	// in parallel. Hence, if a given image is currently being pulled, we queue
-	// into the pullOperation's waitgroup and wait for the pulling goroutine to
-	// unblock us and re-use its results.
-	pullOp, pullInProcess := func() (pullOp *pullOperation, inProgress bool) {
+	// in parallel. Hence, if a given image is currently being pulled, we wait
+	// for the pulling goroutine to finish and re-use its results.
+	// The pull is bound to the lifetime of the server rather than to the
+	// request of its first caller. It gets canceled if aborted by AbortPull or
+	// if no caller is left waiting for it.
+	pullOp := func() *pullOperation {
 		s.pullOperationsLock.Lock()
 		defer s.pullOperationsLock.Unlock()
-		pullOp, inProgress = s.pullOperationsInProgress[pullArgs]
+		pullOp, inProgress := s.pullOperationsInProgress[pullArgs]
 		if !inProgress {
-			pullOp = &pullOperation{}
+			pullCtx, cancel := context.WithCancel(s.ctx)
+			pullOp = &pullOperation{
+				done:      make(chan struct{}),
+				id:        stringid.GenerateNonCryptoID(),
+				startedAt: time.Now(),
+				cancel:    cancel,
+				layers:    make(map[digest.Digest]*pullLayerProgress),
+			}
 			s.pullOperationsInProgress[pullArgs] = pullOp
 			storage.ImageBeingPulled.Store(pullArgs.image, true)
-			pullOp.wg.Add(1)
+			go s.runPullOperation(pullCtx, pullArgs, pullOp)
 		}
-		return pullOp, inProgress
+		pullOp.callers++
+		return pullOp
 	}()
 
-	if !pullInProcess {
-		pullOp.err = errors.New("pullImage was aborted by a Go panic")
-		defer func() {
-			s.pullOperationsLock.Lock()
-			delete(s.pullOperationsInProgress, pullArgs)
-			storage.ImageBeingPulled.Delete(pullArgs.image)
-			pullOp.wg.Done()
-			s.pullOperationsLock.Unlock()
-		}()
-		pullOp.imageRef, pullOp.err = s.pullImage(ctx, &pullArgs)
-	} else {
-		// Wait for the pull operation to finish.
-		pullOp.wg.Wait()
+	select {
+	case <-pullOp.done:
+	case <-ctx.Done():
+		s.leavePullOperation(pullArgs, pullOp)
+		return nil, fmt.Errorf("waiting for pull of image %s: %w", image, ctx.Err())
 	}
 
 	if pullOp.err != nil {
 		wrap := func(e error) error { return fmt.Errorf("%v: %w", e, pullOp.err) }
 
-		if errors.Is(pullOp.err, syscall.ECONNREFUSED) {
+		if errors.Is(pullOp.err, syscall.ECONNREFUSED) || errors.Is(pullOp.err, storage.ErrRegistryCircuitOpen) {
 			return nil, wrap(crierrors.ErrRegistryUnavailable)
 		}
 
@@ -123,10 +128,46 @@ func (s *Server) PullImage(ctx context.Context, req *types.PullImageRequest) (*t
 	}, nil
 }
 
+// runPullOperation runs the pull operation and releases all callers waiting
+// for it once it has finished.
+func (s *Server) runPullOperation(ctx context.Context, pullArgs pullArguments, pullOp *pullOperation) {
+	pullOp.err = errors.New("pullImage was aborted by a Go panic")
+	defer func() {
+		s.pullOperationsLock.Lock()
+		if s.pullOperationsInProgress[pullArgs] == pullOp {
+			delete(s.pullOperationsInProgress, pullArgs)
+		}
+		if _, inProgress := s.pullOperationsInProgress[pullArgs]; !inProgress {
+			storage.ImageBeingPulled.Delete(pullArgs.image)
+		}
+		pullOp.cancel()
+		close(pullOp.done)
+		s.pullOperationsLock.Unlock()
+	}()
+	pullOp.imageRef, pullOp.err = s.pullImage(ctx, &pullArgs, pullOp)
+}
+
+// leavePullOperation removes a caller which stopped waiting from the pull
+// operation. The pull operation gets canceled if no caller is left, so that
+// subsequent pulls of the same image start over.
+func (s *Server) leavePullOperation(pullArgs pullArguments, pullOp *pullOperation) {
+	s.pullOperationsLock.Lock()
+	defer s.pullOperationsLock.Unlock()
+
+	pullOp.callers--
+	if pullOp.callers > 0 {
+		return
+	}
+	pullOp.cancel()
+	if s.pullOperationsInProgress[pullArgs] == pullOp {
+		delete(s.pullOperationsInProgress, pullArgs)
+	}
+}
+
 // pullImage performs the actual pull operation of PullImage. Used to separate
 // the pull implementation from the pullCache logic in PullImage and improve
 // readability and maintainability.
-func (s *Server) pullImage(ctx context.Context, pullArgs *pullArguments) (string, error) {
+func (s *Server) pullImage(ctx context.Context, pullArgs *pullArguments, pullOp *pullOperation) (string, error) {
 	var err error
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
@@ -156,11 +197,22 @@ func (s *Server) pullImage(ctx context.Context, pullArgs *pullArguments) (string
 	if err != nil {
 		return "", err
 	}
+	// Every mirror of a candidate is tried as a separate pull source, so that
+	// its health can be tracked on its own.
+	pullSources, err := s.registryHealth.Order(s.config.SystemContext, remoteCandidates)
+	if err != nil {
+		return "", fmt.Errorf("pulling image %s: %w", pullArgs.image, err)
+	}
 	var pulled *storage.RegistryImageReference // = nil
-	for _, remoteCandidateName := range remoteCandidates {
-		remoteCandidateName := remoteCandidateName // So that *&remoteCandidateName does not change as we iterate the loop.
+	for _, pullSource := range pullSources {
+		remoteCandidateName := pullSource.Candidate // So that *&remoteCandidateName does not change as we iterate the loop.
+		pullEndpoint := pullSource.Endpoint
+		pullSourceCtx := pullSource.SystemContext(&sourceCtx)
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("pulling image %s aborted: %w", pullArgs.image, ctx.Err())
+		}
 		var tmpImg imageTypes.ImageCloser
-		tmpImg, err = s.StorageImageServer().PrepareImage(&sourceCtx, remoteCandidateName)
+		tmpImg, err = s.StorageImageServer().PrepareImage(pullSourceCtx, remoteCandidateName)
 		if err != nil {
 			// We're not able to find the image remotely, check if it's
 			// available locally, but only for localhost ones.
@@ -173,9 +225,13 @@ func (s *Server) pullImage(ctx context.Context, pullArgs *pullArguments) (string
 				}
 			}
 			log.Debugf(ctx, "Error preparing image %s: %v", remoteCandidateName, err)
+			if s.registryHealth.RecordFailure(pullEndpoint, err) {
+				log.Warnf(ctx, "Registry %s seems to be unhealthy: %v", pullEndpoint, err)
+			}
 			tryIncrementImagePullFailureMetric(remoteCandidateName, err)
 			continue
 		}
+		s.registryHealth.RecordSuccess(pullEndpoint)
 		defer tmpImg.Close() // nolint:gocritic
 
 		var storedImage *storage.ImageResult
@@ -210,6 +266,7 @@ func (s *Server) pullImage(ctx context.Context, pullArgs *pullArguments) (string
 		defer close(progress) // nolint:gocritic
 		go func() {
 			for p := range progress {
+				pullOp.updateProgress(&p)
 				if p.Event == imageTypes.ProgressEventSkipped {
 					// Skipped digests metrics
 					tryRecordSkippedMetric(ctx, remoteCandidateName, p.Artifact.Digest)
@@ -268,8 +325,13 @@ func (s *Server) pullImage(ctx context.Context, pullArgs *pullArguments) (string
 			}
 		}
 
-		_, err = s.StorageImageServer().PullImage(s.config.SystemContext, remoteCandidateName, &storage.ImageCopyOptions{
-			SourceCtx:        &sourceCtx,
+		var release func()
+		release, err = s.pullLimiter.Acquire(ctx, remoteCandidateName.Registry(), pullArgs.namespace)
+		if err != nil {
+			return "", fmt.Errorf("pulling image %s aborted while waiting for a free pull slot: %w", pullArgs.image, err)
+		}
+		_, err = s.StorageImageServer().PullImage(ctx, s.config.SystemContext, remoteCandidateName, &storage.ImageCopyOptions{
+			SourceCtx:        pullSourceCtx,
 			DestinationCtx:   s.config.SystemContext,
 			OciDecryptConfig: decryptConfig,
 			ProgressInterval: time.Second,
@@ -279,8 +341,13 @@ func (s *Server) pullImage(ctx context.Context, pullArgs *pullArguments) (string
 				ParentCgroup: cgroup,
 			},
 		})
+		release()
 		if err != nil {
 			log.Debugf(ctx, "Error pulling image %s: %v", remoteCandidateName, err)
+			if ctx.Err() != nil {
+				return "", fmt.Errorf("pulling image %s aborted: %w", pullArgs.image, ctx.Err())
+			}
+			s.registryHealth.RecordFailure(pullEndpoint, err)
 			tryIncrementImagePullFailureMetric(remoteCandidateName, err)
 			continue
 		}
@@ -299,6 +366,7 @@ func (s *Server) pullImage(ctx context.Context, pullArgs *pullArguments) (string
 	if err != nil {
 		return "", err
 	}
+	s.imageGC.MarkUsed(status.ID)
 	imageRef := status.ID
 	if len(status.RepoDigests) > 0 {
 		imageRef = status.RepoDigests[0]
@@ -307,6 +375,47 @@ func (s *Server) pullImage(ctx context.Context, pullArgs *pullArguments) (string
 	return imageRef, nil
 }
 
+// updateProgress records the progress of a single artifact of the pull
+// operation.
+func (p *pullOperation) updateProgress(progress *imageTypes.ProgressProperties) {
+	p.progressLock.Lock()
+	defer p.progressLock.Unlock()
+
+	layer, ok := p.layers[progress.Artifact.Digest]
+	if !ok {
+		layer = &pullLayerProgress{}
+		p.layers[progress.Artifact.Digest] = layer
+	}
+	layer.mediaType = progress.Artifact.MediaType
+	layer.size = progress.Artifact.Size
+	layer.offset = progress.Offset
+	switch progress.Event {
+	case imageTypes.ProgressEventDone:
+		layer.done = true
+	case imageTypes.ProgressEventSkipped:
+		layer.done = true
+		layer.skipped = true
+	}
+}
+
+// AbortPull aborts the pull operation with the provided ID for all callers
+// waiting for it.
+func (s *Server) AbortPull(ctx context.Context, id string) error {
+	s.pullOperationsLock.Lock()
+	defer s.pullOperationsLock.Unlock()
+
+	for pullArgs, pullOp := range s.pullOperationsInProgress {
+		if pullOp.id != id {
+			continue
+		}
+		log.Infof(ctx, "Aborting pull of image %s with %d waiting callers", pullArgs.image, pullOp.callers)
+		pullOp.aborted = true
+		pullOp.cancel()
+		return nil
+	}
+	return fmt.Errorf("%s: %w", id, errPullNotInProgress)
+}
+
 func tryIncrementImagePullFailureMetric(img storage.RegistryImageReference, err error) {
 	// We try to cover some basic use-cases
 	const labelUnknown = "UNKNOWN"
@@ -2,10 +2,14 @@ package server_test
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 
 	imageTypes "github.com/containers/image/v5/types"
 	"github.com/cri-o/cri-o/internal/storage"
 	"github.com/cri-o/cri-o/internal/storage/references"
+	crioTypes "github.com/cri-o/cri-o/pkg/types"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -40,7 +44,7 @@ var _ = t.Describe("ImagePull", func() {
 				imageCloserMock.EXPECT().ConfigInfo().
 					Return(imageTypes.BlobInfo{Digest: digest.Digest("")}),
 				imageServerMock.EXPECT().PullImage(
-					gomock.Any(), imageCandidate, gomock.Any()).
+					gomock.Any(), gomock.Any(), imageCandidate, gomock.Any()).
 					Return(nil, nil),
 				imageServerMock.EXPECT().ImageStatusByName(
 					gomock.Any(), imageCandidate).
@@ -117,7 +121,7 @@ var _ = t.Describe("ImagePull", func() {
 				imageCloserMock.EXPECT().ConfigInfo().
 					Return(imageTypes.BlobInfo{Digest: digest.Digest("")}),
 				imageServerMock.EXPECT().PullImage(
-					gomock.Any(), imageCandidate, gomock.Any()).
+					gomock.Any(), gomock.Any(), imageCandidate, gomock.Any()).
 					Return(nil, nil),
 				imageServerMock.EXPECT().ImageStatusByName(
 					gomock.Any(), imageCandidate).
@@ -141,6 +145,191 @@ var _ = t.Describe("ImagePull", func() {
 			Expect(response).To(BeNil())
 		})
 
+		It("should abort an in progress pull for all waiters", func() {
+			// Given
+			started := make(chan struct{})
+			imageCloserMock.EXPECT().LayerInfos().Return(nil)
+			imageCloserMock.EXPECT().ConfigInfo().Return(imageTypes.BlobInfo{})
+			gomock.InOrder(
+				imageServerMock.EXPECT().CandidatesForPotentiallyShortImageName(
+					gomock.Any(), "image").
+					Return([]storage.RegistryImageReference{imageCandidate}, nil),
+				imageServerMock.EXPECT().PrepareImage(gomock.Any(),
+					imageCandidate).Return(imageCloserMock, nil),
+				imageServerMock.EXPECT().ImageStatusByName(
+					gomock.Any(), imageCandidate).
+					Return(nil, t.TestError),
+				imageServerMock.EXPECT().PullImage(
+					gomock.Any(), gomock.Any(), imageCandidate, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, _ *imageTypes.SystemContext, _ storage.RegistryImageReference, options *storage.ImageCopyOptions) (imageTypes.ImageReference, error) {
+						options.Progress <- imageTypes.ProgressProperties{
+							Event:    imageTypes.ProgressEventRead,
+							Artifact: imageTypes.BlobInfo{Digest: digest.FromString("layer"), Size: 100},
+							Offset:   50,
+						}
+						close(started)
+						<-ctx.Done()
+						return nil, ctx.Err()
+					}),
+				imageCloserMock.EXPECT().Close().Return(nil),
+			)
+			pullErrs := make(chan error, 2)
+			for i := 0; i < 2; i++ {
+				go func() {
+					defer GinkgoRecover()
+					_, err := sut.PullImage(context.Background(),
+						&types.PullImageRequest{Image: &types.ImageSpec{Image: "image"}})
+					pullErrs <- err
+				}()
+			}
+			<-started
+			mux := sut.GetExtendInterfaceMux(false)
+			var pulls []crioTypes.PullInfo
+			Eventually(func(g Gomega) {
+				recorder := httptest.NewRecorder()
+				request, err := http.NewRequest(http.MethodGet, "/pulls", http.NoBody)
+				g.Expect(err).To(BeNil())
+				mux.ServeHTTP(recorder, request)
+				g.Expect(json.Unmarshal(recorder.Body.Bytes(), &pulls)).To(Succeed())
+				g.Expect(pulls).To(HaveLen(1))
+				g.Expect(pulls[0].Waiters).To(Equal(1))
+				g.Expect(pulls[0].Layers).To(HaveLen(1))
+			}).Should(Succeed())
+			Expect(pulls[0].Image).To(Equal("image"))
+			Expect(pulls[0].Layers[0].Offset).To(BeEquivalentTo(50))
+
+			// When
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/pulls/abort/"+pulls[0].ID, http.NoBody)
+			Expect(err).To(BeNil())
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			for i := 0; i < 2; i++ {
+				var pullErr error
+				Eventually(pullErrs).Should(Receive(&pullErr))
+				Expect(pullErr).To(MatchError(context.Canceled))
+			}
+		})
+
+		It("should continue a pull if its first caller is gone", func() {
+			// Given
+			started := make(chan struct{})
+			release := make(chan struct{})
+			gomock.InOrder(
+				imageServerMock.EXPECT().CandidatesForPotentiallyShortImageName(
+					gomock.Any(), "image").
+					Return([]storage.RegistryImageReference{imageCandidate}, nil),
+				imageServerMock.EXPECT().PrepareImage(gomock.Any(),
+					imageCandidate).Return(imageCloserMock, nil),
+				imageServerMock.EXPECT().ImageStatusByName(
+					gomock.Any(), imageCandidate).
+					Return(nil, t.TestError),
+				imageServerMock.EXPECT().PullImage(
+					gomock.Any(), gomock.Any(), imageCandidate, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, _ *imageTypes.SystemContext, _ storage.RegistryImageReference, _ *storage.ImageCopyOptions) (imageTypes.ImageReference, error) {
+						close(started)
+						<-release
+						return nil, ctx.Err()
+					}),
+				imageServerMock.EXPECT().ImageStatusByName(
+					gomock.Any(), imageCandidate).
+					Return(&storage.ImageResult{
+						ID:          "image",
+						RepoDigests: []string{"digest"},
+					}, nil),
+				imageCloserMock.EXPECT().Close().Return(nil),
+			)
+			firstCtx, cancelFirst := context.WithCancel(context.Background())
+			defer cancelFirst()
+			firstErr := make(chan error, 1)
+			go func() {
+				defer GinkgoRecover()
+				_, err := sut.PullImage(firstCtx,
+					&types.PullImageRequest{Image: &types.ImageSpec{Image: "image"}})
+				firstErr <- err
+			}()
+			<-started
+			secondResponse := make(chan *types.PullImageResponse, 1)
+			go func() {
+				defer GinkgoRecover()
+				response, err := sut.PullImage(context.Background(),
+					&types.PullImageRequest{Image: &types.ImageSpec{Image: "image"}})
+				Expect(err).To(BeNil())
+				secondResponse <- response
+			}()
+			mux := sut.GetExtendInterfaceMux(false)
+			Eventually(func() int {
+				recorder := httptest.NewRecorder()
+				request, err := http.NewRequest(http.MethodGet, "/pulls", http.NoBody)
+				Expect(err).To(BeNil())
+				mux.ServeHTTP(recorder, request)
+				var pulls []crioTypes.PullInfo
+				Expect(json.Unmarshal(recorder.Body.Bytes(), &pulls)).To(Succeed())
+				Expect(pulls).To(HaveLen(1))
+				return pulls[0].Waiters
+			}).Should(Equal(1))
+
+			// When
+			cancelFirst()
+			var pullErr error
+			Eventually(firstErr).Should(Receive(&pullErr))
+			close(release)
+
+			// Then
+			Expect(pullErr).To(MatchError(context.Canceled))
+			var response *types.PullImageResponse
+			Eventually(secondResponse).Should(Receive(&response))
+			Expect(response.ImageRef).To(Equal("digest"))
+		})
+
+		It("should cancel a pull if no caller is left", func() {
+			// Given
+			started := make(chan struct{})
+			canceled := make(chan struct{})
+			gomock.InOrder(
+				imageServerMock.EXPECT().CandidatesForPotentiallyShortImageName(
+					gomock.Any(), "image").
+					Return([]storage.RegistryImageReference{imageCandidate}, nil),
+				imageServerMock.EXPECT().PrepareImage(gomock.Any(),
+					imageCandidate).Return(imageCloserMock, nil),
+				imageServerMock.EXPECT().ImageStatusByName(
+					gomock.Any(), imageCandidate).
+					Return(nil, t.TestError),
+				imageServerMock.EXPECT().PullImage(
+					gomock.Any(), gomock.Any(), imageCandidate, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, _ *imageTypes.SystemContext, _ storage.RegistryImageReference, _ *storage.ImageCopyOptions) (imageTypes.ImageReference, error) {
+						close(started)
+						<-ctx.Done()
+						return nil, ctx.Err()
+					}),
+				imageCloserMock.EXPECT().Close().DoAndReturn(func() error {
+					close(canceled)
+					return nil
+				}),
+			)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			pullErr := make(chan error, 1)
+			go func() {
+				defer GinkgoRecover()
+				_, err := sut.PullImage(ctx,
+					&types.PullImageRequest{Image: &types.ImageSpec{Image: "image"}})
+				pullErr <- err
+			}()
+			<-started
+
+			// When
+			cancel()
+
+			// Then
+			var err error
+			Eventually(pullErr).Should(Receive(&err))
+			Expect(err).To(MatchError(context.Canceled))
+			Eventually(canceled).Should(BeClosed())
+		})
+
 		It("should fail credential decode errors", func() {
 			// Given
 			// When
@@ -171,7 +360,7 @@ var _ = t.Describe("ImagePull", func() {
 				imageCloserMock.EXPECT().ConfigInfo().
 					Return(imageTypes.BlobInfo{Digest: digest.Digest("")}),
 				imageServerMock.EXPECT().PullImage(
-					gomock.Any(), imageCandidate, gomock.Any()).
+					gomock.Any(), gomock.Any(), imageCandidate, gomock.Any()).
 					Return(nil, t.TestError),
 				imageCloserMock.EXPECT().Close().Return(nil),
 			)
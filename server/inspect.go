@@ -6,6 +6,8 @@ import (
 	"math"
 	"net/http"
 	"net/http/pprof"
+	"sort"
+	"strconv"
 
 	"github.com/containers/storage/pkg/idtools"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
@@ -16,6 +18,7 @@ import (
 	json "github.com/json-iterator/go"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
+	cri "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
 func (s *Server) getIDMappingsInfo() types.IDMappings {
@@ -48,6 +51,7 @@ func (s *Server) getInfo() types.CrioInfo {
 		StorageImage:      s.config.ImageStore,
 		CgroupDriver:      s.config.CgroupManager().Name(),
 		DefaultIDMappings: s.getIDMappingsInfo(),
+		PodCIDRs:          s.config.CNIManagerPodCIDRs(),
 	}
 }
 
@@ -112,12 +116,68 @@ func (s *Server) getContainerInfo(ctx context.Context, id string, getContainerFu
 	}, nil
 }
 
+// getPullsInfo returns the information about all pull operations in progress.
+func (s *Server) getPullsInfo() []types.PullInfo {
+	s.pullOperationsLock.Lock()
+	defer s.pullOperationsLock.Unlock()
+
+	pulls := make([]types.PullInfo, 0, len(s.pullOperationsInProgress))
+	for pullArgs, pullOp := range s.pullOperationsInProgress {
+		info := types.PullInfo{
+			ID:        pullOp.id,
+			Image:     pullArgs.image,
+			Namespace: pullArgs.namespace,
+			StartedAt: pullOp.startedAt.UnixNano(),
+			Waiters:   pullOp.callers - 1,
+			Aborted:   pullOp.aborted,
+		}
+
+		pullOp.progressLock.Lock()
+		info.Layers = make([]types.PullLayerInfo, 0, len(pullOp.layers))
+		for dgst, layer := range pullOp.layers {
+			info.Layers = append(info.Layers, types.PullLayerInfo{
+				Digest:    dgst.String(),
+				MediaType: layer.mediaType,
+				Size:      layer.size,
+				Offset:    layer.offset,
+				Done:      layer.done,
+				Skipped:   layer.skipped,
+			})
+		}
+		pullOp.progressLock.Unlock()
+
+		sort.Slice(info.Layers, func(i, j int) bool {
+			return info.Layers[i].Digest < info.Layers[j].Digest
+		})
+		pulls = append(pulls, info)
+	}
+	sort.Slice(pulls, func(i, j int) bool {
+		return pulls[i].StartedAt < pulls[j].StartedAt
+	})
+	return pulls
+}
+
 const (
-	InspectConfigEndpoint     = "/config"
-	InspectContainersEndpoint = "/containers"
-	InspectInfoEndpoint       = "/info"
-	InspectPauseEndpoint      = "/pause"
-	InspectUnpauseEndpoint    = "/unpause"
+	InspectConfigEndpoint       = "/config"
+	InspectConfigReloadEndpoint = "/config/reload"
+	InspectContainersEndpoint   = "/containers"
+	InspectInfoEndpoint         = "/info"
+	InspectPauseEndpoint        = "/pause"
+	InspectUnpauseEndpoint      = "/unpause"
+
+	InspectPodCheckpointEndpoint = "/pods/checkpoint"
+	InspectPodRestoreEndpoint    = "/pods/restore"
+
+	InspectImageGCEndpoint = "/images/gc"
+
+	InspectPullsEndpoint      = "/pulls"
+	InspectPullsAbortEndpoint = "/pulls/abort"
+
+	InspectRegistriesEndpoint = "/registries"
+
+	InspectEventsEndpoint = "/events"
+
+	InspectSeccompEndpoint = "/seccomp"
 )
 
 // GetExtendInterfaceMux returns the mux used to serve extend interface requests
@@ -125,6 +185,29 @@ func (s *Server) GetExtendInterfaceMux(enableProfile bool) *chi.Mux {
 	mux := chi.NewMux()
 
 	mux.Get(InspectConfigEndpoint, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch format := req.URL.Query().Get("format"); format {
+		case "", "toml":
+		case "json":
+			options, err := s.config.Options()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			js, err := json.Marshal(options)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			if _, err := w.Write(js); err != nil {
+				logrus.Errorf("Unable to write response JSON: %v", err)
+			}
+			return
+		default:
+			http.Error(w, fmt.Sprintf("invalid format %q, must be toml or json", format), http.StatusBadRequest)
+			return
+		}
+
 		b, err := s.config.ToBytes()
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -137,6 +220,31 @@ func (s *Server) GetExtendInterfaceMux(enableProfile bool) *chi.Mux {
 		}
 	}))
 
+	mux.Get(InspectConfigReloadEndpoint, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		dryRun := false
+		if value := req.URL.Query().Get("dry-run"); value != "" {
+			var err error
+			if dryRun, err = strconv.ParseBool(value); err != nil {
+				http.Error(w, fmt.Sprintf("invalid dry-run value: %v", err), http.StatusBadRequest)
+				return
+			}
+		}
+		changes, err := s.reloadConfig(dryRun)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to reload configuration: %v", err), http.StatusUnprocessableEntity)
+			return
+		}
+		js, err := json.Marshal(types.ConfigReloadInfo{DryRun: dryRun, Changes: changes})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(js); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
 	mux.Get(InspectInfoEndpoint, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ci := s.getInfo()
 		js, err := json.Marshal(ci)
@@ -238,6 +346,163 @@ func (s *Server) GetExtendInterfaceMux(enableProfile bool) *chi.Mux {
 		}
 	}))
 
+	mux.Get(InspectPodCheckpointEndpoint+"/{id}", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		podSandboxID := chi.URLParam(req, "id")
+		location := req.URL.Query().Get("location")
+		if location == "" {
+			http.Error(w, "the location of the pod checkpoint archive must be provided", http.StatusBadRequest)
+			return
+		}
+		if _, err := s.getPodSandboxFromRequest(req.Context(), podSandboxID); err != nil {
+			http.Error(w, fmt.Sprintf("can't find the pod with id %s", podSandboxID), http.StatusNotFound)
+			return
+		}
+		if err := s.CheckpointPod(s.stream.ctx, podSandboxID, location); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		if _, err := w.Write([]byte("200 OK")); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
+	mux.Get(InspectPodRestoreEndpoint, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		location := query.Get("location")
+		if location == "" {
+			http.Error(w, "the location of the pod checkpoint archive must be provided", http.StatusBadRequest)
+			return
+		}
+		var podMetadata *cri.PodSandboxMetadata
+		if query.Get("name") != "" || query.Get("namespace") != "" || query.Get("uid") != "" {
+			podMetadata = &cri.PodSandboxMetadata{
+				Name:      query.Get("name"),
+				Namespace: query.Get("namespace"),
+				Uid:       query.Get("uid"),
+			}
+		}
+		podSandboxID, err := s.RestorePod(s.stream.ctx, location, podMetadata)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		js, err := json.Marshal(types.PodRestoreInfo{ID: podSandboxID})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(js); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
+	mux.Get(InspectImageGCEndpoint, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		js, err := json.Marshal(s.imageGC.Info())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(js); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
+	mux.Get(InspectPullsEndpoint, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		js, err := json.Marshal(s.getPullsInfo())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(js); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
+	mux.Get(InspectPullsAbortEndpoint+"/{id}", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := s.AbortPull(s.stream.ctx, chi.URLParam(req, "id")); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, errPullNotInProgress) {
+				status = http.StatusNotFound
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		if _, err := w.Write([]byte("200 OK")); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
+	mux.Get(InspectRegistriesEndpoint, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		js, err := json.Marshal(s.registryHealth.Info())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(js); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
+	mux.Get(InspectEventsEndpoint, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		var (
+			seq   uint64
+			since int64
+			err   error
+		)
+		if value := query.Get("seq"); value != "" {
+			if seq, err = strconv.ParseUint(value, 10, 64); err != nil {
+				http.Error(w, fmt.Sprintf("invalid sequence number: %v", err), http.StatusBadRequest)
+				return
+			}
+		}
+		if value := query.Get("since"); value != "" {
+			if since, err = parseEventsSince(value); err != nil {
+				http.Error(w, fmt.Sprintf("invalid time: %v", err), http.StatusBadRequest)
+				return
+			}
+		}
+		js, err := json.Marshal(s.getContainerEventsInfo(seq, since))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(js); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
+	mux.Get(InspectSeccompEndpoint+"/{id}", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := context.TODO()
+		containerID := chi.URLParam(req, "id")
+		c, err := s.GetContainerFromShortID(ctx, containerID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("can't find the container with id %s", containerID), http.StatusNotFound)
+			return
+		}
+		profile, err := s.recordedSeccompProfile(c)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("no seccomp profile recorded for container %s", c.ID()), http.StatusNotFound)
+			return
+		}
+		js, err := json.Marshal(profile)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(js); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
 	// Add pprof handlers
 	if enableProfile {
 		mux.Get("/debug/pprof/cmdline", http.HandlerFunc(pprof.Cmdline))
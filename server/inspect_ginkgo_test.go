@@ -254,5 +254,184 @@ var _ = t.Describe("Inspect", func() {
 			Expect(request).NotTo(BeNil())
 			Expect(recorder.Code).To(BeEquivalentTo(http.StatusConflict))
 		})
+		It("should fail without location on /pods/checkpoint route", func() {
+			// Given
+			addContainerAndSandbox()
+
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/pods/checkpoint/"+testSandbox.ID(), http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusBadRequest))
+		})
+
+		It("should fail with invalid pod ID on /pods/checkpoint route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/pods/checkpoint/123?location=pod.tar", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusNotFound))
+		})
+
+		It("should succeed with /images/gc route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/images/gc", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"enabled":false`))
+		})
+
+		It("should succeed with /pulls route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/pulls", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			Expect(recorder.Body.String()).To(Equal("[]"))
+		})
+
+		It("should fail with unknown pull ID on /pulls/abort route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/pulls/abort/id", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusNotFound))
+		})
+
+		It("should succeed with /registries route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/registries", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			Expect(recorder.Body.String()).To(Equal("[]"))
+		})
+
+		It("should succeed with /events route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/events?seq=1", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			Expect(recorder.Body.String()).To(Equal("[]"))
+		})
+
+		It("should fail with invalid time on /events route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/events?since=yesterday", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusBadRequest))
+		})
+
+		It("should succeed with json format on /config route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/config?format=json", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"option":"crio.runtime.log_level"`))
+		})
+
+		It("should fail with invalid format on /config route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/config?format=yaml", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusBadRequest))
+		})
+
+		It("should fail with invalid dry-run on /config/reload route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/config/reload?dry-run=maybe", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusBadRequest))
+		})
+
+		It("should fail with invalid container ID on /seccomp route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/seccomp/123", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusNotFound))
+		})
+
+		It("should fail without recording notifier on /seccomp route", func() {
+			// Given
+			addContainerAndSandbox()
+
+			// When
+			request, err := http.NewRequest(http.MethodGet,
+				"/seccomp/"+testContainer.ID(), http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusNotFound))
+			Expect(recorder.Body.String()).To(ContainSubstring("no seccomp profile recorded"))
+		})
+
+		It("should fail without location on /pods/restore route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/pods/restore", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusBadRequest))
+		})
 	})
 })
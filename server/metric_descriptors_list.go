@@ -1,13 +1,14 @@
 package server
 
 import (
+	statsserver "github.com/cri-o/cri-o/internal/lib/stats"
 	"golang.org/x/net/context"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
 // ListMetricDescriptors lists all metric descriptors
 func (s *Server) ListMetricDescriptors(ctx context.Context, req *types.ListMetricDescriptorsRequest) (*types.ListMetricDescriptorsResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method unimplemented")
+	return &types.ListMetricDescriptorsResponse{
+		Descriptors: statsserver.MetricDescriptors(),
+	}, nil
 }
@@ -93,6 +93,12 @@ type Metrics struct {
 	metricContainersOOMCountTotal             *prometheus.CounterVec
 	metricContainersSeccompNotifierCountTotal *prometheus.CounterVec
 	metricResourcesStalledAtStage             *prometheus.CounterVec
+	metricImageGCEvictionsTotal               *prometheus.CounterVec
+	metricImageGCEvictedBytesTotal            *prometheus.CounterVec
+	metricImagePullsQueueDepth                prometheus.Gauge
+	metricImagePullsQueueWaitSeconds          prometheus.Histogram
+	metricRegistryFailuresTotal               *prometheus.CounterVec
+	metricRegistryCircuitOpen                 *prometheus.GaugeVec
 }
 
 var instance *Metrics
@@ -338,6 +344,53 @@ func New(config *libconfig.MetricsConfig) *Metrics {
 			},
 			[]string{"stage"},
 		),
+		metricImageGCEvictionsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.ImageGCEvictionsTotal.String(),
+				Help:      "Cumulative number of images removed by the CRI-O image garbage collection by reason.",
+			},
+			[]string{"reason"},
+		),
+		metricImageGCEvictedBytesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.ImageGCEvictedBytesTotal.String(),
+				Help:      "Cumulative number of bytes freed by the CRI-O image garbage collection by reason.",
+			},
+			[]string{"reason"},
+		),
+		metricImagePullsQueueDepth: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.ImagePullsQueueDepth.String(),
+				Help:      "Number of CRI-O image pulls waiting for a free pull slot.",
+			},
+		),
+		metricImagePullsQueueWaitSeconds: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.ImagePullsQueueWaitSeconds.String(),
+				Help:      "Time in seconds CRI-O image pulls waited for a free pull slot.",
+				Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600},
+			},
+		),
+		metricRegistryFailuresTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.RegistryFailuresTotal.String(),
+				Help:      "Cumulative number of failed requests to registries during CRI-O image pulls by registry.",
+			},
+			[]string{"registry"},
+		),
+		metricRegistryCircuitOpen: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.RegistryCircuitOpen.String(),
+				Help:      "Registries skipped by CRI-O image pulls because they are unhealthy, 1 if skipped and 0 otherwise.",
+			},
+			[]string{"registry"},
+		),
 	}
 	return Instance()
 }
@@ -582,6 +635,52 @@ func (m *Metrics) MetricResourcesStalledAtStage(stage string) {
 	c.Inc()
 }
 
+func (m *Metrics) MetricImageGCEvictionsInc(reason string, size int64) {
+	c, err := m.metricImageGCEvictionsTotal.GetMetricWithLabelValues(reason)
+	if err != nil {
+		logrus.Warnf("Unable to write image GC evictions metric: %v", err)
+		return
+	}
+	c.Inc()
+
+	c, err = m.metricImageGCEvictedBytesTotal.GetMetricWithLabelValues(reason)
+	if err != nil {
+		logrus.Warnf("Unable to write image GC evicted bytes metric: %v", err)
+		return
+	}
+	c.Add(float64(size))
+}
+
+func (m *Metrics) MetricImagePullsQueueDepthSet(depth int) {
+	m.metricImagePullsQueueDepth.Set(float64(depth))
+}
+
+func (m *Metrics) MetricImagePullsQueueWaitObserve(wait time.Duration) {
+	m.metricImagePullsQueueWaitSeconds.Observe(wait.Seconds())
+}
+
+func (m *Metrics) MetricRegistryFailuresInc(registry string) {
+	c, err := m.metricRegistryFailuresTotal.GetMetricWithLabelValues(registry)
+	if err != nil {
+		logrus.Warnf("Unable to write registry failures metric: %v", err)
+		return
+	}
+	c.Inc()
+}
+
+func (m *Metrics) MetricRegistryCircuitOpenSet(registry string, open bool) {
+	g, err := m.metricRegistryCircuitOpen.GetMetricWithLabelValues(registry)
+	if err != nil {
+		logrus.Warnf("Unable to write registry circuit open metric: %v", err)
+		return
+	}
+	if open {
+		g.Set(1)
+		return
+	}
+	g.Set(0)
+}
+
 // createEndpoint creates a /metrics endpoint for prometheus monitoring.
 func (m *Metrics) createEndpoint() (*http.ServeMux, error) {
 	for collector, metric := range map[collectors.Collector]prometheus.Collector{
@@ -613,6 +712,12 @@ func (m *Metrics) createEndpoint() (*http.ServeMux, error) {
 		collectors.ContainersOOMCountTotal:             m.metricContainersOOMCountTotal,
 		collectors.ContainersSeccompNotifierCountTotal: m.metricContainersSeccompNotifierCountTotal,
 		collectors.ResourcesStalledAtStage:             m.metricResourcesStalledAtStage,
+		collectors.ImageGCEvictionsTotal:               m.metricImageGCEvictionsTotal,
+		collectors.ImageGCEvictedBytesTotal:            m.metricImageGCEvictedBytesTotal,
+		collectors.ImagePullsQueueDepth:                m.metricImagePullsQueueDepth,
+		collectors.ImagePullsQueueWaitSeconds:          m.metricImagePullsQueueWaitSeconds,
+		collectors.RegistryFailuresTotal:               m.metricRegistryFailuresTotal,
+		collectors.RegistryCircuitOpen:                 m.metricRegistryCircuitOpen,
 	} {
 		if m.config.MetricsCollectors.Contains(collector) {
 			logrus.Debugf("Enabling metric: %s", collector.Stripped())
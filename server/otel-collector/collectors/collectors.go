@@ -107,6 +107,24 @@ const (
 
 	// ResourcesStalledAtStage is the key for the resources stalled at different stages in container and pod creation.
 	ResourcesStalledAtStage Collector = crioPrefix + "resources_stalled_at_stage"
+
+	// ImageGCEvictionsTotal is the key for the images removed by the CRI-O image garbage collection.
+	ImageGCEvictionsTotal Collector = crioPrefix + "image_gc_evictions_total"
+
+	// ImageGCEvictedBytesTotal is the key for the bytes freed by the CRI-O image garbage collection.
+	ImageGCEvictedBytesTotal Collector = crioPrefix + "image_gc_evicted_bytes_total"
+
+	// ImagePullsQueueDepth is the key for the image pulls waiting for a free pull slot.
+	ImagePullsQueueDepth Collector = crioPrefix + "image_pulls_queue_depth"
+
+	// ImagePullsQueueWaitSeconds is the key for the time image pulls waited for a free pull slot.
+	ImagePullsQueueWaitSeconds Collector = crioPrefix + "image_pulls_queue_wait_seconds"
+
+	// RegistryFailuresTotal is the key for the failed requests to registries during image pulls.
+	RegistryFailuresTotal Collector = crioPrefix + "registry_failures_total"
+
+	// RegistryCircuitOpen is the key for the registries skipped by image pulls because they are unhealthy.
+	RegistryCircuitOpen Collector = crioPrefix + "registry_circuit_open"
 )
 
 // FromSlice converts a string slice to a Collectors type.
@@ -156,6 +174,12 @@ func All() Collectors {
 		ContainersOOMCountTotal.Stripped(),
 		ContainersSeccompNotifierCountTotal.Stripped(),
 		ResourcesStalledAtStage.Stripped(),
+		ImageGCEvictionsTotal.Stripped(),
+		ImageGCEvictedBytesTotal.Stripped(),
+		ImagePullsQueueDepth.Stripped(),
+		ImagePullsQueueWaitSeconds.Stripped(),
+		RegistryFailuresTotal.Stripped(),
+		RegistryCircuitOpen.Stripped(),
 	}
 }
 
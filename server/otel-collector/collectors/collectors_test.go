@@ -63,11 +63,13 @@ var _ = t.Describe("Collectors", func() {
 				collectors.ContainersOOMCountTotal,
 				collectors.ContainersSeccompNotifierCountTotal,
 				collectors.ResourcesStalledAtStage,
+				collectors.ImageGCEvictionsTotal,
+				collectors.ImageGCEvictedBytesTotal,
 			} {
 				Expect(all.Contains(collector)).To(BeTrue())
 			}
 
-			Expect(all).To(HaveLen(27))
+			Expect(all).To(HaveLen(33))
 		})
 	})
 
@@ -3,16 +3,45 @@ package server
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/pkg/config"
+	"github.com/cri-o/cri-o/utils/cmdrunner"
+	"github.com/opencontainers/runtime-spec/specs-go/features"
+	"github.com/opencontainers/selinux/go-selinux"
 	"golang.org/x/net/context"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
-// networkNotReadyReason is the reason reported when network is not ready.
-const networkNotReadyReason = "NetworkPluginNotReady"
+const (
+	// networkNotReadyReason is the reason reported when network is not ready.
+	networkNotReadyReason = "NetworkPluginNotReady"
+
+	// runtimeNotReadyReason is the reason reported when the default runtime
+	// handler is not usable.
+	runtimeNotReadyReason = "DefaultRuntimeHandlerNotReady"
+
+	// storageNotReadyReason is the reason reported when the container storage
+	// is not usable.
+	storageNotReadyReason = "StorageNotReady"
+
+	// runtimeHandlerNotReadyReason is the reason reported when a runtime
+	// handler is not usable.
+	runtimeHandlerNotReadyReason = "RuntimeHandlerNotReady"
+
+	// RuntimeHandlerReadyConditionPrefix is the prefix of the runtime
+	// condition type, which gets reported for every runtime handler that is
+	// not usable. The name of the runtime handler is used as suffix.
+	RuntimeHandlerReadyConditionPrefix = "RuntimeHandlerReady/"
+)
 
 // Status returns the status of the runtime
 func (s *Server) Status(ctx context.Context, req *types.StatusRequest) (*types.StatusResponse, error) {
+	ctx, span := log.StartSpan(ctx)
+	defer span.End()
+
 	runtimeCondition := &types.RuntimeCondition{
 		Type:   types.RuntimeReady,
 		Status: true,
@@ -28,16 +57,50 @@ func (s *Server) Status(ctx context.Context, req *types.StatusRequest) (*types.S
 		networkCondition.Message = fmt.Sprintf("Network plugin returns error: %v", err)
 	}
 
+	conditions := []*types.RuntimeCondition{
+		runtimeCondition,
+		networkCondition,
+	}
+
+	handlerErrors := s.probeRuntimeHandlers()
+	for _, name := range sortedRuntimeHandlerNames(s.config.Runtimes) {
+		err, ok := handlerErrors[name]
+		if !ok {
+			continue
+		}
+		log.Debugf(ctx, "Runtime handler %q is not usable: %v", name, err)
+
+		message := fmt.Sprintf("Runtime handler %q is not usable: %v", name, err)
+		if name == s.config.DefaultRuntime {
+			runtimeCondition.Status = false
+			runtimeCondition.Reason = runtimeNotReadyReason
+			runtimeCondition.Message = message
+		}
+		conditions = append(conditions, &types.RuntimeCondition{
+			Type:    RuntimeHandlerReadyConditionPrefix + name,
+			Status:  false,
+			Reason:  runtimeHandlerNotReadyReason,
+			Message: message,
+		})
+	}
+
+	storage := s.storageInfo()
+	if storage.Error != "" {
+		log.Debugf(ctx, "Storage is not usable: %s", storage.Error)
+		if runtimeCondition.Status {
+			runtimeCondition.Status = false
+			runtimeCondition.Reason = storageNotReadyReason
+			runtimeCondition.Message = "Storage is not usable: " + storage.Error
+		}
+	}
+
 	resp := &types.StatusResponse{
 		Status: &types.RuntimeStatus{
-			Conditions: []*types.RuntimeCondition{
-				runtimeCondition,
-				networkCondition,
-			},
+			Conditions: conditions,
 		},
 	}
 	if req.Verbose {
-		info, err := s.createRuntimeInfo()
+		info, err := s.createRuntimeInfo(ctx, handlerErrors, &storage)
 		if err != nil {
 			return nil, fmt.Errorf("creating runtime info: %w", err)
 		}
@@ -46,13 +109,153 @@ func (s *Server) Status(ctx context.Context, req *types.StatusRequest) (*types.S
 	return resp, nil
 }
 
-func (s *Server) createRuntimeInfo() (map[string]string, error) {
-	config := map[string]interface{}{
-		"sandboxImage": s.config.ImageConfig.PauseImage,
+// probeRuntimeHandlers probes every configured runtime handler and returns
+// the errors of the unusable ones indexed by their name.
+func (s *Server) probeRuntimeHandlers() map[string]error {
+	handlerErrors := make(map[string]error)
+	for name, handler := range s.config.Runtimes {
+		if err := handler.Probe(name); err != nil {
+			handlerErrors[name] = err
+		}
+	}
+	return handlerErrors
+}
+
+func sortedRuntimeHandlerNames(runtimes config.Runtimes) []string {
+	names := make([]string, 0, len(runtimes))
+	for name := range runtimes {
+		names = append(names, name)
 	}
-	bytes, err := json.Marshal(config)
+	sort.Strings(names)
+	return names
+}
+
+// runtimeHandlerInfo is the verbose status information of a runtime handler.
+type runtimeHandlerInfo struct {
+	RuntimePath    string             `json:"runtimePath"`
+	RuntimeType    string             `json:"runtimeType"`
+	Ready          bool               `json:"ready"`
+	Message        string             `json:"message,omitempty"`
+	MonitorPath    string             `json:"monitorPath,omitempty"`
+	MonitorVersion string             `json:"monitorVersion,omitempty"`
+	Features       *features.Features `json:"features,omitempty"`
+}
+
+// storageInfo is the verbose status information of the storage driver.
+type storageInfo struct {
+	Driver string            `json:"driver"`
+	Root   string            `json:"root"`
+	Status map[string]string `json:"status,omitempty"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// securityInfo is the verbose status information of the security features.
+type securityInfo struct {
+	Seccomp  seccompInfo  `json:"seccomp"`
+	AppArmor apparmorInfo `json:"apparmor"`
+	SELinux  selinuxInfo  `json:"selinux"`
+}
+
+type seccompInfo struct {
+	Enabled             bool `json:"enabled"`
+	UseDefaultWhenEmpty bool `json:"useDefaultWhenEmpty"`
+}
+
+type apparmorInfo struct {
+	Enabled bool `json:"enabled"`
+}
+
+type selinuxInfo struct {
+	Enabled       bool `json:"enabled"`
+	SystemEnabled bool `json:"systemEnabled"`
+}
+
+func (s *Server) createRuntimeInfo(ctx context.Context, handlerErrors map[string]error, storage *storageInfo) (map[string]string, error) {
+	info := map[string]interface{}{
+		"config": map[string]interface{}{
+			"sandboxImage": s.config.ImageConfig.PauseImage,
+		},
+		"runtimeHandlers": s.runtimeHandlersInfo(ctx, handlerErrors),
+		"storage":         storage,
+		"security":        s.securityInfo(),
+	}
+
+	res := make(map[string]string, len(info))
+	for key, value := range info {
+		bytes, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("marshal %s data: %w", key, err)
+		}
+		res[key] = string(bytes)
+	}
+	return res, nil
+}
+
+func (s *Server) runtimeHandlersInfo(ctx context.Context, handlerErrors map[string]error) map[string]runtimeHandlerInfo {
+	handlers := make(map[string]runtimeHandlerInfo, len(s.config.Runtimes))
+	for name, handler := range s.config.Runtimes {
+		handlerInfo := runtimeHandlerInfo{
+			RuntimePath: handler.RuntimePath,
+			RuntimeType: handler.RuntimeType,
+			Ready:       true,
+			MonitorPath: handler.MonitorPath,
+			Features:    handler.RuntimeFeatures(),
+		}
+		if err, ok := handlerErrors[name]; ok {
+			handlerInfo.Ready = false
+			handlerInfo.Message = err.Error()
+		} else if handler.RuntimeType != config.RuntimeTypeVM && handler.MonitorPath != "" {
+			version, err := monitorVersion(handler.MonitorPath)
+			if err != nil {
+				log.Warnf(ctx, "Unable to retrieve version of monitor %s: %v", handler.MonitorPath, err)
+			}
+			handlerInfo.MonitorVersion = version
+		}
+		handlers[name] = handlerInfo
+	}
+	return handlers
+}
+
+// monitorVersion returns the first line of the version output of conmon or
+// conmon-rs.
+func monitorVersion(monitorPath string) (string, error) {
+	output, err := cmdrunner.CombinedOutput(monitorPath, "--version")
+	if err != nil {
+		return "", err
+	}
+	version, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	return version, nil
+}
+
+func (s *Server) storageInfo() storageInfo {
+	info := storageInfo{
+		Driver: s.config.Storage,
+		Root:   s.config.Root,
+	}
+	status, err := s.Store().Status()
 	if err != nil {
-		return nil, fmt.Errorf("marshal data: %w", err)
+		info.Error = err.Error()
+		return info
+	}
+	info.Status = make(map[string]string, len(status))
+	for _, pair := range status {
+		info.Status[pair[0]] = pair[1]
+	}
+	return info
+}
+
+func (s *Server) securityInfo() securityInfo {
+	return securityInfo{
+		Seccomp: seccompInfo{
+			Enabled:             !s.config.Seccomp().IsDisabled(),
+			UseDefaultWhenEmpty: s.config.Seccomp().UseDefaultWhenEmpty(),
+		},
+		AppArmor: apparmorInfo{
+			Enabled: s.config.AppArmor().IsEnabled(),
+		},
+		SELinux: selinuxInfo{
+			Enabled:       s.config.SELinux,
+			SystemEnabled: selinux.GetEnabled(),
+		},
 	}
-	return map[string]string{"config": string(bytes)}, nil
 }
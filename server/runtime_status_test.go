@@ -2,7 +2,11 @@ package server_test
 
 import (
 	"context"
+	"errors"
 
+	"github.com/cri-o/cri-o/pkg/config"
+	"github.com/cri-o/cri-o/server"
+	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
@@ -13,6 +17,7 @@ var _ = t.Describe("Status", func() {
 	// Prepare the sut
 	BeforeEach(func() {
 		beforeEach()
+		mockRuncInLibConfig()
 		setupSUT()
 	})
 
@@ -20,6 +25,9 @@ var _ = t.Describe("Status", func() {
 
 	t.Describe("Status", func() {
 		It("should succeed", func() {
+			// Given
+			storeMock.EXPECT().Status().Return(nil, nil)
+
 			// When
 			response, err := sut.Status(context.Background(),
 				&types.StatusRequest{})
@@ -34,6 +42,9 @@ var _ = t.Describe("Status", func() {
 		})
 
 		It("should succeed when CNI plugin status errors", func() {
+			// Given
+			storeMock.EXPECT().Status().Return(nil, nil)
+
 			// When
 			response, err := sut.Status(context.Background(),
 				&types.StatusRequest{})
@@ -48,6 +59,11 @@ var _ = t.Describe("Status", func() {
 		})
 
 		It("should return info as part of a verbose response", func() {
+			// Given
+			gomock.InOrder(
+				storeMock.EXPECT().Status().Return([][2]string{{"Backing Filesystem", "xfs"}}, nil),
+			)
+
 			// When
 			response, err := sut.Status(context.Background(),
 				&types.StatusRequest{Verbose: true})
@@ -56,6 +72,88 @@ var _ = t.Describe("Status", func() {
 			Expect(err).To(BeNil())
 			Expect(response).NotTo(BeNil())
 			Expect(response.Info).NotTo(BeNil())
+			Expect(response.Info).To(HaveKey("config"))
+			Expect(response.Info).To(HaveKey("security"))
+			Expect(response.Info["runtimeHandlers"]).To(ContainSubstring(`"runc":{`))
+			Expect(response.Info["runtimeHandlers"]).To(ContainSubstring(`"ready":true`))
+			Expect(response.Info["storage"]).To(ContainSubstring(`"Backing Filesystem":"xfs"`))
+		})
+
+		It("should return storage error as part of a verbose response", func() {
+			// Given
+			gomock.InOrder(
+				storeMock.EXPECT().Status().Return(nil, errors.New("error")),
+			)
+
+			// When
+			response, err := sut.Status(context.Background(),
+				&types.StatusRequest{Verbose: true})
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(response).NotTo(BeNil())
+			Expect(response.Info["storage"]).To(ContainSubstring(`"error":"error"`))
+		})
+
+		It("should report unusable storage", func() {
+			// Given
+			storeMock.EXPECT().Status().Return(nil, errors.New("error"))
+
+			// When
+			response, err := sut.Status(context.Background(),
+				&types.StatusRequest{})
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(response).NotTo(BeNil())
+			Expect(response.Status.Conditions).To(HaveLen(2))
+			Expect(response.Status.Conditions[0].Type).To(Equal(types.RuntimeReady))
+			Expect(response.Status.Conditions[0].Status).To(BeFalse())
+			Expect(response.Status.Conditions[0].Reason).To(Equal("StorageNotReady"))
+			Expect(response.Status.Conditions[0].Message).To(ContainSubstring("error"))
+			Expect(response.Status.Conditions[1].Status).To(BeTrue())
+		})
+
+		It("should report unusable default runtime handler", func() {
+			// Given
+			storeMock.EXPECT().Status().Return(nil, nil)
+			sut.Config().Runtimes["runc"].RuntimePath = "/not/existing"
+
+			// When
+			response, err := sut.Status(context.Background(),
+				&types.StatusRequest{})
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(response).NotTo(BeNil())
+			Expect(response.Status.Conditions).To(HaveLen(3))
+			Expect(response.Status.Conditions[0].Type).To(Equal(types.RuntimeReady))
+			Expect(response.Status.Conditions[0].Status).To(BeFalse())
+			Expect(response.Status.Conditions[1].Status).To(BeTrue())
+			Expect(response.Status.Conditions[2].Type).To(Equal(server.RuntimeHandlerReadyConditionPrefix + "runc"))
+			Expect(response.Status.Conditions[2].Status).To(BeFalse())
+		})
+
+		It("should report unusable additional runtime handler", func() {
+			// Given
+			storeMock.EXPECT().Status().Return(nil, nil)
+			sut.Config().Runtimes["other"] = &config.RuntimeHandler{
+				RuntimePath: "/not/existing",
+			}
+
+			// When
+			response, err := sut.Status(context.Background(),
+				&types.StatusRequest{})
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(response).NotTo(BeNil())
+			Expect(response.Status.Conditions).To(HaveLen(3))
+			Expect(response.Status.Conditions[0].Status).To(BeTrue())
+			Expect(response.Status.Conditions[1].Status).To(BeTrue())
+			Expect(response.Status.Conditions[2].Type).To(Equal(server.RuntimeHandlerReadyConditionPrefix + "other"))
+			Expect(response.Status.Conditions[2].Status).To(BeFalse())
+			Expect(response.Status.Conditions[2].Message).To(ContainSubstring("/not/existing"))
 		})
 	})
 })
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cri-o/cri-o/internal/lib"
+	"github.com/cri-o/cri-o/internal/log"
+	"golang.org/x/net/context"
+)
+
+// CheckpointPod checkpoints all running containers of the pod sandbox into a
+// single archive written to location. The containers keep running after the
+// checkpoint has been created.
+func (s *Server) CheckpointPod(ctx context.Context, podSandboxID, location string) error {
+	ctx, span := log.StartSpan(ctx)
+	defer span.End()
+
+	if !s.config.RuntimeConfig.CheckpointRestore() {
+		return errors.New("checkpoint/restore support not available")
+	}
+
+	sb, err := s.getPodSandboxFromRequest(ctx, podSandboxID)
+	if err != nil {
+		return fmt.Errorf("could not find pod %q: %w", podSandboxID, err)
+	}
+
+	log.Infof(ctx, "Checkpointing pod: %s", sb.ID())
+	manifest, err := s.ContainerServer.PodCheckpoint(ctx, sb.ID(), &lib.PodCheckpointOptions{
+		TargetFile: location,
+		// Similar to the container checkpoint, the pod is
+		// kept running after checkpointing it.
+		KeepRunning: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infof(ctx, "Checkpointed pod %s with %d containers to %s", sb.ID(), len(manifest.Containers), location)
+	return nil
+}
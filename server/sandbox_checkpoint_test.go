@@ -0,0 +1,97 @@
+package server_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+var _ = t.Describe("PodCheckpoint", func() {
+	// Prepare the sut
+	BeforeEach(func() {
+		beforeEach()
+		createDummyConfig()
+		mockRuncInLibConfig()
+		serverConfig.SetCheckpointRestore(true)
+		setupSUT()
+	})
+
+	AfterEach(afterEach)
+
+	t.Describe("CheckpointPod", func() {
+		It("should fail with invalid pod id", func() {
+			// Given
+			// When
+			err := sut.CheckpointPod(context.Background(), "invalid", "pod.tar")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail without running containers", func() {
+			// Given
+			addContainerAndSandbox()
+
+			// When
+			err := sut.CheckpointPod(context.Background(), testSandbox.ID(), "pod.tar")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("has no running containers to checkpoint"))
+		})
+
+		It("should not checkpoint the pod via the CRI", func() {
+			// Given
+			addContainerAndSandbox()
+
+			// When
+			_, err := sut.CheckpointContainer(
+				context.Background(),
+				&types.CheckpointContainerRequest{
+					ContainerId: testSandbox.ID(),
+					Location:    "pod.tar",
+				},
+			)
+
+			// Then
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+	})
+})
+
+var _ = t.Describe("PodCheckpoint with CheckpointRestore set to false", func() {
+	// Prepare the sut
+	BeforeEach(func() {
+		beforeEach()
+		serverConfig.SetCheckpointRestore(false)
+		setupSUT()
+	})
+
+	AfterEach(afterEach)
+
+	t.Describe("CheckpointPod", func() {
+		It("should fail with checkpoint/restore support not available", func() {
+			// Given
+			// When
+			err := sut.CheckpointPod(context.Background(), testSandbox.ID(), "pod.tar")
+
+			// Then
+			Expect(err.Error()).To(Equal(`checkpoint/restore support not available`))
+		})
+	})
+
+	t.Describe("RestorePod", func() {
+		It("should fail with checkpoint/restore support not available", func() {
+			// Given
+			// When
+			_, err := sut.RestorePod(context.Background(), "pod.tar", nil)
+
+			// Then
+			Expect(err.Error()).To(Equal(`checkpoint/restore support not available`))
+		})
+	})
+})
@@ -1,13 +1,17 @@
 package server
 
 import (
+	"github.com/cri-o/cri-o/internal/log"
 	"golang.org/x/net/context"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
 // ListPodSandboxMetrics lists all pod sandbox metrics
 func (s *Server) ListPodSandboxMetrics(ctx context.Context, req *types.ListPodSandboxMetricsRequest) (*types.ListPodSandboxMetricsResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method unimplemented")
+	_, span := log.StartSpan(ctx)
+	defer span.End()
+
+	return &types.ListPodSandboxMetricsResponse{
+		PodMetrics: s.ContainerServer.MetricsForSandboxes(s.ContainerServer.ListSandboxes()),
+	}, nil
 }
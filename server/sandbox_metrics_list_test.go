@@ -0,0 +1,52 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// The actual test suite
+var _ = t.Describe("ListPodSandboxMetrics", func() {
+	// Prepare the sut
+	BeforeEach(func() {
+		beforeEach()
+		setupSUT()
+	})
+
+	AfterEach(afterEach)
+
+	t.Describe("ListPodSandboxMetrics", func() {
+		It("should succeed without sandboxes", func() {
+			// When
+			response, err := sut.ListPodSandboxMetrics(context.Background(),
+				&types.ListPodSandboxMetricsRequest{})
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(response).NotTo(BeNil())
+			Expect(response.PodMetrics).To(BeEmpty())
+		})
+
+		It("should succeed", func() {
+			// Given
+			addContainerAndSandbox()
+			storeMock.EXPECT().GraphDriver().Return(nil, errors.New("not implemented"))
+
+			// When
+			response, err := sut.ListPodSandboxMetrics(context.Background(),
+				&types.ListPodSandboxMetricsRequest{})
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(response).NotTo(BeNil())
+			Expect(response.PodMetrics).To(HaveLen(1))
+			Expect(response.PodMetrics[0].PodSandboxId).To(Equal(sandboxID))
+			Expect(response.PodMetrics[0].ContainerMetrics).To(HaveLen(1))
+			Expect(response.PodMetrics[0].ContainerMetrics[0].ContainerId).To(Equal(containerID))
+		})
+	})
+})
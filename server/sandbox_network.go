@@ -4,18 +4,18 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"net"
 	"time"
 
 	cnitypes "github.com/containernetworking/cni/pkg/types"
 	cnicurrent "github.com/containernetworking/cni/pkg/types/100"
+	"github.com/cri-o/cri-o/internal/bandwidth"
 	"github.com/cri-o/cri-o/internal/hostport"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/server/metrics"
 	"github.com/cri-o/ocicni/pkg/ocicni"
 	"k8s.io/apimachinery/pkg/api/resource"
-
-	utilnet "k8s.io/utils/net"
 )
 
 // networkStart sets up the sandbox's network and returns the pod IP on success
@@ -80,47 +80,34 @@ func (s *Server) networkStart(ctx context.Context, sb *sandbox.Sandbox) (podIPs
 		return nil, nil, fmt.Errorf("failed to get network JSON for pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
 	}
 
-	// only do portmapping to the first IP of each IP family
-	foundIPv4 := false
-	foundIPv6 := false
+	if err := s.applyBandwidthLimits(ctx, sb, &podNetwork, network); err != nil {
+		return nil, nil, err
+	}
+
 	// cache the portmapping info
 	sbID := sb.ID()
 	sbName := sb.Name()
 	sbPortMappings := sb.PortMappings()
-	// iterate over each IP and add the portmap if needed
+	podIPNets := make([]net.IPNet, 0, len(network.IPs))
 	for _, podIPConfig := range network.IPs {
-		ip := podIPConfig.Address.IP
-		podIPs = append(podIPs, ip.String())
-
-		// the pod has host-ports defined
-		if len(sbPortMappings) > 0 {
-			mapping := &hostport.PodPortMapping{
-				Name:         sbName,
-				PortMappings: sbPortMappings,
-				IP:           ip,
-				HostNetwork:  false,
-			}
-			// nolint:gocritic // using a switch statement is not much different
-			if utilnet.IsIPv6(ip) {
-				if foundIPv6 {
-					// we have already done the portmap for IPv6
-					continue
-				}
-				// found a new IPv6 address, do the portmap
-				foundIPv6 = true
-			} else if foundIPv4 {
-				// we have already done the portmap for IPv4
-				continue
-			} else {
-				// found a new IPv4 address, do the portmap
-				foundIPv4 = true
-			}
-			err = s.hostportManager.Add(sbID, mapping, "")
-			if err != nil {
-				return nil, nil, fmt.Errorf("failed to add hostport mapping for sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
-			}
+		podIPs = append(podIPs, podIPConfig.Address.IP.String())
+		podIPNets = append(podIPNets, podIPConfig.Address)
+	}
+
+	// the pod has host-ports defined, map them to all pod IPs
+	if len(sbPortMappings) > 0 && len(podIPNets) > 0 {
+		mapping := &hostport.PodPortMapping{
+			Name:         sbName,
+			PortMappings: sbPortMappings,
+			IPs:          podIPNets,
+			HostNetwork:  false,
+		}
+		err = s.hostportManager.Add(sbID, mapping, "")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to add hostport mapping for sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
 		}
 	}
+
 	log.Debugf(ctx, "Found POD IPs: %v", podIPs)
 
 	// metric about the whole network setup operation
@@ -182,6 +169,13 @@ func (s *Server) networkStop(ctx context.Context, sb *sandbox.Sandbox) error {
 			sb.Name(), sb.ID(), err)
 	}
 
+	if limits := sb.BandwidthLimits(); limits != nil {
+		if err := bandwidth.Remove(sb.NetNsPath(), limits); err != nil {
+			log.Warnf(ctx, "Failed to remove bandwidth limits for pod sandbox %s(%s): %v",
+				sb.Name(), sb.ID(), err)
+		}
+	}
+
 	podNetwork, err := s.newPodNetwork(ctx, sb)
 	if err != nil {
 		return err
@@ -189,10 +183,58 @@ func (s *Server) networkStop(ctx context.Context, sb *sandbox.Sandbox) error {
 	if err := s.config.CNIPlugin().TearDownPodWithContext(stopCtx, podNetwork); err != nil {
 		return fmt.Errorf("failed to destroy network for pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
 	}
+	sb.SetBandwidthLimits(nil)
 
 	return sb.SetNetworkStopped(ctx, true)
 }
 
+// applyBandwidthLimits applies the bandwidth limits of the pod network on the
+// pod interface, if the default CNI network does not support the bandwidth
+// capability and would therefore silently ignore them.
+func (s *Server) applyBandwidthLimits(ctx context.Context, sb *sandbox.Sandbox, podNetwork *ocicni.PodNetwork, result *cnicurrent.Result) error {
+	var bwConfig *ocicni.BandwidthConfig
+	for _, runtimeConfig := range podNetwork.RuntimeConfig {
+		if runtimeConfig.Bandwidth != nil {
+			bwConfig = runtimeConfig.Bandwidth
+		}
+	}
+	if bwConfig == nil {
+		return nil
+	}
+
+	supported, err := s.config.CNIManagerDefaultNetworkHasCapability("bandwidth")
+	if err != nil {
+		log.Warnf(ctx, "Unable to detect bandwidth capability of the CNI network, not applying bandwidth limits for pod sandbox %s(%s): %v",
+			sb.Name(), sb.ID(), err)
+		return nil
+	}
+	if supported {
+		return nil
+	}
+
+	limits := &bandwidth.Limits{
+		Interface:    bandwidth.DefaultInterface,
+		IngressRate:  bwConfig.IngressRate,
+		IngressBurst: bwConfig.IngressBurst,
+		EgressRate:   bwConfig.EgressRate,
+		EgressBurst:  bwConfig.EgressBurst,
+	}
+	for _, iface := range result.Interfaces {
+		if iface.Sandbox != "" {
+			limits.Interface = iface.Name
+			break
+		}
+	}
+
+	log.Infof(ctx, "CNI network does not support the bandwidth capability, applying bandwidth limits for pod sandbox %s(%s) on interface %s",
+		sb.Name(), sb.ID(), limits.Interface)
+	if err := bandwidth.Apply(sb.NetNsPath(), limits); err != nil {
+		return fmt.Errorf("failed to apply bandwidth limits for pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
+	}
+	sb.SetBandwidthLimits(limits)
+	return nil
+}
+
 func (s *Server) newPodNetwork(ctx context.Context, sb *sandbox.Sandbox) (ocicni.PodNetwork, error) {
 	_, span := log.StartSpan(ctx)
 	defer span.End()
@@ -241,6 +283,7 @@ func (s *Server) newPodNetwork(ctx context.Context, sb *sandbox.Sandbox) (ocicni
 			network: {
 				Bandwidth:  bwConfig,
 				CgroupPath: sb.CgroupParent(),
+				IpRanges:   s.config.CNIManagerPodIPRanges(),
 			},
 		},
 	}, nil
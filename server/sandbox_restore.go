@@ -0,0 +1,88 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cri-o/cri-o/internal/lib"
+	"github.com/cri-o/cri-o/internal/log"
+	"golang.org/x/net/context"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// RestorePod restores a pod checkpoint archive created by CheckpointPod into a
+// new pod sandbox and returns the ID of the new sandbox. If podMetadata is
+// provided, it replaces the metadata of the checkpointed sandbox, which allows
+// to restore a pod next to the still existing original one.
+func (s *Server) RestorePod(ctx context.Context, input string, podMetadata *types.PodSandboxMetadata) (podSandboxID string, retErr error) {
+	ctx, span := log.StartSpan(ctx)
+	defer span.End()
+
+	if !s.config.RuntimeConfig.CheckpointRestore() {
+		return "", errors.New("checkpoint/restore support not available")
+	}
+
+	// The container checkpoints have to stay available until every
+	// container has been restored by StartContainer.
+	dir, err := os.MkdirTemp("", "pod-restore-")
+	if err != nil {
+		return "", fmt.Errorf("create pod restore directory: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Warnf(ctx, "Unable to remove pod restore directory %s: %v", dir, err)
+		}
+	}()
+
+	manifest, err := lib.ReadPodCheckpointManifest(input, dir)
+	if err != nil {
+		return "", err
+	}
+
+	sbConfig := manifest.Config
+	if podMetadata != nil {
+		sbConfig.Metadata = podMetadata
+	}
+
+	log.Infof(ctx, "Restoring pod %s from %s", manifest.ID, input)
+	resp, err := s.RunPodSandbox(ctx, &types.RunPodSandboxRequest{
+		Config:         sbConfig,
+		RuntimeHandler: manifest.RuntimeHandler,
+	})
+	if err != nil {
+		return "", fmt.Errorf("create sandbox for pod %s: %w", manifest.ID, err)
+	}
+	defer func() {
+		if retErr == nil {
+			return
+		}
+		log.Infof(ctx, "RestorePod: removing sandbox %s", resp.PodSandboxId)
+		if _, err := s.StopPodSandbox(ctx, &types.StopPodSandboxRequest{PodSandboxId: resp.PodSandboxId}); err != nil {
+			log.Warnf(ctx, "Failed to stop sandbox %s: %v", resp.PodSandboxId, err)
+		}
+		if _, err := s.RemovePodSandbox(ctx, &types.RemovePodSandboxRequest{PodSandboxId: resp.PodSandboxId}); err != nil {
+			log.Warnf(ctx, "Failed to remove sandbox %s: %v", resp.PodSandboxId, err)
+		}
+	}()
+
+	for _, ctr := range manifest.Containers {
+		ctrID, err := s.CRImportCheckpoint(ctx, &types.ContainerConfig{
+			Metadata: &types.ContainerMetadata{Name: ctr.Name},
+			Image:    &types.ImageSpec{Image: filepath.Join(dir, ctr.Archive)},
+			Linux:    &types.LinuxContainerConfig{},
+		}, resp.PodSandboxId, sbConfig.Metadata.Uid)
+		if err != nil {
+			return "", fmt.Errorf("import checkpoint of container %s: %w", ctr.ID, err)
+		}
+
+		if _, err := s.StartContainer(ctx, &types.StartContainerRequest{ContainerId: ctrID}); err != nil {
+			return "", fmt.Errorf("restore container %s: %w", ctr.ID, err)
+		}
+		log.Infof(ctx, "Restored container %s of pod %s as %s", ctr.ID, manifest.ID, ctrID)
+	}
+
+	log.Infof(ctx, "Restored pod %s as %s", manifest.ID, resp.PodSandboxId)
+	return resp.PodSandboxId, nil
+}
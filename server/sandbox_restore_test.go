@@ -0,0 +1,47 @@
+package server_test
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = t.Describe("PodRestore", func() {
+	// Prepare the sut
+	BeforeEach(func() {
+		beforeEach()
+		serverConfig.SetCheckpointRestore(true)
+		setupSUT()
+	})
+
+	AfterEach(func() {
+		afterEach()
+		os.RemoveAll("pod.tar")
+	})
+
+	t.Describe("RestorePod", func() {
+		It("should fail with not existing archive", func() {
+			// Given
+			// When
+			id, err := sut.RestorePod(context.Background(), "does-not-exist.tar", nil)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(id).To(BeEmpty())
+		})
+
+		It("should fail with archive without manifest", func() {
+			// Given
+			Expect(os.WriteFile("pod.tar", []byte("not a tar archive"), 0o644)).To(BeNil())
+
+			// When
+			id, err := sut.RestorePod(context.Background(), "pod.tar", nil)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(id).To(BeEmpty())
+		})
+	})
+})
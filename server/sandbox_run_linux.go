@@ -787,6 +787,15 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 		}
 		g.AddAnnotation(annotations.CNIResult, string(cniResultJSON))
 	}
+	if limits := sb.BandwidthLimits(); limits != nil {
+		bandwidthLimitsJSON, err := json.Marshal(limits)
+		if err != nil {
+			return nil, err
+		}
+		g.AddAnnotation(ann.BandwidthLimitsAnnotation, string(bandwidthLimitsJSON))
+	} else {
+		g.RemoveAnnotation(ann.BandwidthLimitsAnnotation)
+	}
 	s.resourceStore.SetStageForResource(ctx, sbox.Name(), "sandbox storage start")
 
 	mountPoint, err := s.StorageRuntimeServer().StartContainer(sbox.ID())
@@ -990,6 +999,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	if err := s.Runtime().StartContainer(ctx, container); err != nil {
 		return nil, err
 	}
+	s.monitorContainerExit(ctx, container)
 	resourceCleaner.Add(ctx, "runSandbox: stopping container "+container.ID(), func() error {
 		// Clean-up steps from RemovePodSandbox
 		if err := s.stopContainer(ctx, container, int64(10)); err != nil {
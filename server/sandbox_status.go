@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/cri-o/cri-o/internal/bandwidth"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/internal/oci"
 	json "github.com/json-iterator/go"
@@ -71,7 +72,7 @@ func (s *Server) PodSandboxStatus(ctx context.Context, req *types.PodSandboxStat
 	}
 
 	if req.Verbose {
-		info, err := createSandboxInfo(sb.InfraContainer())
+		info, err := createSandboxInfo(sb.InfraContainer(), sb.BandwidthLimits())
 		if err != nil {
 			return nil, fmt.Errorf("creating sandbox info: %w", err)
 		}
@@ -88,23 +89,27 @@ func toPodIPs(ips []string) (result []*types.PodIP) {
 	return result
 }
 
-func createSandboxInfo(c *oci.Container) (map[string]string, error) {
+func createSandboxInfo(c *oci.Container, bandwidthLimits *bandwidth.Limits) (map[string]string, error) {
 	var info interface{}
 	if c.Spoofed() {
 		info = struct {
-			RuntimeSpec spec.Spec `json:"runtimeSpec,omitempty"`
+			RuntimeSpec     spec.Spec         `json:"runtimeSpec,omitempty"`
+			BandwidthLimits *bandwidth.Limits `json:"bandwidthLimits,omitempty"`
 		}{
 			c.Spec(),
+			bandwidthLimits,
 		}
 	} else {
 		info = struct {
-			Image       string    `json:"image"`
-			Pid         int       `json:"pid"`
-			RuntimeSpec spec.Spec `json:"runtimeSpec,omitempty"`
+			Image           string            `json:"image"`
+			Pid             int               `json:"pid"`
+			RuntimeSpec     spec.Spec         `json:"runtimeSpec,omitempty"`
+			BandwidthLimits *bandwidth.Limits `json:"bandwidthLimits,omitempty"`
 		}{
 			c.Image(),
 			c.State().Pid,
 			c.Spec(),
+			bandwidthLimits,
 		}
 	}
 	bytes, err := json.Marshal(info)
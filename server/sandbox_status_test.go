@@ -3,6 +3,7 @@ package server_test
 import (
 	"context"
 
+	"github.com/cri-o/cri-o/internal/bandwidth"
 	"github.com/cri-o/cri-o/internal/oci"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -91,5 +92,29 @@ var _ = t.Describe("PodSandboxStatus", func() {
 			Expect(response.Info["info"]).To(ContainSubstring(`"ociVersion":"1.0.0"`))
 			Expect(response.Info["info"]).To(ContainSubstring(`"image":"pauseImage"`))
 		})
+
+		It("should return the bandwidth limits as part of a verbose response", func() {
+			// Given
+			addContainerAndSandbox()
+			testContainer.SetStateAndSpoofPid(&oci.ContainerState{
+				State: specs.State{Status: oci.ContainerStateRunning},
+			})
+			testContainer.SetSpec(&specs.Spec{Version: "1.0.0"})
+			testSandbox.SetBandwidthLimits(&bandwidth.Limits{
+				Interface:   bandwidth.DefaultInterface,
+				IngressRate: 1000000,
+			})
+
+			// When
+			response, err := sut.PodSandboxStatus(context.Background(),
+				&types.PodSandboxStatusRequest{PodSandboxId: testSandbox.ID(), Verbose: true})
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(response).NotTo(BeNil())
+			Expect(response.Info["info"]).To(ContainSubstring(
+				`"bandwidthLimits":{"interface":"eth0","ingressRate":1000000}`,
+			))
+		})
 	})
 })
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cri-o/cri-o/internal/config/seccomp"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
+	json "github.com/json-iterator/go"
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var errSeccompNotRecorded = errors.New("seccomp profile is not recorded")
+
+// recordedSeccompProfile returns the seccomp profile recorded by the notifier
+// of the container.
+func (s *Server) recordedSeccompProfile(c *oci.Container) (*specs.LinuxSeccomp, error) {
+	result, ok := s.seccompNotifiers.Load(c.ID())
+	if !ok {
+		return nil, errSeccompNotRecorded
+	}
+	notifier, ok := result.(*seccomp.Notifier)
+	if !ok || notifier == nil || !notifier.Record() {
+		return nil, errSeccompNotRecorded
+	}
+
+	var base *specs.LinuxSeccomp
+	if spec := c.Spec(); spec.Linux != nil {
+		base = spec.Linux.Seccomp
+	}
+	return notifier.Profile(base), nil
+}
+
+// writeRecordedSeccompProfile writes the recorded seccomp profile of the
+// container into the seccomp_profile_record_dir, if both are available.
+func (s *Server) writeRecordedSeccompProfile(ctx context.Context, c *oci.Container) {
+	dir := s.config.SeccompProfileRecordDir
+	if dir == "" {
+		return
+	}
+	profile, err := s.recordedSeccompProfile(c)
+	if err != nil {
+		return
+	}
+
+	if err := writeSeccompProfile(filepath.Join(dir, c.ID()+".json"), profile); err != nil {
+		log.Errorf(ctx, "Unable to write recorded seccomp profile of container %s: %v", c.ID(), err)
+		return
+	}
+	log.Infof(ctx, "Wrote recorded seccomp profile of container %s to %s", c.ID(), dir)
+}
+
+func writeSeccompProfile(path string, profile *specs.LinuxSeccomp) error {
+	b, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode profile: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
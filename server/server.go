@@ -24,7 +24,10 @@ import (
 	imageTypes "github.com/containers/image/v5/types"
 	"github.com/containers/storage/pkg/idtools"
 	storageTypes "github.com/containers/storage/types"
+	"github.com/cri-o/cri-o/internal/audit"
+	"github.com/cri-o/cri-o/internal/bandwidth"
 	"github.com/cri-o/cri-o/internal/config/seccomp"
+	"github.com/cri-o/cri-o/internal/events"
 	"github.com/cri-o/cri-o/internal/hostport"
 	"github.com/cri-o/cri-o/internal/lib"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
@@ -36,9 +39,11 @@ import (
 	"github.com/cri-o/cri-o/internal/storage"
 	"github.com/cri-o/cri-o/internal/version"
 	libconfig "github.com/cri-o/cri-o/pkg/config"
+	crioTypes "github.com/cri-o/cri-o/pkg/types"
 	"github.com/cri-o/cri-o/server/metrics"
 	"github.com/cri-o/cri-o/utils"
 	"github.com/fsnotify/fsnotify"
+	"github.com/opencontainers/go-digest"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sys/unix"
@@ -53,6 +58,11 @@ const (
 	certRefreshInterval            = time.Minute * 5
 	rootlessEnvName                = "_CRIO_ROOTLESS"
 	irqBalanceConfigRestoreDisable = "disable"
+
+	// registryHealthDirName is the name of the directory inside the run
+	// root, which holds the registries.conf files restricting image pulls to
+	// single pull sources.
+	registryHealthDirName = "crio-pull-sources"
 )
 
 var errSandboxNotCreated = errors.New("sandbox not created")
@@ -68,6 +78,8 @@ type StreamService struct {
 
 // Server implements the RuntimeService and ImageService
 type Server struct {
+	// ctx is done once the server shuts down.
+	ctx             context.Context
 	config          libconfig.Config
 	stream          StreamService
 	hostportManager hostport.HostPortManager
@@ -85,6 +97,15 @@ type Server struct {
 	// pullOperationsLock is used to synchronize pull operations.
 	pullOperationsLock sync.Mutex
 
+	// pullLimiter limits the number of concurrent image pulls.
+	pullLimiter *storage.PullLimiter
+	// registryHealth tracks the health of the registries used for image
+	// pulls.
+	registryHealth *storage.RegistryHealth
+
+	// imageGC removes unused images from the storage.
+	imageGC *storage.ImageGC
+
 	resourceStore *resourcestore.ResourceStore
 
 	seccompNotifierChan chan seccomp.Notification
@@ -92,6 +113,20 @@ type Server struct {
 
 	containerEventClients           sync.Map
 	containerEventStreamBroadcaster sync.Once
+	// containerEventsJournal records the container events for replay, if
+	// enabled.
+	containerEventsJournal *events.Journal
+	// containerEventsLock serializes broadcasting events and registering
+	// new clients.
+	containerEventsLock sync.Mutex
+
+	// configReloadLock serializes the configuration reloads triggered by
+	// SIGHUP and the config reload endpoint.
+	configReloadLock sync.Mutex
+
+	// auditLogger records exec, attach and port forward sessions, if
+	// enabled.
+	auditLogger *audit.Logger
 
 	// NRI runtime interface
 	nri *nriAPI
@@ -107,17 +142,45 @@ type pullArguments struct {
 }
 
 // pullOperation is used to synchronize parallel pull operations via the
-// server's pullCache.  Goroutines can block the pullOperation's waitgroup and
-// be released once the pull operation has finished.
+// server's pullCache.  Goroutines can block on the pullOperation's done channel
+// and be released once the pull operation has finished.
 type pullOperation struct {
-	// wg allows for Goroutines trying to pull the same image to wait until the
-	// currently running pull operation has finished.
-	wg sync.WaitGroup
+	// done is closed once the pull operation has finished, which allows for
+	// Goroutines trying to pull the same image to wait for it.
+	done chan struct{}
 	// imageRef is the reference of the actually pulled image which will differ
 	// from the input if it was a short name (e.g., alpine).
 	imageRef string
 	// err is the error indicating if the pull operation has succeeded or not.
 	err error
+
+	// id identifies the pull operation for inspection and abort requests.
+	id string
+	// startedAt is the time the pull operation has been started.
+	startedAt time.Time
+	// cancel aborts the pull operation for all waiters.
+	cancel context.CancelFunc
+	// callers is the number of callers waiting for the pull operation to
+	// finish. The pull operation is canceled once no caller is left.
+	// Protected by the server's pullOperationsLock.
+	callers int
+	// aborted indicates that the pull operation has been aborted.
+	// Protected by the server's pullOperationsLock.
+	aborted bool
+
+	// progressLock protects layers.
+	progressLock sync.Mutex
+	// layers is the progress of every artifact of the pull operation.
+	layers map[digest.Digest]*pullLayerProgress
+}
+
+// pullLayerProgress is the progress of a single artifact of a pull operation.
+type pullLayerProgress struct {
+	mediaType string
+	size      int64
+	offset    uint64
+	done      bool
+	skipped   bool
 }
 
 type certConfigCache struct {
@@ -299,8 +362,29 @@ func (s *Server) restore(ctx context.Context) []string {
 		}
 	}()
 
-	// Restore sandbox IPs
+	// Watch the exits of the restored running containers
+	restoredContainers, err := s.ContainerServer.ListContainers()
+	if err != nil {
+		log.Warnf(ctx, "Could not list restored containers: %v", err)
+	}
+	for _, sb := range s.ListSandboxes() {
+		if infra := sb.InfraContainer(); infra != nil && !infra.Spoofed() {
+			restoredContainers = append(restoredContainers, infra)
+		}
+	}
+	for _, c := range restoredContainers {
+		if c.State().Status == oci.ContainerStateRunning {
+			s.monitorContainerExit(ctx, c)
+		}
+	}
+
+	// Restore sandbox IPs and bandwidth limits
 	for _, sb := range s.ListSandboxes() {
+		if limits := sb.BandwidthLimits(); limits != nil && !sb.NetworkStopped() {
+			if err := bandwidth.Apply(sb.NetNsPath(), limits); err != nil {
+				log.Warnf(ctx, "Could not restore sandbox bandwidth limits for %v: %v", sb.ID(), err)
+			}
+		}
 		ips, err := s.getSandboxIPs(ctx, sb)
 		if err != nil {
 			log.Warnf(ctx, "Could not restore sandbox IP for %v: %v", sb.ID(), err)
@@ -322,6 +406,9 @@ func (s *Server) restore(ctx context.Context) []string {
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.config.CNIManagerShutdown()
 	s.resourceStore.Close()
+	if err := s.auditLogger.Close(); err != nil {
+		log.Warnf(ctx, "Unable to close audit log: %v", err)
+	}
 
 	if err := s.ContainerServer.Shutdown(); err != nil {
 		return err
@@ -446,6 +533,11 @@ func New(
 		return nil, err
 	}
 
+	auditLogger, err := audit.New(config.AuditConfig())
+	if err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
 	if os.Getenv(rootlessEnvName) == "" {
 		// Not running as rootless, reset XDG_RUNTIME_DIR and DBUS_SESSION_BUS_ADDRESS
 		os.Unsetenv("XDG_RUNTIME_DIR")
@@ -453,6 +545,7 @@ func New(
 	}
 
 	s := &Server{
+		ctx:                      ctx,
 		ContainerServer:          containerServer,
 		hostportManager:          hostportManager,
 		config:                   *config,
@@ -462,11 +555,22 @@ func New(
 		minimumMappableGID:       config.MinimumMappableGID,
 		pullOperationsInProgress: make(map[pullArguments]*pullOperation),
 		resourceStore:            resourcestore.New(),
-	}
+		pullLimiter: storage.NewPullLimiter(storage.PullLimits{
+			Node:      config.MaxConcurrentPulls,
+			Registry:  config.MaxConcurrentPullsPerRegistry,
+			Namespace: config.MaxConcurrentPullsPerNamespace,
+		}),
+		registryHealth: storage.NewRegistryHealth(config.RegistryFailureThreshold, config.RegistryCircuitBreakerCooldown, filepath.Join(config.RunRoot, registryHealthDirName)),
+		auditLogger:    auditLogger,
+	}
+	s.imageGC = storage.NewImageGC(s.StorageImageServer(), &s.config.ImageConfig)
 	if s.config.EnablePodEvents {
 		// creating a container events channel only if the evented pleg is enabled
 		s.ContainerEventsChan = make(chan types.ContainerEventResponse, 1000)
 	}
+	if err := s.openContainerEventsJournal(); err != nil {
+		return nil, err
+	}
 	if err := configureMaxThreads(); err != nil {
 		return nil, err
 	}
@@ -482,6 +586,8 @@ func New(
 		return nil, fmt.Errorf("close stdin: %w", err)
 	}
 
+	s.restorePodCIDRs(ctx)
+
 	deletedImages := s.restore(ctx)
 	s.wipeIfAppropriate(ctx, deletedImages)
 
@@ -554,6 +660,12 @@ func New(
 		logrus.Debug("Metrics are disabled")
 	}
 
+	if s.config.ImageGCEnabled() {
+		s.imageGC.Start(ctx, s.monitorsChan)
+	} else {
+		logrus.Debug("Image garbage collection is disabled")
+	}
+
 	if err := s.startSeccompNotifierWatcher(ctx); err != nil {
 		return nil, fmt.Errorf("start seccomp notifier watcher: %w", err)
 	}
@@ -586,7 +698,7 @@ func (s *Server) startReloadWatcher(ctx context.Context) {
 		for {
 			// Block until the signal is received
 			<-ch
-			if err := s.config.Reload(); err != nil {
+			if _, err := s.reloadConfig(false); err != nil {
 				logrus.Errorf("Unable to reload configuration: %v", err)
 				continue
 			}
@@ -596,6 +708,22 @@ func (s *Server) startReloadWatcher(ctx context.Context) {
 	log.Infof(ctx, "Registered SIGHUP reload watcher")
 }
 
+// reloadConfig reloads the configuration and returns the effective changes,
+// which are not applied if dryRun is true.
+func (s *Server) reloadConfig(dryRun bool) ([]crioTypes.ConfigChange, error) {
+	s.configReloadLock.Lock()
+	defer s.configReloadLock.Unlock()
+	changes, err := s.config.ReloadConfig(dryRun)
+	if err != nil || dryRun {
+		return changes, err
+	}
+
+	// The runtime applies the workloads to the monitor process based on the
+	// configuration of the container server, which has to follow the reload.
+	s.ContainerServer.Config().Workloads = s.config.Workloads
+	return changes, nil
+}
+
 func useDefaultUmask() {
 	const defaultUmask = 0o022
 	oldUmask := unix.Umask(defaultUmask)
@@ -818,11 +946,9 @@ func (s *Server) startSeccompNotifierWatcher(ctx context.Context) error {
 			id := msg.ContainerID()
 			syscall := msg.Syscall()
 
-			log.Infof(ctx, "Got seccomp notifier message for container ID: %s (syscall = %s)", id, syscall)
-
 			result, ok := s.seccompNotifiers.Load(id)
 			if !ok {
-				log.Errorf(ctx, "Unable to get notifier for container ID")
+				log.Errorf(ctx, "Unable to get notifier for container ID: %s", id)
 				continue
 			}
 			notifier, ok := result.(*seccomp.Notifier)
@@ -832,6 +958,14 @@ func (s *Server) startSeccompNotifierWatcher(ctx context.Context) error {
 			}
 			notifier.AddSyscall(syscall)
 
+			if notifier.Record() {
+				// Every allowed syscall is reported in record mode, which is
+				// neither worth an info log nor a metric.
+				continue
+			}
+
+			log.Infof(ctx, "Got seccomp notifier message for container ID: %s (syscall = %s)", id, syscall)
+
 			ctr := s.ContainerServer.GetContainer(ctx, id)
 			usedSyscalls := notifier.UsedSyscalls()
 
@@ -903,7 +1037,37 @@ func (s *Server) handleExit(ctx context.Context, event fsnotify.Event) {
 	if event.Op&fsnotify.Create != fsnotify.Create {
 		return
 	}
-	containerID := filepath.Base(event.Name)
+	if !s.handleContainerExit(ctx, filepath.Base(event.Name)) {
+		return
+	}
+	if err := os.Remove(event.Name); err != nil {
+		log.Warnf(ctx, "Failed to remove exit file: %v", err)
+	}
+}
+
+// monitorContainerExit watches the exit of the running container and handles
+// it as soon as the process is gone, which avoids waiting for the exit file.
+// The exit file is still handled, which serves as fallback if the exit cannot
+// be watched.
+func (s *Server) monitorContainerExit(ctx context.Context, c *oci.Container) {
+	ctx = context.WithoutCancel(ctx)
+	exited := s.Runtime().WatchContainerExit(ctx, c)
+	if exited == nil {
+		return
+	}
+	go func() {
+		<-exited
+		s.handleContainerExit(ctx, c.ID())
+	}()
+}
+
+// handleContainerExit handles the exit of the container or sandbox infra
+// container with the provided ID. Every exit is handled only once, even if it
+// got reported by both the exit notifier and the exit file. It returns false
+// if neither a container nor a sandbox with the ID exists.
+func (s *Server) handleContainerExit(ctx context.Context, containerID string) bool {
+	ctx, span := log.StartSpan(ctx)
+	defer span.End()
 	log.Debugf(ctx, "Container or sandbox exited: %v", containerID)
 	c := s.GetContainer(ctx, containerID)
 	nriCtr := c
@@ -912,7 +1076,7 @@ func (s *Server) handleExit(ctx context.Context, event fsnotify.Event) {
 	if c == nil {
 		sb = s.GetSandbox(containerID)
 		if sb == nil {
-			return
+			return false
 		}
 		c = sb.InfraContainer()
 		resource = "sandbox infra"
@@ -920,12 +1084,17 @@ func (s *Server) handleExit(ctx context.Context, event fsnotify.Event) {
 		sb = s.GetSandbox(c.Sandbox())
 	}
 	log.Debugf(ctx, "%s exited and found: %v", resource, containerID)
+	if !c.SetAsExitHandled() {
+		log.Debugf(ctx, "Exit of %s %s has already been handled", resource, containerID)
+		return true
+	}
 
 	if err := s.ContainerStateToDisk(ctx, c); err != nil {
 		log.Warnf(ctx, "Unable to write %s %s state to disk: %v", resource, c.ID(), err)
 	}
 
 	if nriCtr != nil {
+		s.writeRecordedSeccompProfile(ctx, nriCtr)
 		if err := s.nri.stopContainer(ctx, nil, nriCtr); err != nil {
 			log.Warnf(ctx, "NRI stop container request of %s failed: %v", nriCtr.ID(), err)
 		}
@@ -941,9 +1110,7 @@ func (s *Server) handleExit(ctx context.Context, event fsnotify.Event) {
 	}
 
 	s.generateCRIEvent(ctx, c, types.ContainerEventType_CONTAINER_STOPPED_EVENT)
-	if err := os.Remove(event.Name); err != nil {
-		log.Warnf(ctx, "Failed to remove exit file: %v", err)
-	}
+	return true
 }
 
 func (s *Server) getSandboxStatuses(ctx context.Context, sandboxID string) (*types.PodSandboxStatus, error) {
@@ -7,7 +7,9 @@
 package server
 
 import (
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/ocicni/pkg/ocicni"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
 // SetStorageRuntimeServer sets the runtime server for the ContainerServer
@@ -20,3 +22,9 @@ func (s *StreamService) SetRuntimeServer(server *Server) {
 func (s *Server) SetCNIPlugin(plugin ocicni.CNIPlugin) error {
 	return s.config.SetCNIPlugin(plugin)
 }
+
+// PortForwardProtocol returns the protocol used for forwarding the port into
+// the sandbox.
+func PortForwardProtocol(sb *sandbox.Sandbox, port int32) (types.Protocol, error) {
+	return portForwardProtocol(sb, port)
+}
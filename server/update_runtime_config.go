@@ -2,12 +2,100 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/google/renameio"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
+// podCIDRFileName is the name of the file inside the run root, which persists
+// the pod CIDRs of the node across CRI-O restarts.
+const podCIDRFileName = "crio-pod-cidr"
+
+// UpdateRuntimeConfig updates the runtime configuration based on the given request.
 func (s *Server) UpdateRuntimeConfig(
 	ctx context.Context, req *types.UpdateRuntimeConfigRequest,
 ) (*types.UpdateRuntimeConfigResponse, error) {
+	ctx, span := log.StartSpan(ctx)
+	defer span.End()
+
+	podCIDR := req.GetRuntimeConfig().GetNetworkConfig().GetPodCidr()
+	if podCIDR == "" {
+		return &types.UpdateRuntimeConfigResponse{}, nil
+	}
+
+	cidrs, err := parsePodCIDRs(podCIDR)
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.Equal(s.config.CNIManagerPodCIDRs(), cidrs) {
+		return &types.UpdateRuntimeConfigResponse{}, nil
+	}
+
+	// Persist the pod CIDRs before applying them, so that a failed write gets
+	// retried on the next call instead of being skipped as unchanged.
+	if err := s.persistPodCIDRs(cidrs); err != nil {
+		return nil, fmt.Errorf("persist pod CIDRs: %w", err)
+	}
+
+	s.config.CNIManagerSetPodCIDRs(cidrs)
+	log.Infof(ctx, "Updated pod CIDRs to %s", strings.Join(cidrs, ","))
+
 	return &types.UpdateRuntimeConfigResponse{}, nil
 }
+
+// restorePodCIDRs loads the pod CIDRs persisted by a previous
+// UpdateRuntimeConfig call, so that CNI plugins can rely on them even before
+// the kubelet pushes them again.
+func (s *Server) restorePodCIDRs(ctx context.Context) {
+	content, err := os.ReadFile(s.podCIDRFile())
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Warnf(ctx, "Unable to read persisted pod CIDRs: %v", err)
+		}
+		return
+	}
+
+	cidrs, err := parsePodCIDRs(strings.TrimSpace(string(content)))
+	if err != nil {
+		log.Warnf(ctx, "Ignoring persisted pod CIDRs: %v", err)
+		return
+	}
+
+	s.config.CNIManagerSetPodCIDRs(cidrs)
+	log.Infof(ctx, "Restored pod CIDRs %s", strings.Join(cidrs, ","))
+}
+
+func (s *Server) persistPodCIDRs(cidrs []string) error {
+	file := s.podCIDRFile()
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	return renameio.WriteFile(file, []byte(strings.Join(cidrs, ",")), 0o644)
+}
+
+func (s *Server) podCIDRFile() string {
+	return filepath.Join(s.config.RunRoot, podCIDRFileName)
+}
+
+// parsePodCIDRs validates the comma separated list of CIDRs as provided by the
+// kubelet and returns them in their canonical form.
+func parsePodCIDRs(podCIDR string) ([]string, error) {
+	cidrs := []string{}
+	for _, cidr := range strings.Split(podCIDR, ",") {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid pod CIDR %q: %w", cidr, err)
+		}
+		cidrs = append(cidrs, ipNet.String())
+	}
+	return cidrs, nil
+}
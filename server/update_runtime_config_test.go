@@ -0,0 +1,118 @@
+package server_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/cri-o/cri-o/server"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// The actual test suite
+var _ = t.Describe("UpdateRuntimeConfig", func() {
+	// Prepare the sut
+	BeforeEach(func() {
+		beforeEach()
+		serverConfig.RunRoot = testPath
+
+		// Share the CNI manager between the test and the server
+		cniPluginMock.EXPECT().Status().Return(nil)
+		Expect(serverConfig.SetCNIPlugin(cniPluginMock)).To(BeNil())
+		cniPluginMock.EXPECT().Shutdown().Return(nil)
+		setupSUT()
+	})
+
+	AfterEach(afterEach)
+
+	updateRequest := func(podCIDR string) *types.UpdateRuntimeConfigRequest {
+		return &types.UpdateRuntimeConfigRequest{
+			RuntimeConfig: &types.RuntimeConfig{
+				NetworkConfig: &types.NetworkConfig{PodCidr: podCIDR},
+			},
+		}
+	}
+
+	t.Describe("UpdateRuntimeConfig", func() {
+		It("should succeed without pod CIDR", func() {
+			// Given
+			// When
+			response, err := sut.UpdateRuntimeConfig(context.Background(),
+				&types.UpdateRuntimeConfigRequest{})
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(response).NotTo(BeNil())
+			Expect(serverConfig.CNIManagerPodCIDRs()).To(BeEmpty())
+		})
+
+		It("should succeed with dual stack pod CIDR", func() {
+			// Given
+			// When
+			response, err := sut.UpdateRuntimeConfig(context.Background(),
+				updateRequest("10.88.0.1/16, fd00::/64"))
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(response).NotTo(BeNil())
+			Expect(serverConfig.CNIManagerPodCIDRs()).To(Equal([]string{"10.88.0.0/16", "fd00::/64"}))
+			Expect(serverConfig.CNIManagerPodIPRanges()).To(HaveLen(2))
+			content, err := os.ReadFile(filepath.Join(testPath, "crio-pod-cidr"))
+			Expect(err).To(BeNil())
+			Expect(string(content)).To(Equal("10.88.0.0/16,fd00::/64"))
+		})
+
+		It("should retry persisting the pod CIDR if it failed before", func() {
+			// Given
+			podCIDRFile := filepath.Join(testPath, "crio-pod-cidr")
+			Expect(os.MkdirAll(filepath.Join(podCIDRFile, "dir"), 0o755)).To(Succeed())
+			_, err := sut.UpdateRuntimeConfig(context.Background(),
+				updateRequest("10.88.0.0/16"))
+			Expect(err).NotTo(BeNil())
+			Expect(serverConfig.CNIManagerPodCIDRs()).To(BeEmpty())
+			Expect(os.RemoveAll(podCIDRFile)).To(Succeed())
+
+			// When
+			response, err := sut.UpdateRuntimeConfig(context.Background(),
+				updateRequest("10.88.0.0/16"))
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(response).NotTo(BeNil())
+			Expect(serverConfig.CNIManagerPodCIDRs()).To(Equal([]string{"10.88.0.0/16"}))
+			content, err := os.ReadFile(podCIDRFile)
+			Expect(err).To(BeNil())
+			Expect(string(content)).To(Equal("10.88.0.0/16"))
+		})
+
+		It("should restore the pod CIDR on server creation", func() {
+			// Given
+			_, err := sut.UpdateRuntimeConfig(context.Background(),
+				updateRequest("10.88.0.0/16"))
+			Expect(err).To(BeNil())
+			serverConfig.CNIManagerSetPodCIDRs(nil)
+			mockNewServer()
+
+			// When
+			_, err = server.New(context.Background(), libMock)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(serverConfig.CNIManagerPodCIDRs()).To(Equal([]string{"10.88.0.0/16"}))
+		})
+
+		It("should fail with invalid pod CIDR", func() {
+			// Given
+			// When
+			response, err := sut.UpdateRuntimeConfig(context.Background(),
+				updateRequest("10.88.0.0/16,invalid"))
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(response).To(BeNil())
+			Expect(serverConfig.CNIManagerPodCIDRs()).To(BeEmpty())
+		})
+	})
+})
@@ -30,3 +30,22 @@ load helpers
 	# then
 	[[ "$output" == *"unsupported migration version"* ]]
 }
+
+@test "config migrate subcommand should report changed, renamed and removed options" {
+	# when
+	output=$(crio -c "$TESTDATA/config/config-v1.17.0.toml" -d "" config migrate --from 1.17 --drop-in "$TESTDIR/99-migrated.conf")
+
+	# then
+	[[ "$output" == *'[1.17] changed crio.runtime.log_level: "error" -> "info"'* ]]
+	[[ "$output" == *'[1.27] renamed crio.runtime.conmon_cgroup to crio.runtime.runtimes.runc.monitor_cgroup'* ]]
+	[[ "$output" == *'removed crio.runtime.manage_ns_lifecycle'* ]]
+	grep -q 'log_level = "info"' "$TESTDIR/99-migrated.conf"
+}
+
+@test "config migrate subcommand should fail on invalid version" {
+	# when
+	run -1 crio -c "$TESTDATA/config/config-v1.17.0.toml" -d "" config migrate --from 1.16
+
+	# then
+	[[ "$output" == *"unsupported migration version"* ]]
+}